@@ -0,0 +1,286 @@
+package alert
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"net/http"
+
+	"job_board/helpers"
+	"job_board/models"
+	"job_board/pagination"
+)
+
+func create(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req SavedSearchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	frequency, err := models.ParseAlertFrequency(req.Frequency)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	payPeriod, err := models.ParsePayPeriod(req.PayPeriod)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := createSavedSearch(models.SavedSearch{
+		UserID:           user.ID,
+		Name:             req.Name,
+		Title:            req.Title,
+		Description:      req.Description,
+		CountryID:        req.CountryID,
+		JobTypeID:        req.JobTypeID,
+		LevelID:          req.LevelID,
+		CompanyID:        req.CompanyID,
+		Skills:           pq.StringArray(req.Skills),
+		Salary:           req.Salary,
+		SalaryCurrencyID: req.SalaryCurrencyID,
+		PayPeriod:        payPeriod,
+		Frequency:        frequency,
+		Active:           true,
+	})
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully saved search",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func get(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getSavedSearches(*user, ctx.Query("name"), params)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched saved searches",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+		Links:      resp.Links(ctx),
+	})
+}
+
+func getSingle(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getSingleSavedSearch(ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusNotFound,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched saved search",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func update(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req UpdateSavedSearchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Frequency != "" {
+		frequency, err := models.ParseAlertFrequency(req.Frequency)
+		if err != nil {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    err.Error(),
+				StatusCode: http.StatusBadRequest,
+				Data:       nil,
+			})
+			return
+		}
+		updates["frequency"] = frequency
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	resp, err := updateSavedSearch(ID, *user, updates)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully updated saved search",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func delete(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := deleteSingleSavedSearch(ID, *user); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully deleted saved search",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func unsubscribe(ctx *gin.Context) {
+	resp, err := unsubscribeSavedSearch(ctx.Param("token"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusNotFound,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "You will no longer receive alerts for " + resp.Name,
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
@@ -0,0 +1,32 @@
+package alert
+
+import (
+	"github.com/google/uuid"
+)
+
+type SavedSearchRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Frequency   string     `json:"frequency" binding:"omitempty"`
+	Title       string     `json:"title" binding:"omitempty"`
+	Description string     `json:"description" binding:"omitempty"`
+	CountryID   *uuid.UUID `json:"country_id" binding:"omitempty"`
+	JobTypeID   *uuid.UUID `json:"job_type_id" binding:"omitempty"`
+	LevelID     *uuid.UUID `json:"level_id" binding:"omitempty"`
+	CompanyID   *uuid.UUID `json:"company_id" binding:"omitempty"`
+	Skills      []string   `json:"skills" binding:"omitempty"`
+	// the salary is the least the candidate wants, in this currency and per this period
+	Salary           float64    `json:"salary" binding:"omitempty,gte=0"`
+	SalaryCurrencyID *uuid.UUID `json:"salary_currency_id" binding:"omitempty"`
+	PayPeriod        string     `json:"pay_period" binding:"omitempty"`
+}
+
+type UpdateSavedSearchRequest struct {
+	Name      string `json:"name" binding:"omitempty"`
+	Frequency string `json:"frequency" binding:"omitempty"`
+	Active    *bool  `json:"active" binding:"omitempty"`
+}
+
+type DigestJob struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+}
@@ -0,0 +1,22 @@
+package alert
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"job_board/jwt"
+	"job_board/middleware"
+	"job_board/models"
+)
+
+func AlertRoutes(superRoute *gin.RouterGroup) {
+	alertRouter := superRoute.Group("/alerts")
+
+	// unsubscribe links are opened from emails so they can't carry a jwt
+	alertRouter.GET("/unsubscribe/:token", unsubscribe)
+
+	alertRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.AlertManageOwn), create)
+	alertRouter.GET("/", jwt.Middleware(), middleware.PermissionMiddleware(models.AlertManageOwn), get)
+	alertRouter.GET("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.AlertManageOwn), getSingle)
+	alertRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.AlertManageOwn), update)
+	alertRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.AlertManageOwn), delete)
+}
@@ -0,0 +1,153 @@
+package alert
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"job_board/models"
+	"job_board/pagination"
+)
+
+var database *gorm.DB
+
+var appURL string
+
+// Setup gives the package its database handle and the url unsubscribe links point at
+func Setup(db *gorm.DB, url string) {
+	database = db
+	appURL = url
+}
+
+func generateUnsubscribeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func createSavedSearch(search models.SavedSearch) (*models.SavedSearch, error) {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	token, err := generateUnsubscribeToken()
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error generating unsubscribe token: %w", err)
+	}
+	search.UnsubscribeToken = token
+
+	if err := tx.Create(&search).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error creating a new saved search: %w", err)
+	}
+
+	// Commit the transaction
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return &search, nil
+}
+
+func getSavedSearches(user models.User, name string, params pagination.Params) (*pagination.Page[models.SavedSearch], error) {
+	// Initialize database model with filtering conditions
+	db := database.Model(&models.SavedSearch{}).Where("user_id = ?", user.ID)
+
+	if name != "" {
+		db = db.Where("name ILIKE ?", "%"+name+"%")
+	}
+
+	data, err := pagination.Find[models.SavedSearch](db, params)
+	if err != nil {
+		log.Println("Error finding saved searches:", err)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func getSingleSavedSearch(ID uuid.UUID, user models.User) (*models.SavedSearch, error) {
+	var record models.SavedSearch
+	if err := database.First(&record, "id = ? AND user_id = ?", ID, user.ID).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func updateSavedSearch(ID uuid.UUID, user models.User, updates map[string]interface{}) (*models.SavedSearch, error) {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var existingRecord models.SavedSearch
+	if err := tx.First(&existingRecord, "id = ? AND user_id = ?", ID, user.ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err // Record not found or other database error
+	}
+
+	if err := tx.Model(&existingRecord).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		return nil, err // Error updating the record
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return &existingRecord, nil
+}
+
+func deleteSingleSavedSearch(ID uuid.UUID, user models.User) error {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result := tx.Delete(&models.SavedSearch{}, "id = ? AND user_id = ?", ID, user.ID)
+	if result.Error != nil {
+		// Rollback the transaction if an error occurs
+		tx.Rollback()
+		return result.Error
+	}
+
+	// Check if the record was not found
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return fmt.Errorf("record with ID %s not found", ID)
+	}
+
+	if err := tx.Where("saved_search_id = ?", ID).Delete(&models.SavedSearchMatch{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error deleting pending matches: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func unsubscribeSavedSearch(token string) (*models.SavedSearch, error) {
+	var record models.SavedSearch
+	if err := database.First(&record, "unsubscribe_token = ?", token).Error; err != nil {
+		return nil, fmt.Errorf("invalid unsubscribe link")
+	}
+	if err := database.Model(&record).Update("active", false).Error; err != nil {
+		return nil, fmt.Errorf("error unsubscribing: %w", err)
+	}
+	return &record, nil
+}
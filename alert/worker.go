@@ -0,0 +1,166 @@
+package alert
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	"job_board/models"
+	"job_board/notifications"
+)
+
+// digestInterval is how often due digests are looked for, the frequency of each
+// saved search decides whether it actually goes out
+const digestInterval = 15 * time.Minute
+
+var jobQueue = make(chan uuid.UUID, 100)
+
+// QueueJob hands a freshly committed job to the matcher, it never blocks the request
+func QueueJob(jobID uuid.UUID) {
+	select {
+	case jobQueue <- jobID:
+	default:
+		go func() { jobQueue <- jobID }()
+	}
+}
+
+// Start runs the matcher and the digest sender in the background
+func Start() {
+	go func() {
+		for jobID := range jobQueue {
+			if err := matchJob(jobID); err != nil {
+				log.Printf("Failed to match job %s against saved searches: %v", jobID, err)
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(digestInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			sendDigests()
+		}
+	}()
+}
+
+// matchJob stores a pending match for every active saved search the job satisfies,
+// the conditions mirror the filters of job.getJob, the saved salary is the least the
+// candidate wants so it has to fit under the top of the job range
+func matchJob(jobID uuid.UUID) error {
+	var job models.Job
+	if err := database.First(&job, "id = ?", jobID).Error; err != nil {
+		return err
+	}
+	if !job.IsLive() {
+		return nil
+	}
+
+	var searches []models.SavedSearch
+	if err := database.
+		Where("active = ?", true).
+		// strpos takes the saved text literally, as a LIKE pattern a saved % or _ would match anything
+		Where("title = '' OR strpos(lower(?), lower(title)) > 0", job.Title).
+		Where("description = '' OR strpos(lower(?), lower(description)) > 0", job.Description).
+		Where("country_id IS NULL OR country_id = ?", job.CountryID).
+		Where("job_type_id IS NULL OR job_type_id = ?", job.JobTypeID).
+		Where("level_id IS NULL OR level_id = ?", job.LevelID).
+		Where("company_id IS NULL OR company_id = ?", job.CompanyID).
+		Where("skills IS NULL OR cardinality(skills) = 0 OR skills && ?", job.Skills).
+		Where("salary = 0 OR (? AND ? >= "+models.AnnualBaseSQL("saved_searches.salary", "saved_searches.pay_period", "saved_searches.salary_currency_id")+")",
+			!job.SalaryHidden, job.SalaryMaxBase).
+		Find(&searches).Error; err != nil {
+		return err
+	}
+	if len(searches) == 0 {
+		return nil
+	}
+
+	matches := make([]models.SavedSearchMatch, 0, len(searches))
+	for _, search := range searches {
+		matches = append(matches, models.SavedSearchMatch{SavedSearchID: search.ID, JobID: job.ID})
+	}
+	return database.Clauses(clause.OnConflict{DoNothing: true}).Create(&matches).Error
+}
+
+func sendDigests() {
+	var searches []models.SavedSearch
+	if err := database.
+		Preload("User").
+		Where("active = ?", true).
+		Where("EXISTS (SELECT 1 FROM saved_search_matches WHERE saved_search_matches.saved_search_id = saved_searches.id AND saved_search_matches.sent_at IS NULL)").
+		Find(&searches).Error; err != nil {
+		log.Printf("Failed to load saved searches for digests: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, search := range searches {
+		last := search.CreatedAt
+		if search.LastSentAt != nil {
+			last = *search.LastSentAt
+		}
+		if now.Sub(last) < search.Frequency.Interval() {
+			continue
+		}
+		if err := sendDigest(search, now); err != nil {
+			log.Printf("Failed to send digest for saved search %s: %v", search.ID, err)
+		}
+	}
+}
+
+func sendDigest(search models.SavedSearch, now time.Time) error {
+	var matches []models.SavedSearchMatch
+	if err := database.
+		Preload("Job").
+		Where("saved_search_id = ? AND sent_at IS NULL", search.ID).
+		Order("created_at").
+		Find(&matches).Error; err != nil {
+		return err
+	}
+
+	jobs := make([]DigestJob, 0, len(matches))
+	ids := make([]uuid.UUID, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+		// jobs deleted or taken down since they matched are dropped from the digest
+		if match.Job.ID == uuid.Nil || !match.Job.IsLive() {
+			continue
+		}
+		jobs = append(jobs, DigestJob{ID: match.Job.ID, Title: match.Job.Title})
+	}
+
+	tx := database.Begin()
+	if len(jobs) > 0 {
+		notification := notifications.Trigger{
+			EventID: "job-alert-digest",
+			To: map[string]interface{}{
+				"subscriberId": search.User.SubscriberID,
+				"email":        search.User.Email,
+			},
+			Data: map[string]interface{}{
+				"companyName":    "Jobby",
+				"searchName":     search.Name,
+				"frequency":      search.Frequency,
+				"jobs":           jobs,
+				"unsubscribeUrl": appURL + "/api/v1/alerts/unsubscribe/" + search.UnsubscribeToken,
+			},
+		}
+		if err := notifications.Enqueue(tx, notification); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if len(ids) > 0 {
+		if err := tx.Model(&models.SavedSearchMatch{}).Where("id IN ?", ids).Update("sent_at", now).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Model(&search).UpdateColumn("last_sent_at", now).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
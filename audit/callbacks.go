@@ -0,0 +1,343 @@
+package audit
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"job_board/models"
+)
+
+// a statement touching more rows than this only has the first ones logged
+const maxRows = 500
+
+const beforeKey = "audit:before"
+
+// tracked maps the audited tables to the resource type their entries are filed under
+var tracked = map[string]string{
+	"users":            "user",
+	"companies":        "company",
+	"company_members":  "company_member",
+	"jobs":             "job",
+	"job_applications": "application",
+}
+
+// columns that are logged as changed without their value
+var redacted = []string{"password", "secret", "token", "hash", "recovery_codes", "otp"}
+
+var database *gorm.DB
+
+// Setup gives the package its database handle and starts auditing the changes made through it
+func Setup(db *gorm.DB) error {
+	database = db
+	return Register(db)
+}
+
+// Register hooks the audit trail into every create, update and delete made through the
+// handle. The entries are written in the same transaction as the change so a rolled back
+// change leaves no entry, and a failed entry fails the change
+func Register(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Create().Before("gorm:commit_or_rollback_transaction").Register("audit:after_create", afterCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("audit:before_update", capture); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:commit_or_rollback_transaction").Register("audit:after_update", afterUpdate); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("audit:before_delete", capture); err != nil {
+		return err
+	}
+	return callback.Delete().Before("gorm:commit_or_rollback_transaction").Register("audit:after_delete", afterDelete)
+}
+
+func resourceType(tx *gorm.DB) (string, bool) {
+	resource, ok := tracked[tx.Statement.Table]
+	return resource, ok
+}
+
+func primaryKey(stmt *gorm.Statement) string {
+	if stmt.Schema != nil && stmt.Schema.PrioritizedPrimaryField != nil {
+		return stmt.Schema.PrioritizedPrimaryField.DBName
+	}
+	return "id"
+}
+
+// primaryKeys are the ids set on the statement's model, gorm adds them to the where clause
+// of updates and deletes itself so they aren't in the clauses yet
+func primaryKeys(stmt *gorm.Statement) []interface{} {
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil || !stmt.ReflectValue.IsValid() {
+		return nil
+	}
+	field := stmt.Schema.PrioritizedPrimaryField
+	var ids []interface{}
+	add := func(value reflect.Value) {
+		value = reflect.Indirect(value)
+		if value.Kind() != reflect.Struct || value.Type() != stmt.Schema.ModelType {
+			return
+		}
+		if id, zero := field.ValueOf(stmt.Context, value); !zero {
+			ids = append(ids, id)
+		}
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			add(stmt.ReflectValue.Index(i))
+		}
+	case reflect.Struct:
+		add(stmt.ReflectValue)
+	}
+	return ids
+}
+
+// snapshot reads the rows as they are right now, inside the statement's transaction
+func snapshot(tx *gorm.DB, build func(query *gorm.DB) *gorm.DB) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	query := tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Unscoped()
+	if schema := tx.Statement.Schema; schema != nil {
+		// the model lets conditions on the primary key like Delete(&User{}, id) resolve
+		query = query.Model(reflect.New(schema.ModelType).Interface())
+	}
+	query = query.Table(tx.Statement.Table)
+	if err := build(query).Limit(maxRows).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for column, value := range row {
+			row[column] = normalize(value)
+		}
+	}
+	return rows, nil
+}
+
+func byIDs(tx *gorm.DB, ids []interface{}) ([]map[string]interface{}, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return snapshot(tx, func(query *gorm.DB) *gorm.DB {
+		return query.Where(clause.IN{Column: clause.Column{Name: primaryKey(tx.Statement)}, Values: ids})
+	})
+}
+
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case [16]byte:
+		return uuid.UUID(v).String()
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC()
+	}
+	return value
+}
+
+func isRedacted(column string) bool {
+	for _, word := range redacted {
+		if strings.Contains(column, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func values(row map[string]interface{}) models.AuditValues {
+	out := models.AuditValues{}
+	for column, value := range row {
+		if isRedacted(column) && value != nil {
+			value = "[redacted]"
+		}
+		out[column] = value
+	}
+	return out
+}
+
+// diff keeps the columns that changed, updated_at always does and is left out
+func diff(before, after map[string]interface{}) (models.AuditValues, models.AuditValues) {
+	from, to := map[string]interface{}{}, map[string]interface{}{}
+	for column, value := range after {
+		if column == "updated_at" || reflect.DeepEqual(before[column], value) {
+			continue
+		}
+		from[column], to[column] = before[column], value
+	}
+	if len(to) == 0 {
+		return nil, nil
+	}
+	return values(from), values(to)
+}
+
+func idOf(tx *gorm.DB, row map[string]interface{}) (interface{}, string) {
+	id := row[primaryKey(tx.Statement)]
+	return id, fmt.Sprint(id)
+}
+
+func entry(tx *gorm.DB, action models.AuditAction, resource string, resourceID string, before, after models.AuditValues) models.AuditLog {
+	request := FromContext(tx.Statement.Context)
+	return models.AuditLog{
+		ActorID:      request.ActorID,
+		ActorRole:    request.ActorRole,
+		Action:       action,
+		ResourceType: resource,
+		ResourceID:   resourceID,
+		Before:       before,
+		After:        after,
+		IP:           request.IP,
+		UserAgent:    request.UserAgent,
+		Method:       request.Method,
+		Path:         request.Path,
+		RequestID:    request.RequestID,
+	}
+}
+
+func write(tx *gorm.DB, entries []models.AuditLog) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&entries).Error; err != nil {
+		tx.AddError(fmt.Errorf("error writing audit log: %w", err))
+	}
+}
+
+// capture keeps the rows an update or delete is about to touch
+func capture(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.DryRun {
+		return
+	}
+	if _, ok := resourceType(tx); !ok {
+		return
+	}
+
+	var where []clause.Expression
+	if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+		if conditions, ok := c.Expression.(clause.Where); ok && len(conditions.Exprs) > 0 {
+			where = append(where, clause.Where{Exprs: conditions.Exprs})
+		}
+	}
+	ids := primaryKeys(tx.Statement)
+	if len(where) == 0 && len(ids) == 0 {
+		// gorm refuses updates and deletes without conditions anyway
+		return
+	}
+
+	rows, err := snapshot(tx, func(query *gorm.DB) *gorm.DB {
+		if len(ids) > 0 {
+			query = query.Where(clause.IN{Column: clause.Column{Name: primaryKey(tx.Statement)}, Values: ids})
+		}
+		return query.Clauses(where...)
+	})
+	if err != nil {
+		tx.AddError(fmt.Errorf("error reading rows for the audit log: %w", err))
+		return
+	}
+	tx.InstanceSet(beforeKey, rows)
+}
+
+func captured(tx *gorm.DB) []map[string]interface{} {
+	value, ok := tx.InstanceGet(beforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := value.([]map[string]interface{})
+	return rows
+}
+
+func afterCreate(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.DryRun || tx.RowsAffected == 0 {
+		return
+	}
+	resource, ok := resourceType(tx)
+	if !ok {
+		return
+	}
+
+	rows, err := byIDs(tx, primaryKeys(tx.Statement))
+	if err != nil {
+		tx.AddError(fmt.Errorf("error reading rows for the audit log: %w", err))
+		return
+	}
+	entries := make([]models.AuditLog, 0, len(rows))
+	for _, row := range rows {
+		_, id := idOf(tx, row)
+		entries = append(entries, entry(tx, models.AuditCreate, resource, id, nil, values(row)))
+	}
+	write(tx, entries)
+}
+
+func afterUpdate(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.DryRun || tx.RowsAffected == 0 {
+		return
+	}
+	resource, ok := resourceType(tx)
+	if !ok {
+		return
+	}
+	before := captured(tx)
+	if len(before) == 0 {
+		return
+	}
+
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		id, _ := idOf(tx, row)
+		ids = append(ids, id)
+	}
+	after, err := byIDs(tx, ids)
+	if err != nil {
+		tx.AddError(fmt.Errorf("error reading rows for the audit log: %w", err))
+		return
+	}
+	current := make(map[string]map[string]interface{}, len(after))
+	for _, row := range after {
+		_, id := idOf(tx, row)
+		current[id] = row
+	}
+
+	entries := make([]models.AuditLog, 0, len(before))
+	for _, row := range before {
+		_, id := idOf(tx, row)
+		from, to := diff(row, current[id])
+		if to == nil {
+			continue
+		}
+		action := models.AuditUpdate
+		if row["deleted_at"] != nil && current[id]["deleted_at"] == nil {
+			action = models.AuditReinstate
+		}
+		entries = append(entries, entry(tx, action, resource, id, from, to))
+	}
+	write(tx, entries)
+}
+
+func afterDelete(tx *gorm.DB) {
+	if tx.Error != nil || tx.Statement.DryRun || tx.RowsAffected == 0 {
+		return
+	}
+	resource, ok := resourceType(tx)
+	if !ok {
+		return
+	}
+
+	action := models.AuditDelete
+	if tx.Statement.Unscoped || tx.Statement.Schema == nil || tx.Statement.Schema.LookUpField("DeletedAt") == nil {
+		action = models.AuditPurge
+	}
+	before := captured(tx)
+	entries := make([]models.AuditLog, 0, len(before))
+	for _, row := range before {
+		if action == models.AuditDelete && row["deleted_at"] != nil {
+			// already soft deleted, the statement skipped it
+			continue
+		}
+		_, id := idOf(tx, row)
+		entries = append(entries, entry(tx, action, resource, id, values(row), nil))
+	}
+	write(tx, entries)
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"job_board/models"
+)
+
+type contextKey struct{}
+
+// Request is who made a change and from where, it rides on the context handed to gorm
+type Request struct {
+	ActorID   *uuid.UUID
+	ActorRole models.RoleAllowed
+	IP        string
+	UserAgent string
+	Method    string
+	Path      string
+	RequestID string
+}
+
+func FromContext(ctx context.Context) Request {
+	if ctx == nil {
+		return Request{}
+	}
+	request, _ := ctx.Value(contextKey{}).(Request)
+	return request
+}
+
+func WithRequest(ctx context.Context, request Request) context.Context {
+	return context.WithValue(ctx, contextKey{}, request)
+}
+
+// WithActor marks the user as the one making the changes, the jwt middleware calls it once
+// the user is known
+func WithActor(ctx context.Context, user models.User) context.Context {
+	request := FromContext(ctx)
+	id := user.ID
+	request.ActorID = &id
+	request.ActorRole = user.RoleName
+	return WithRequest(ctx, request)
+}
+
+// Middleware puts the request metadata on the request context, services pass
+// ctx.Request.Context() to gorm for it to end up in the audit log
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > 64 {
+			requestID = uuid.NewString()
+		}
+		c.Header("X-Request-ID", requestID)
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		c.Request = c.Request.WithContext(WithRequest(c.Request.Context(), Request{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			Method:    c.Request.Method,
+			Path:      path,
+			RequestID: requestID,
+		}))
+		c.Next()
+	}
+}
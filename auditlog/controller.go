@@ -0,0 +1,121 @@
+package auditlog
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"job_board/helpers"
+	"job_board/models"
+	"job_board/pagination"
+)
+
+// parseDate takes a full timestamp or a plain date, which means the start of that day
+func parseDate(name string, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return &date, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date like 2006-01-02 or an RFC3339 timestamp", name)
+	}
+	return &date, nil
+}
+
+func parseFilter(ctx *gin.Context) (Filter, error) {
+	filter := Filter{
+		ResourceType: ctx.Query("resource_type"),
+		ResourceID:   ctx.Query("resource_id"),
+		Action:       models.AuditAction(ctx.Query("action")),
+		RequestID:    ctx.Query("request_id"),
+	}
+	var err error
+	if id := ctx.Query("actor_id"); id != "" {
+		if filter.ActorID, err = uuid.Parse(id); err != nil {
+			return filter, err
+		}
+	}
+	if filter.From, err = parseDate("from", ctx.Query("from")); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseDate("to", ctx.Query("to")); err != nil {
+		return filter, err
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return filter, fmt.Errorf("to must come after from")
+	}
+	return filter, nil
+}
+
+func get(ctx *gin.Context) {
+	filter, err := parseFilter(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	params, err := pagination.FromContext(ctx, auditSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getLogs(filter, params)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched audit logs",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+		Links:      resp.Links(ctx),
+	})
+}
+
+func getSingle(ctx *gin.Context) {
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getSingleLog(ID)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusNotFound,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched audit log",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
@@ -0,0 +1,27 @@
+package auditlog
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"job_board/models"
+	"job_board/pagination"
+)
+
+var auditSorts = pagination.Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at": "created_at",
+	},
+}
+
+type Filter struct {
+	ActorID      uuid.UUID
+	ResourceType string
+	ResourceID   string
+	Action       models.AuditAction
+	RequestID    string
+	From         *time.Time
+	To           *time.Time
+}
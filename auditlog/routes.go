@@ -0,0 +1,17 @@
+package auditlog
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"job_board/jwt"
+	"job_board/middleware"
+	"job_board/models"
+)
+
+func AuditLogRoutes(superRoute *gin.RouterGroup) {
+	auditLogRouter := superRoute.Group("/audit-logs")
+
+	auditLogRouter.Use(jwt.Middleware(), middleware.PermissionMiddleware(models.AuditRead))
+	auditLogRouter.GET("/", get)
+	auditLogRouter.GET("/:id", getSingle)
+}
@@ -0,0 +1,57 @@
+package auditlog
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"job_board/models"
+	"job_board/pagination"
+)
+
+var database *gorm.DB
+
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
+}
+
+func getLogs(filter Filter, params pagination.Params) (*pagination.Page[models.AuditLog], error) {
+	db := database.Model(&models.AuditLog{})
+	if filter.ActorID != uuid.Nil {
+		db = db.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.ResourceType != "" {
+		db = db.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		db = db.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.Action != "" {
+		db = db.Where("action = ?", filter.Action)
+	}
+	if filter.RequestID != "" {
+		db = db.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("created_at < ?", *filter.To)
+	}
+
+	data, err := pagination.Find[models.AuditLog](db, params)
+	if err != nil {
+		log.Println("Error finding audit logs:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func getSingleLog(ID uuid.UUID) (*models.AuditLog, error) {
+	var data models.AuditLog
+	if err := database.First(&data, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
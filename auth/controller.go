@@ -3,22 +3,17 @@ package auth
 import (
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
-	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/oauth2"
-	"gorm.io/gorm"
 
 	"job_board/helpers"
 	"job_board/jwt"
 	"job_board/models"
-	"job_board/notifications"
 	"job_board/user"
 )
 
@@ -88,7 +83,7 @@ func Callback(auth *Authenticator) gin.HandlerFunc {
 			return
 		}
 
-		audience := "https://" + os.Getenv("AUTH0_DOMAIN") + "/"
+		audience := "https://" + settings.Auth0.Domain + "/"
 		// Exchange an authorization code for a token.
 		token, err := auth.Exchange(ctx.Request.Context(), ctx.Query("code"), oauth2.SetAuthURLParam("audience", audience))
 		if err != nil {
@@ -163,7 +158,7 @@ func Authorize(ctx *gin.Context) {
 		})
 		return
 	}
-	profile, isNew, err := handleUser(subject, session)
+	profile, _, err := handleUser(subject, session)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -172,10 +167,7 @@ func Authorize(ctx *gin.Context) {
 		})
 		return
 	}
-	fmt.Println("got here")
-	userAgent := ctx.Request.Header.Get("User-Agent")
-	isMobile := strings.Contains(userAgent, "Android") || strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPad")
-	token, err := jwt.GenerateJWT(profile.ProviderID, isMobile)
+	tokens, err := jwt.StartSession(profile.ProviderID, ctx.Request.UserAgent(), ctx.ClientIP())
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -187,49 +179,13 @@ func Authorize(ctx *gin.Context) {
 	//save to db and if user don't exist redirect to token
 	ctx.JSON(http.StatusOK, gin.H{
 		"data": gin.H{
-			"profile":      profile,
-			"subject":      subject,
-			"access_token": token,
+			"profile":       profile,
+			"subject":       subject,
+			"access_token":  tokens.AccessToken,
+			"refresh_token": tokens.RefreshToken,
+			"expires_in":    tokens.ExpiresIn,
 		},
 	})
-
-	go func() {
-		if isNew {
-			log.Print("Try creating subscriber ")
-			subscriber := notifications.Subscriber{
-				SubscriberID: profile.SubscriberID,
-				Name:         profile.Name,
-				Email:        profile.Email,
-				Avatar:       profile.Picture,
-				Data:         map[string]interface{}{},
-			}
-
-			if _, err := notifications.CreateSubscriber(subscriber); err != nil {
-				log.Printf("Failed to create subscriber: %v", err)
-				return
-			}
-			log.Printf("finished creating subscriber ID: %v", subscriber.SubscriberID)
-			if profile.Email != "" {
-				log.Print("Send notification ")
-				notification := notifications.Trigger{
-					Name:         profile.Name,
-					Email:        profile.Email,
-					Title:        "Welcome to Jobby",
-					SubscriberID: profile.SubscriberID,
-					EventID:      "welcome",
-					Logo:         "https://via.placeholder.com/200x200",
-				}
-
-				if _, err := notifications.SendNotification(notification); err != nil {
-					log.Printf("Failed to send notification: %v", err)
-					return
-				}
-				log.Print("Successfully sent welcome notification")
-			} else {
-				log.Print("No email found will be skipping")
-			}
-		}
-	}()
 }
 
 func LoginAdmin(ctx *gin.Context) {
@@ -249,7 +205,7 @@ func LoginAdmin(ctx *gin.Context) {
 
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
-			Message:    err.Error(),
+			Message:    "Invalid credentials",
 			StatusCode: http.StatusBadRequest,
 			Data:       nil,
 		})
@@ -264,8 +220,23 @@ func LoginAdmin(ctx *gin.Context) {
 		return
 	}
 
+	// a locked account answers the same whether or not the password is right
+	if locked, err := lockedOut(newuser.ID); err != nil || locked {
+		status, message := http.StatusTooManyRequests, errTooManyAttempts.Error()
+		if err != nil {
+			status, message = http.StatusInternalServerError, err.Error()
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    message,
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
 	isMatch := helpers.CheckPasswordHash(req.Password, newuser.Password)
 	if !isMatch {
+		recordFailure(newuser.ID)
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    "Invalid credentials",
 			StatusCode: http.StatusBadRequest,
@@ -273,49 +244,33 @@ func LoginAdmin(ctx *gin.Context) {
 		})
 		return
 	}
-	helpers.CreateResponse(ctx, helpers.Response{
-		Message:    "Successfully sent OTP",
-		StatusCode: http.StatusOK,
-		Data:       nil,
-	})
-	go func() {
-		otp := GenerateOtp(4)
-		expiryTime := time.Now().Add(24 * time.Hour) //  24 hours
 
-		_, err = user.UpdateSingleUser(newuser.ID, models.User{
-			VerificationToken: otp,
-			ExpiresAt:         expiryTime,
-		})
-		if err != nil {
-			log.Println("Failed to update user:", err)
-			return
-		}
-
-		log.Print("Send otp notification ")
-		notification := notifications.Trigger{
-			Name:         newuser.Name,
-			Email:        newuser.Email,
-			Title:        "You have the power",
-			SubscriberID: newuser.SubscriberID,
-			EventID:      "otp",
-			Logo:         "https://via.placeholder.com/200x200",
-			To: map[string]interface{}{
-				"subscriberId": newuser.SubscriberID,
-				"email":        newuser.Email,
-			},
-			Data: map[string]interface{}{
-				"companyName": "Jobby",
-				"otp":         otp,
-			},
-		}
-
-		if _, err := notifications.SendNotification(notification); err != nil {
-			log.Printf("Failed to send  otp notification: %v", err)
-			return
+	challenge, method, err := startAdminChallenge(newuser, req.Method)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errTooManyAttempts) {
+			status = http.StatusTooManyRequests
 		}
-		log.Printf("Send otp notification: %v ", otp)
-	}()
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
 
+	message := "Enter the code from your authenticator app"
+	if method == "email" {
+		message = "Successfully sent OTP"
+	}
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    message,
+		StatusCode: http.StatusOK,
+		Data: gin.H{
+			"challenge": challenge,
+			"method":    method,
+		},
+	})
 }
 
 func ConfirmLoginAdmin(ctx *gin.Context) {
@@ -328,27 +283,23 @@ func ConfirmLoginAdmin(ctx *gin.Context) {
 		})
 		return
 	}
-	dbUser, err := user.GetVerificationToken(req.Otp)
+	dbUser, err := confirmAdminChallenge(req.Challenge, req.Otp)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			helpers.CreateResponse(ctx, helpers.Response{
-				Message:    "Invalid otp try login again.",
-				StatusCode: http.StatusBadRequest,
-				Data:       nil,
-			})
-			return
+		status := http.StatusBadRequest
+		if errors.Is(err, errTooManyAttempts) {
+			status = http.StatusTooManyRequests
+		} else if !errors.Is(err, errInvalidCode) && !errors.Is(err, errLoginExpired) {
+			status = http.StatusInternalServerError
 		}
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
-			StatusCode: http.StatusBadRequest,
+			StatusCode: status,
 			Data:       nil,
 		})
 		return
 	}
-	fmt.Println("GOT HERE", dbUser.ProviderID)
-	userAgent := ctx.Request.Header.Get("User-Agent")
-	isMobile := strings.Contains(userAgent, "Android") || strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPad")
-	token, err := jwt.GenerateJWT(dbUser.ProviderID, isMobile)
+
+	tokens, err := jwt.StartSession(dbUser.ProviderID, ctx.Request.UserAgent(), ctx.ClientIP())
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -360,19 +311,8 @@ func ConfirmLoginAdmin(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "Successfully loggedIn",
 		StatusCode: http.StatusOK,
-		Data:       token,
+		Data:       tokens,
 	})
-
-	go func() {
-		_, err = user.UpdateSingleUser(dbUser.ID, models.User{
-			VerificationToken: "nil",
-			ExpiresAt:         time.Time{},
-		})
-		if err != nil {
-			log.Println("Failed to update user:", err)
-			return
-		}
-	}()
 }
 
 func Protect(ctx *gin.Context) {
@@ -382,7 +322,7 @@ func Protect(ctx *gin.Context) {
 }
 
 func Logout(ctx *gin.Context) {
-	logoutUrl, err := url.Parse("https://" + os.Getenv("AUTH0_DOMAIN") + "/v2/logout")
+	logoutUrl, err := url.Parse("https://" + settings.Auth0.Domain + "/v2/logout")
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -409,8 +349,468 @@ func Logout(ctx *gin.Context) {
 
 	parameters := url.Values{}
 	parameters.Add("returnTo", returnTo.String())
-	parameters.Add("client_id", os.Getenv("AUTH0_CLIENT_ID"))
+	parameters.Add("client_id", settings.Auth0.ClientID)
 	logoutUrl.RawQuery = parameters.Encode()
 
 	ctx.Redirect(http.StatusTemporaryRedirect, logoutUrl.String())
 }
+
+func Refresh(ctx *gin.Context) {
+	var req RefreshDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	tokens, err := jwt.Refresh(req.RefreshToken, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, jwt.ErrInvalidRefreshToken) {
+			status = http.StatusUnauthorized
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully refreshed tokens",
+		StatusCode: http.StatusOK,
+		Data:       tokens,
+	})
+}
+
+func GetSessions(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
+	}
+
+	sessions, err := jwt.GetSessions(user.ProviderID)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+	current := ctx.GetString("session_id")
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == current
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched sessions",
+		StatusCode: http.StatusOK,
+		Data:       sessions,
+	})
+}
+
+func RevokeSession(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
+	}
+
+	// logging out is revoking the session the request came from
+	sessionID := ctx.Param("id")
+	if sessionID == "" {
+		sessionID = ctx.GetString("session_id")
+	}
+
+	if err := jwt.RevokeSession(user.ProviderID, sessionID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, jwt.ErrSessionNotFound) {
+			status = http.StatusNotFound
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully revoked session",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func LogoutAll(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := jwt.RevokeAllSessions(user.ProviderID); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully logged out of all devices",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func verificationLink(token string) string {
+	return appURL + "/api/v1/auth/verify?token=" + url.QueryEscape(token)
+}
+
+func resetLink(token string) string {
+	return appURL + "/reset-password?token=" + url.QueryEscape(token)
+}
+
+func Register(ctx *gin.Context) {
+	var req RegisterDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	newUser, err := registerUser(ctx.Request.Context(), req)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully registered, check your email to verify your account",
+		StatusCode: http.StatusCreated,
+		Data:       newUser,
+	})
+}
+
+func VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    "token is required",
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if _, err := verifyEmail(ctx.Request.Context(), token); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully verified email, you can now log in",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func ResendVerification(ctx *gin.Context) {
+	var req EmailDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := issueToken(req.Email, verifyPurpose); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "If the account exists and isn't verified yet, a new link is on its way",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func LocalLogin(ctx *gin.Context) {
+	var req LoginDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	dbUser, err := loginLocalUser(req.Email, req.Password)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errInvalidCredentials) {
+			status = http.StatusUnauthorized
+		} else if errors.Is(err, errEmailNotVerified) {
+			status = http.StatusForbidden
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
+	tokens, err := jwt.StartSession(dbUser.ProviderID, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully loggedIn",
+		StatusCode: http.StatusOK,
+		Data:       tokens,
+	})
+}
+
+func ForgotPassword(ctx *gin.Context) {
+	var req EmailDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := issueToken(req.Email, resetPurpose); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "If the account exists, a password reset link is on its way",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func ResetPassword(ctx *gin.Context) {
+	var req ResetPasswordDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if _, err := resetPassword(ctx.Request.Context(), req.Token, req.Password); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully reset password, log in with your new password",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func SetupTOTP(ctx *gin.Context) {
+	admin, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
+	}
+
+	secret, uri, err := setupTOTP(ctx.Request.Context(), admin.ID)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Scan the provisioning uri as a QR code, then confirm with a code from your app",
+		StatusCode: http.StatusOK,
+		Data: gin.H{
+			"secret":           secret,
+			"provisioning_uri": uri,
+		},
+	})
+}
+
+func EnableTOTP(ctx *gin.Context) {
+	admin, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req CodeDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	codes, err := enableTOTP(ctx.Request.Context(), admin.ID, req.Code)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully enabled two factor authentication, store the recovery codes somewhere safe",
+		StatusCode: http.StatusOK,
+		Data:       gin.H{"recovery_codes": codes},
+	})
+}
+
+func DisableTOTP(ctx *gin.Context) {
+	admin, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req CodeDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := disableTOTP(ctx.Request.Context(), admin.ID, req.Code); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully disabled two factor authentication",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func RegenerateRecoveryCodes(ctx *gin.Context) {
+	admin, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req CodeDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	codes, err := regenerateRecoveryCodes(ctx.Request.Context(), admin.ID, req.Code)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully generated new recovery codes, the old ones no longer work",
+		StatusCode: http.StatusOK,
+		Data:       gin.H{"recovery_codes": codes},
+	})
+}
@@ -1,6 +1,10 @@
 package auth
 
-import "time"
+import (
+	"time"
+
+	"job_board/models"
+)
 
 type TokenResponse struct {
 	AccessToken string `json:"access_token"`
@@ -58,8 +62,42 @@ type GithubResponse struct {
 type Admin struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=5"`
+	// Method asks for an emailed code instead of the authenticator app
+	Method string `json:"method" binding:"omitempty,oneof=totp email"`
 }
 
+// OtpDto confirms an admin login, otp is an authenticator code, an emailed code or a recovery code
 type OtpDto struct {
-	Otp string `json:"otp" binding:"required,min=4"`
+	Challenge string `json:"challenge" binding:"required"`
+	Otp       string `json:"otp" binding:"required,min=6"`
+}
+
+type CodeDto struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type RefreshDto struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type RegisterDto struct {
+	Name         string             `json:"name" binding:"required"`
+	Email        string             `json:"email" binding:"required,email"`
+	Password     string             `json:"password" binding:"required"`
+	Role         models.RoleAllowed `json:"role" binding:"required,oneof=user poster"`
+	MobileNumber *string            `json:"mobile_number" binding:"omitempty"`
+}
+
+type LoginDto struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type EmailDto struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordDto struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
 }
@@ -4,21 +4,47 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"log"
+
+	"job_board/jwt"
+	"job_board/middleware"
+	"job_board/models"
 )
 
 // New registers the routes and returns the router.
 func AuthRoutes(superRoute *gin.RouterGroup) {
 	authRouter := superRoute.Group("/auth")
 	{
-		authenticator, err := New()
-		if err != nil {
-			log.Fatalf("Failed to initialize the authenticator: %v", err)
+		// social login goes through auth0 and is left out when it isn't configured
+		if settings.Auth0.Enabled() {
+			authenticator, err := New()
+			if err != nil {
+				log.Fatalf("Failed to initialize the authenticator: %v", err)
+			}
+			authRouter.GET("/login", Login(authenticator))
+			authRouter.GET("/callback", Callback(authenticator))
+			authRouter.GET("/authorize", IsAuthenticated, Authorize)
+			authRouter.GET("/logout", Logout)
+		} else {
+			log.Print("AUTH0_DOMAIN isn't set, social login is disabled")
 		}
 		authRouter.POST("/login-admin", LoginAdmin)
 		authRouter.POST("/confirm-login-admin", ConfirmLoginAdmin)
-		authRouter.GET("/login", Login(authenticator))
-		authRouter.GET("/callback", Callback(authenticator))
-		authRouter.GET("/authorize", IsAuthenticated, Authorize)
-		authRouter.GET("/logout", Logout)
+		authRouter.POST("/register", Register)
+		authRouter.GET("/verify", VerifyEmail)
+		authRouter.POST("/resend-verification", ResendVerification)
+		authRouter.POST("/login", LocalLogin)
+		authRouter.POST("/forgot-password", ForgotPassword)
+		authRouter.POST("/reset-password", ResetPassword)
+		authRouter.POST("/refresh", Refresh)
+		authRouter.POST("/logout", jwt.Middleware(), RevokeSession)
+		authRouter.POST("/logout-all", jwt.Middleware(), LogoutAll)
+		authRouter.GET("/sessions", jwt.Middleware(), GetSessions)
+		authRouter.DELETE("/sessions/:id", jwt.Middleware(), RevokeSession)
+
+		twoFactorRouter := authRouter.Group("/2fa", jwt.Middleware(), middleware.PermissionMiddleware(models.TwoFactorManage))
+		twoFactorRouter.POST("/totp/setup", SetupTOTP)
+		twoFactorRouter.POST("/totp/enable", EnableTOTP)
+		twoFactorRouter.POST("/totp/disable", DisableTOTP)
+		twoFactorRouter.POST("/recovery-codes", RegenerateRecoveryCodes)
 	}
 }
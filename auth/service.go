@@ -4,30 +4,43 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	mrand "math/rand"
+	"math/big"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gin-contrib/sessions"
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"golang.org/x/oauth2"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/config"
+	"job_board/helpers"
+	"job_board/jwt"
 	"job_board/models"
+	"job_board/notifications"
+	cisredis "job_board/redis"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+var settings config.Auth
+var appURL string
+
+// Setup gives the package its database handle, the auth settings and the url emailed links point at
+func Setup(db *gorm.DB, cfg config.Auth, url string) {
+	database = db
+	settings = cfg
+	appURL = url
 }
 
 // Authenticator is used to authenticate our users.
@@ -40,16 +53,16 @@ type Authenticator struct {
 func New() (*Authenticator, error) {
 	provider, err := oidc.NewProvider(
 		context.Background(),
-		"https://"+os.Getenv("AUTH0_DOMAIN")+"/",
+		"https://"+settings.Auth0.Domain+"/",
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	conf := oauth2.Config{
-		ClientID:     os.Getenv("AUTH0_CLIENT_ID"),
-		ClientSecret: os.Getenv("AUTH0_CLIENT_SECRET"),
-		RedirectURL:  os.Getenv("AUTH0_CALLBACK_URL"),
+		ClientID:     settings.Auth0.ClientID,
+		ClientSecret: settings.Auth0.ClientSecret,
+		RedirectURL:  settings.Auth0.CallbackURL,
 		Endpoint:     provider.Endpoint(),
 		Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
 	}
@@ -87,14 +100,14 @@ func generateRandomState() (string, error) {
 }
 
 func generateToken(sub string) (*TokenResponse, error) {
-	url := "https://" + os.Getenv("AUTH0_DOMAIN") + "/oauth/token"
+	url := "https://" + settings.Auth0.Domain + "/oauth/token"
 
-	clientID := os.Getenv("TOKEN_CLIENT_ID")
-	clientSecret := os.Getenv("TOKEN_CLIENT_SECRET")
-	audience := os.Getenv("TOKEN_AUDIENCE")
+	clientID := settings.Auth0.TokenClientID
+	clientSecret := settings.Auth0.TokenClientSecret
+	audience := settings.Auth0.TokenAudience
 
 	if clientID == "" || clientSecret == "" || audience == "" {
-		return nil, fmt.Errorf("environment variable missing")
+		return nil, fmt.Errorf("TOKEN_CLIENT_ID, TOKEN_CLIENT_SECRET and TOKEN_AUDIENCE are required")
 	}
 
 	data := map[string]interface{}{
@@ -234,6 +247,70 @@ func handleUser(sub string, session sessions.Session) (*models.User, bool, error
 	}
 }
 
+func subscriberOf(user *models.User) notifications.Subscriber {
+	return notifications.Subscriber{
+		SubscriberID: user.SubscriberID,
+		Name:         user.Name,
+		Email:        user.Email,
+		Avatar:       user.Picture,
+		Data:         map[string]interface{}{},
+	}
+}
+
+// queueWelcome registers someone who just signed up through a provider and welcomes them
+// when the provider shared their email
+func queueWelcome(tx *gorm.DB, user *models.User) error {
+	if err := notifications.EnqueueSubscriber(tx, subscriberOf(user)); err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+	return notifications.Enqueue(tx, notifications.Trigger{
+		EventID: "welcome",
+		To: map[string]interface{}{
+			"subscriberId": user.SubscriberID,
+			"email":        user.Email,
+		},
+		Data: map[string]interface{}{
+			"companyName": "Jobby",
+			"name":        user.Name,
+			"title":       "Welcome to Jobby",
+			"logo":        "https://via.placeholder.com/200x200",
+		},
+	})
+}
+
+// accountEmail mails a verification or reset link, the token only ever leaves through here
+func accountEmail(user *models.User, eventID string, link string) notifications.Trigger {
+	return notifications.Trigger{
+		EventID: eventID,
+		To: map[string]interface{}{
+			"subscriberId": user.SubscriberID,
+			"email":        user.Email,
+		},
+		Data: map[string]interface{}{
+			"companyName": "Jobby",
+			"name":        user.Name,
+			"link":        link,
+		},
+	}
+}
+
+func otpNotification(admin *models.User, otp string) notifications.Trigger {
+	return notifications.Trigger{
+		EventID: "otp",
+		To: map[string]interface{}{
+			"subscriberId": admin.SubscriberID,
+			"email":        admin.Email,
+		},
+		Data: map[string]interface{}{
+			"companyName": "Jobby",
+			"otp":         otp,
+		},
+	}
+}
+
 func CreateUser(user models.User) (*models.User, bool, error) {
 	// Start a new transaction
 	tx := database.Begin()
@@ -276,6 +353,10 @@ func CreateUser(user models.User) (*models.User, bool, error) {
 		tx.Rollback()
 		return nil, false, fmt.Errorf("error creating a new user: %v", err.Error())
 	}
+	if err := queueWelcome(tx, &user); err != nil {
+		tx.Rollback()
+		return nil, false, err
+	}
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, false, fmt.Errorf("error committing transaction: %w", err)
@@ -283,20 +364,553 @@ func CreateUser(user models.User) (*models.User, bool, error) {
 	return &user, true, nil
 }
 
-const charset = "0123456789"
-
-var seededRand *mrand.Rand = mrand.New(
-	mrand.NewSource(time.Now().UnixNano()))
-
-func StringWithCharset(length int, charset string) string {
+// GenerateOtp returns a numeric code from crypto/rand, math/rand codes can be predicted
+func GenerateOtp(length int) (string, error) {
 	b := make([]byte, length)
 	for i := range b {
-		b[i] = charset[seededRand.Intn(len(charset))]
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b[i] = '0' + byte(n.Int64())
+	}
+	return string(b), nil
+}
+
+const (
+	verifyPurpose = "verify"
+	resetPurpose  = "reset"
+	verifyTTL     = 24 * time.Hour
+	resetTTL      = time.Hour
+)
+
+var (
+	errInvalidCredentials = errors.New("invalid email or password")
+	errEmailNotVerified   = errors.New("verify your email before logging in")
+	errInvalidToken       = errors.New("invalid or expired token, please request another one")
+)
+
+// hashToken is what ends up in verification_token, the purpose prefix keeps a verification
+// link from working as a reset link and never collides with the admin otp
+func hashToken(purpose, token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return purpose + ":" + hex.EncodeToString(sum[:])
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func findLocalUser(tx *gorm.DB, email string) (*models.User, error) {
+	var user models.User
+	if err := tx.
+		Where("LOWER(email) = ?", strings.ToLower(email)).
+		Where("provider_id LIKE ?", models.LocalProvider+"|%").
+		First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// registerUser creates an unverified local account, the returned token goes in the verification link
+func registerUser(ctx context.Context, req RegisterDto) (*models.User, error) {
+	if err := helpers.ValidatePassword(req.Password, req.Email); err != nil {
+		return nil, err
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var count int64
+	if err := tx.Unscoped().Model(&models.User{}).Where("LOWER(email) = ?", strings.ToLower(req.Email)).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error fetching user: %w", err)
+	}
+	if count > 0 {
+		tx.Rollback()
+		return nil, errors.New("an account with this email already exists")
+	}
+
+	subscriberID := uuid.NewString()
+	user := models.User{
+		Name:              req.Name,
+		Email:             strings.ToLower(req.Email),
+		Password:          req.Password,
+		MobileNumber:      req.MobileNumber,
+		RoleName:          req.Role,
+		ProviderID:        models.LocalProvider + "|" + subscriberID,
+		SubscriberID:      subscriberID,
+		VerificationToken: hashToken(verifyPurpose, token),
+		ExpiresAt:         time.Now().Add(verifyTTL),
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error creating a new user: %w", err)
+	}
+	if err := notifications.EnqueueSubscriber(tx, subscriberOf(&user)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := notifications.Enqueue(tx, accountEmail(&user, "email-verification", verificationLink(token))); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &user, nil
+}
+
+// consumeToken finds the user a token was issued to and clears it so it only works once
+func consumeToken(tx *gorm.DB, purpose, token string, updates map[string]interface{}) (*models.User, error) {
+	var user models.User
+	if err := tx.
+		Where("verification_token = ?", hashToken(purpose, token)).
+		Where("expires_at >= ?", time.Now()).
+		First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidToken
+		}
+		return nil, err
+	}
+
+	updates["verification_token"] = ""
+	updates["expires_at"] = time.Time{}
+	if err := tx.Model(&user).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("error updating user: %w", err)
+	}
+	return &user, nil
+}
+
+func verifyEmail(ctx context.Context, token string) (*models.User, error) {
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	user, err := consumeToken(tx, verifyPurpose, token, map[string]interface{}{"email_verified_at": time.Now()})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
-	fmt.Println(string(b), "otp")
-	return string(b)
+	return user, nil
 }
 
-func GenerateOtp(length int) string {
-	return StringWithCharset(length, charset)
+// issueToken gives a local user a new verification or reset token and queues the email
+// carrying it. Unknown emails are ignored, callers answer the same way so emails can't be
+// enumerated
+func issueToken(email, purpose string) error {
+	user, err := findLocalUser(database, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if purpose == verifyPurpose && user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	ttl, eventID, link := verifyTTL, "email-verification", verificationLink(token)
+	if purpose == resetPurpose {
+		ttl, eventID, link = resetTTL, "password-reset", resetLink(token)
+	}
+	return database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"verification_token": hashToken(purpose, token),
+			"expires_at":         time.Now().Add(ttl),
+		}).Error; err != nil {
+			return fmt.Errorf("error updating user: %w", err)
+		}
+		return notifications.Enqueue(tx, accountEmail(user, eventID, link))
+	})
+}
+
+func loginLocalUser(email, password string) (*models.User, error) {
+	user, err := findLocalUser(database, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !helpers.CheckPasswordHash(password, user.Password) {
+		return nil, errInvalidCredentials
+	}
+	if user.EmailVerifiedAt == nil {
+		return nil, errEmailNotVerified
+	}
+	return user, nil
+}
+
+// resetPassword sets a new password and signs the user out everywhere
+func resetPassword(ctx context.Context, token, password string) (*models.User, error) {
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	hash, err := helpers.HashPassword(password, helpers.PasswordCost)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	user, err := consumeToken(tx, resetPurpose, token, map[string]interface{}{
+		"password": hash,
+		// following the emailed link proves the address too
+		"email_verified_at": gorm.Expr("COALESCE(email_verified_at, NOW())"),
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := helpers.ValidatePassword(password, user.Email); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	if err := jwt.RevokeAllSessions(user.ProviderID); err != nil {
+		return nil, fmt.Errorf("password changed but sessions couldn't be revoked: %w", err)
+	}
+	return user, nil
+}
+
+const (
+	adminChallengeTTL    = 5 * time.Minute
+	maxChallengeAttempts = 5
+	maxAdminFailures     = 10
+	adminLockout         = 15 * time.Minute
+	recoveryCodeCount    = 10
+	otpPurpose           = "otp"
+	recoveryPurpose      = "recovery"
+)
+
+var (
+	errTooManyAttempts = errors.New("too many failed attempts, try again later")
+	errInvalidCode     = errors.New("invalid code")
+	errLoginExpired    = errors.New("login expired, please start again")
+)
+
+// adminChallenge is a login that passed the password check and waits for its second factor
+type adminChallenge struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Method   string    `json:"method"`
+	OtpHash  string    `json:"otp_hash,omitempty"`
+	Attempts int       `json:"attempts"`
+}
+
+func challengeKey(challenge string) string {
+	return "admin-login:" + hashToken("challenge", challenge)
+}
+
+func failuresKey(userID uuid.UUID) string {
+	return "admin-login-failures:" + userID.String()
+}
+
+// emailOtpFallback lets admins with an authenticator still ask for an emailed code
+func emailOtpFallback() bool {
+	return settings.AdminEmailOTP
+}
+
+func lockedOut(userID uuid.UUID) (bool, error) {
+	failures, err := cisredis.GetClient().Get(context.Background(), failuresKey(userID)).Int()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return failures >= maxAdminFailures, nil
+}
+
+func recordFailure(userID uuid.UUID) {
+	client := cisredis.GetClient()
+	if n, err := client.Incr(context.Background(), failuresKey(userID)).Result(); err == nil && n == 1 {
+		client.Expire(context.Background(), failuresKey(userID), adminLockout)
+	}
+}
+
+// startAdminChallenge opens the second step of an admin login, when the method is email
+// the otp is queued to the admin
+func startAdminChallenge(admin *models.User, method string) (string, string, error) {
+	if locked, err := lockedOut(admin.ID); err != nil || locked {
+		if err != nil {
+			return "", "", err
+		}
+		return "", "", errTooManyAttempts
+	}
+
+	pending := adminChallenge{UserID: admin.ID, Method: "totp"}
+	var otp string
+	if admin.TOTPEnabledAt == nil || (method == "email" && emailOtpFallback()) {
+		code, err := GenerateOtp(totpDigits)
+		if err != nil {
+			return "", "", err
+		}
+		otp = code
+		pending.Method = "email"
+		pending.OtpHash = hashToken(otpPurpose, code)
+	}
+
+	challenge, err := newToken()
+	if err != nil {
+		return "", "", err
+	}
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return "", "", err
+	}
+	if err := cisredis.GetClient().Set(context.Background(), challengeKey(challenge), data, adminChallengeTTL).Err(); err != nil {
+		return "", "", err
+	}
+	if otp != "" {
+		// the challenge lives in redis so there is no transaction to share, the code is
+		// queued on its own
+		if err := notifications.Enqueue(database, otpNotification(admin, otp)); err != nil {
+			return "", "", err
+		}
+	}
+	return challenge, pending.Method, nil
+}
+
+// useRecoveryCode burns a recovery code, the array_remove keeps two requests from using the same one
+func useRecoveryCode(userID uuid.UUID, code string) (bool, error) {
+	hash := hashToken(recoveryPurpose, strings.ToLower(strings.TrimSpace(code)))
+	result := database.Model(&models.User{}).
+		Where("id = ? AND ? = ANY(recovery_codes)", userID, hash).
+		Update("recovery_codes", gorm.Expr("array_remove(recovery_codes, ?)", hash))
+	return result.RowsAffected == 1, result.Error
+}
+
+// checkTOTP accepts each authenticator code once, a code seen over the shoulder can't be replayed
+func checkTOTP(admin *models.User, code string) (bool, error) {
+	counter, ok := validateTOTP(admin.TOTPSecret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	key := fmt.Sprintf("totp-used:%s:%d", admin.ID, counter)
+	fresh, err := cisredis.GetClient().SetNX(context.Background(), key, 1, (2*totpSkew+1)*totpPeriod*time.Second).Result()
+	if err != nil {
+		return false, err
+	}
+	return fresh, nil
+}
+
+// verifySecondFactor accepts an authenticator code or one of the recovery codes
+func verifySecondFactor(admin *models.User, code string) (bool, error) {
+	if admin.TOTPEnabledAt == nil {
+		return false, nil
+	}
+	if ok, err := checkTOTP(admin, code); ok || err != nil {
+		return ok, err
+	}
+	return useRecoveryCode(admin.ID, code)
+}
+
+// confirmAdminChallenge finishes an admin login. The code is checked against the admin the
+// challenge was opened for, never used to look someone up
+func confirmAdminChallenge(challenge, code string) (*models.User, error) {
+	client := cisredis.GetClient()
+	key := challengeKey(challenge)
+	data, err := client.Get(context.Background(), key).Bytes()
+	if err == redis.Nil {
+		return nil, errLoginExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pending adminChallenge
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, err
+	}
+
+	if locked, err := lockedOut(pending.UserID); err != nil || locked {
+		if err != nil {
+			return nil, err
+		}
+		client.Del(context.Background(), key)
+		return nil, errTooManyAttempts
+	}
+
+	var admin models.User
+	if err := database.
+		Where("role_name IN ?", []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}).
+		First(&admin, "id = ?", pending.UserID).Error; err != nil {
+		return nil, errLoginExpired
+	}
+
+	var ok bool
+	if pending.Method == "email" {
+		ok = hashToken(otpPurpose, code) == pending.OtpHash
+	} else if ok, err = verifySecondFactor(&admin, code); err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		recordFailure(admin.ID)
+		pending.Attempts++
+		if pending.Attempts >= maxChallengeAttempts {
+			client.Del(context.Background(), key)
+			return nil, errTooManyAttempts
+		}
+		if data, err := json.Marshal(pending); err == nil {
+			client.Set(context.Background(), key, data, redis.KeepTTL)
+		}
+		return nil, errInvalidCode
+	}
+
+	client.Del(context.Background(), key, failuresKey(admin.ID))
+	return &admin, nil
+}
+
+func generateRecoveryCodes() ([]string, pq.StringArray, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make(pq.StringArray, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := hex.EncodeToString(b)
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashToken(recoveryPurpose, codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// getAdmin reloads the admin from the database, the cached user in the context doesn't
+// carry the totp secret
+func getAdmin(ID uuid.UUID) (*models.User, error) {
+	var admin models.User
+	if err := database.First(&admin, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+	return &admin, nil
+}
+
+// setupTOTP stores a pending secret, it only protects logins once enableTOTP confirms it
+func setupTOTP(ctx context.Context, ID uuid.UUID) (string, string, error) {
+	admin, err := getAdmin(ID)
+	if err != nil {
+		return "", "", err
+	}
+	if admin.TOTPEnabledAt != nil {
+		return "", "", errors.New("two factor authentication is already enabled, disable it first")
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+	if err := database.WithContext(ctx).Model(admin).Update("totp_secret", secret).Error; err != nil {
+		return "", "", fmt.Errorf("error updating user: %w", err)
+	}
+	return secret, provisioningURI(secret, admin.Email), nil
+}
+
+// enableTOTP turns two factor on once the admin proves their app produces codes, the
+// recovery codes are only ever shown in this response
+func enableTOTP(ctx context.Context, ID uuid.UUID, code string) ([]string, error) {
+	admin, err := getAdmin(ID)
+	if err != nil {
+		return nil, err
+	}
+	if admin.TOTPEnabledAt != nil {
+		return nil, errors.New("two factor authentication is already enabled")
+	}
+	if admin.TOTPSecret == "" {
+		return nil, errors.New("set up two factor authentication first")
+	}
+	if ok, err := checkTOTP(admin, code); err != nil || !ok {
+		if err != nil {
+			return nil, err
+		}
+		return nil, errInvalidCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := database.WithContext(ctx).Model(admin).Updates(map[string]interface{}{
+		"totp_enabled_at": time.Now(),
+		"recovery_codes":  hashes,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("error updating user: %w", err)
+	}
+	return codes, nil
+}
+
+func disableTOTP(ctx context.Context, ID uuid.UUID, code string) error {
+	admin, err := getAdmin(ID)
+	if err != nil {
+		return err
+	}
+	if ok, err := verifySecondFactor(admin, code); err != nil || !ok {
+		if err != nil {
+			return err
+		}
+		return errInvalidCode
+	}
+
+	if err := database.WithContext(ctx).Model(admin).Updates(map[string]interface{}{
+		"totp_secret":     gorm.Expr("NULL"),
+		"totp_enabled_at": gorm.Expr("NULL"),
+		"recovery_codes":  gorm.Expr("NULL"),
+	}).Error; err != nil {
+		return fmt.Errorf("error updating user: %w", err)
+	}
+	return nil
+}
+
+func regenerateRecoveryCodes(ctx context.Context, ID uuid.UUID, code string) ([]string, error) {
+	admin, err := getAdmin(ID)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := checkTOTP(admin, code); err != nil || !ok {
+		if err != nil {
+			return nil, err
+		}
+		return nil, errInvalidCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := database.WithContext(ctx).Model(admin).Update("recovery_codes", hashes).Error; err != nil {
+		return nil, fmt.Errorf("error updating user: %w", err)
+	}
+	return codes, nil
 }
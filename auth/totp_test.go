@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 4226 and RFC 6238 test vectors, "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTPMatchesRFC4226(t *testing.T) {
+	// RFC 4226 appendix D
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		if got := hotp([]byte("12345678901234567890"), uint64(counter)); got != code {
+			t.Errorf("hotp(counter %d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestValidateTOTPMatchesRFC6238(t *testing.T) {
+	// RFC 6238 appendix B, SHA1, cut to the last six of the eight digit codes
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		now := time.Unix(tt.unix, 0)
+		step, ok := validateTOTP(rfcSecret, tt.code, now)
+		if !ok {
+			t.Errorf("validateTOTP rejected %s at %d", tt.code, tt.unix)
+			continue
+		}
+		if want := uint64(tt.unix / totpPeriod); step != want {
+			t.Errorf("validateTOTP(%s at %d) matched step %d, want %d", tt.code, tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	// 287082 is the code of step 1, seconds 30 to 59
+	tests := []struct {
+		unix int64
+		ok   bool
+	}{
+		{0, true},   // a step early
+		{45, true},  // on time
+		{60, true},  // a step late
+		{89, true},  // still a step late
+		{90, false}, // two steps late
+	}
+	for _, tt := range tests {
+		step, ok := validateTOTP(rfcSecret, "287082", time.Unix(tt.unix, 0))
+		if ok != tt.ok {
+			t.Errorf("validateTOTP at %d = %v, want %v", tt.unix, ok, tt.ok)
+		}
+		if ok && step != 1 {
+			t.Errorf("validateTOTP at %d matched step %d, want 1", tt.unix, step)
+		}
+	}
+}
+
+func TestValidateTOTPRejectsBadInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	tests := []struct {
+		name, secret, code string
+	}{
+		{"wrong code", rfcSecret, "287083"},
+		{"eight digits", rfcSecret, "94287082"},
+		{"short code", rfcSecret, "28708"},
+		{"empty code", rfcSecret, ""},
+		{"secret isn't base32", "not base32!", "287082"},
+	}
+	for _, tt := range tests {
+		if _, ok := validateTOTP(tt.secret, tt.code, now); ok {
+			t.Errorf("%s: validateTOTP accepted %q", tt.name, tt.code)
+		}
+	}
+
+	// apps hand secrets back in lower case or padded, both still work
+	for _, secret := range []string{strings.ToLower(rfcSecret), rfcSecret + "===="} {
+		if _, ok := validateTOTP(secret, "287082", now); !ok {
+			t.Errorf("validateTOTP rejected the secret written as %q", secret)
+		}
+	}
+}
+
+func TestGeneratedSecretsValidate(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := base32NoPadding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q decodes to %d bytes, %v", secret, len(key), err)
+	}
+	now := time.Now()
+	if _, ok := validateTOTP(secret, hotp(key, uint64(now.Unix()/totpPeriod)), now); !ok {
+		t.Error("the current code of a generated secret was rejected")
+	}
+	if uri := provisioningURI(secret, "ada@example.com"); !strings.Contains(uri, "secret="+secret) || !strings.HasPrefix(uri, "otpauth://totp/Jobby:ada@example.com?") {
+		t.Errorf("provisioning uri %s", uri)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 /* award segment  starts*/
@@ -87,7 +88,16 @@ func GetAward(ctx *gin.Context) {
 		Year:        year,
 	}
 
-	resp, total, page, perPage, err := getAward(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	resp, err := getAward(filter, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -99,12 +109,8 @@ func GetAward(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched awards",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     resp,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       resp,
+		Links:      resp.Links(ctx),
 	})
 }
 
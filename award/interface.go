@@ -1,21 +1,29 @@
 package award
+
 import (
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
+// validYear has to be registered before any request using it is bound, gin panics otherwise
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("validYear", validYear)
+	}
+}
+
 type Request struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description" binding:"required"`
 	Year        int    `json:"year" binding:"required,validYear"` //custom validation fucntion
 }
 
-
 func validYear(fl validator.FieldLevel) bool {
-    year := fl.Field().Int()
+	year := fl.Field().Int()
 
-    // Check if the year falls within a reasonable range
-    // For example, consider years between 1900 and 2100 as valid
-    return year >= 1900 && year <= 2100
+	// Check if the year falls within a reasonable range
+	// For example, consider years between 1900 and 2100 as valid
+	return year >= 1900 && year <= 2100
 }
 
 type Search struct {
@@ -3,19 +3,20 @@ package award
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/completeness"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func checkProfile(user models.User) bool {
@@ -47,29 +48,12 @@ func createAward(Award models.Award, user models.User) (*models.Award, error) {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	completeness.Queue(Award.ProfileID)
+
 	return &Award, nil
 }
 
-func getAward(filter Search, pageSize string, pageNumber string) ([]models.Award, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getAward(filter Search, params pagination.Params) (*pagination.Page[models.Award], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Award{})
 
@@ -84,25 +68,13 @@ func getAward(filter Search, pageSize string, pageNumber string) ([]models.Award
 		db = db.Where("description = ?", filter.Description)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.Award
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.Award](db, params)
+	if err != nil {
 		log.Println("Error finding Award:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleAward(ID uuid.UUID, user models.User) (*models.Award, error) {
@@ -116,7 +88,7 @@ func getSingleAward(ID uuid.UUID, user models.User) (*models.Award, error) {
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole && record.ProfileID != user.Profile.ID {
+	if err := policy.Check(user, "profile:read", policy.OwnProfile(record.ProfileID)); err != nil {
 		return nil, fmt.Errorf("you don't have permission to view this record")
 	}
 
@@ -137,13 +109,9 @@ func updateAward(ID uuid.UUID, user models.User, updates Request) (*models.Award
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole {
-		if profile := checkProfile(user); profile {
-			return nil, fmt.Errorf("you don't have a profile")
-		}
-		if existingRecord.ProfileID != user.Profile.ID {
-			return nil, fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:update", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
 	}
 
 	// Update the record with the provided updates
@@ -172,10 +140,9 @@ func deleteSingleAward(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName == models.UserRole {
-		if existingRecord.ProfileID != user.Profile.ID {
-			return fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:delete", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.Award{}, "id = ?", ID)
@@ -194,5 +161,7 @@ func deleteSingleAward(ID uuid.UUID, user models.User) error {
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+
+	completeness.Queue(existingRecord.ProfileID)
 	return nil
 }
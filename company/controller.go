@@ -10,6 +10,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 func create(ctx *gin.Context) {
@@ -54,13 +55,14 @@ func create(ctx *gin.Context) {
 		Logo:            req.Logo,
 	}
 
-	resp, err := createCompany(newCompany, *user)
+	resp, err := createCompany(ctx.Request.Context(), newCompany, *user)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
 			StatusCode: http.StatusBadRequest,
 			Data:       nil,
 		})
+		return
 	}
 
 	helpers.CreateResponse(ctx, helpers.Response{
@@ -121,7 +123,16 @@ func get(ctx *gin.Context) {
 		Logo:            ctx.Query("logo"),
 	}
 
-	resp, total, page, perPage, err := getCompany(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, companySorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	resp, err := getCompany(filter, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -133,12 +144,8 @@ func get(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched companies",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     resp,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       resp,
+		Links:      resp.Links(ctx),
 	})
 }
 
@@ -210,7 +217,7 @@ func update(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := updateCompany(ID, *user, req)
+	resp, err := updateCompany(ctx.Request.Context(), ID, *user, req)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -248,7 +255,7 @@ func delete(ctx *gin.Context) {
 		return
 	}
 
-	err = deleteSingleCompany(ID, *user)
+	err = deleteSingleCompany(ctx.Request.Context(), ID, *user)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -296,9 +303,16 @@ func createIndustryHandler(ctx *gin.Context) {
 
 func getIndustryHandler(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	industries, total, page, perPage, err := getIndustry(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	industries, err := getIndustry(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -310,12 +324,8 @@ func getIndustryHandler(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched industries",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     industries,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       industries,
+		Links:      industries.Links(ctx),
 	})
 }
 
@@ -437,9 +447,16 @@ func createSizes(ctx *gin.Context) {
 
 func getSizes(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	sizes, total, page, perPage, err := getEmployeesSize(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	sizes, err := getEmployeesSize(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -451,12 +468,8 @@ func getSizes(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched employee sizes",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     sizes,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       sizes,
+		Links:      sizes.Links(ctx),
 	})
 }
 
@@ -546,3 +559,520 @@ func deleteSizes(ctx *gin.Context) {
 		Data:       nil,
 	})
 }
+
+/* team segment starts*/
+
+// teamParams reads the signed in user and the company id shared by every team route
+func teamParams(ctx *gin.Context) (*models.User, uuid.UUID, bool) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return nil, uuid.Nil, false
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return nil, uuid.Nil, false
+	}
+	return user, ID, true
+}
+
+func getMembersHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	resp, err := getMembers(ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched team",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func updateMemberHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req MemberRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := updateMemberRole(ctx.Request.Context(), ID, memberID, *user, models.CompanyRole(req.Role))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully updated team member",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func removeMemberHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := removeMember(ctx.Request.Context(), ID, memberID, *user); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully removed team member",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func inviteHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req InviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	invitation, err := inviteMember(ID, *user, req.Email, models.CompanyRole(req.Role))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully sent invitation",
+		StatusCode: http.StatusOK,
+		Data:       invitation,
+	})
+}
+
+func getInvitationsHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	resp, err := getInvitations(ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched invitations",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func revokeInvitationHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	invitationID, err := uuid.Parse(ctx.Param("invitationId"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := revokeInvitation(ID, invitationID, *user); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully revoked invitation",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func acceptInvitationHandler(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req AcceptInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := acceptInvitation(ctx.Request.Context(), req.Token, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully joined company",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func transferHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req TransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := transferOwnership(ctx.Request.Context(), ID, req.UserID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully transferred company",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+/* verification segment starts*/
+
+func startVerificationHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req VerificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	verification, err := startVerification(ID, *user, req)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	message := "Verification sent for review"
+	switch verification.Method {
+	case models.VerifyByEmail:
+		message = "Verification code sent to " + verification.Email
+	case models.VerifyByDNS:
+		message = "Add the TXT record to " + verification.Domain + " then confirm"
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    message,
+		StatusCode: http.StatusOK,
+		Data:       verification,
+	})
+}
+
+func getVerificationsHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	resp, err := getVerifications(ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched verifications",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func confirmVerificationHandler(ctx *gin.Context) {
+	user, ID, ok := teamParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req ConfirmVerificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := confirmVerification(ctx.Request.Context(), ID, *user, req.Code)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully verified company",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func revokeVerificationHandler(ctx *gin.Context) {
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := revokeVerification(ctx.Request.Context(), ID); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully revoked verification",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func getReviewQueueHandler(ctx *gin.Context) {
+	params, err := pagination.FromContext(ctx, verificationSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	status := models.VerificationStatus(ctx.DefaultQuery("status", string(models.VerificationPending)))
+	resp, err := getReviewQueue(status, params)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched verification reviews",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+		Links:      resp.Links(ctx),
+	})
+}
+
+func reviewHandler(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("verificationId"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req ReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := reviewVerification(ctx.Request.Context(), ID, *user, *req.Approve, req.Note)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully reviewed verification",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+/* public employer page */
+
+func employerPage(ctx *gin.Context) {
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getEmployerPage(ID)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusNotFound,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched employer",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
@@ -0,0 +1,127 @@
+package company
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"job_board/models"
+)
+
+// employers show at most this many open jobs, the job search has the rest
+const employerJobsLimit = 50
+
+// getEmployerPage gathers what anyone may see about a company without signing in
+func getEmployerPage(ID uuid.UUID) (*EmployerPage, error) {
+	var company models.Company
+	if err := database.
+		Preload("Industry").
+		Preload("EmployeesSize").
+		First(&company, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+
+	page := EmployerPage{
+		Employer: Employer{
+			ID:            company.ID,
+			Name:          company.Name,
+			Description:   company.Description,
+			Website:       company.Website,
+			Logo:          company.Logo,
+			Location:      company.Location,
+			Established:   company.Established,
+			Industry:      company.Industry.Name,
+			EmployeesSize: company.EmployeesSize.Name,
+			Verified:      company.Verified,
+			VerifiedAt:    company.VerifiedAt,
+		},
+		OpenJobs: []models.Job{},
+	}
+
+	if err := database.
+		Preload("Country").
+		Preload("JobType").
+		Preload("Level").
+		Preload("SalaryCurrency").
+		Where("company_id = ?", ID).
+		Where(models.LiveJobs).
+		Order("published_at DESC").
+		Limit(employerJobsLimit).
+		Find(&page.OpenJobs).Error; err != nil {
+		return nil, fmt.Errorf("error fetching open jobs: %w", err)
+	}
+	for i := range page.OpenJobs {
+		page.OpenJobs[i].MaskSalary(nil)
+	}
+
+	stats, err := hiringStats(ID)
+	if err != nil {
+		return nil, err
+	}
+	page.Stats = *stats
+
+	return &page, nil
+}
+
+func hiringStats(companyID uuid.UUID) (*HiringStats, error) {
+	since := time.Now().AddDate(-1, 0, 0)
+	stats := HiringStats{}
+
+	if err := database.Model(&models.Job{}).
+		Where("company_id = ?", companyID).
+		Where(models.LiveJobs).
+		Count(&stats.OpenJobs).Error; err != nil {
+		return nil, fmt.Errorf("error counting open jobs: %w", err)
+	}
+	if err := database.Model(&models.Job{}).
+		Where("company_id = ? AND published_at >= ?", companyID, since).
+		Count(&stats.JobsPosted).Error; err != nil {
+		return nil, fmt.Errorf("error counting posted jobs: %w", err)
+	}
+
+	applications := database.Model(&models.JobApplication{}).
+		Joins("JOIN jobs ON jobs.id = job_applications.job_id").
+		Where("jobs.company_id = ? AND job_applications.applied_at >= ?", companyID, since)
+
+	var counts struct {
+		Applications int64
+		Hires        int64
+		Answered     int64
+	}
+	if err := applications.
+		Select(`COUNT(*) AS applications,
+			COUNT(*) FILTER (WHERE job_applications.status = ?) AS hires,
+			COUNT(*) FILTER (WHERE job_applications.status NOT IN ?) AS answered`,
+			models.Hired, []models.Status{models.Applied, models.Withdrawn}).
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("error counting applications: %w", err)
+	}
+	stats.Applications = counts.Applications
+	stats.Hires = counts.Hires
+	if counts.Applications > 0 {
+		stats.ResponseRate = float64(counts.Answered) / float64(counts.Applications)
+	}
+
+	// the first move out of applied, withdrawing doesn't count as hearing back
+	var response struct {
+		Days *float64
+	}
+	if err := database.Raw(`SELECT AVG(EXTRACT(EPOCH FROM responses.changed_at - job_applications.applied_at) / 86400) AS days
+		FROM job_applications
+		JOIN jobs ON jobs.id = job_applications.job_id
+		JOIN (
+			SELECT job_application_id, MIN(created_at) AS changed_at
+			FROM application_status_histories
+			WHERE from_status = ? AND to_status <> ?
+			GROUP BY job_application_id
+		) responses ON responses.job_application_id = job_applications.id
+		WHERE jobs.company_id = ? AND job_applications.applied_at >= ? AND job_applications.deleted_at IS NULL`,
+		models.Applied, models.Withdrawn, companyID, since).
+		Scan(&response).Error; err != nil {
+		return nil, fmt.Errorf("error measuring response time: %w", err)
+	}
+	stats.AverageResponseDays = response.Days
+
+	return &stats, nil
+}
@@ -4,8 +4,21 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"time"
+
+	"job_board/models"
+	"job_board/pagination"
 )
 
+var companySorts = pagination.Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at":  "created_at",
+		"updated_at":  "updated_at",
+		"name":        "name",
+		"established": "established",
+	},
+}
+
 type Request struct {
 	Name string `json:"name" binding:"required"`
 }
@@ -49,3 +62,70 @@ type SearchCompanyRequest struct {
 	EmployeesSizeID uuid.UUID `json:"employees_size_id" binding:"required"`
 	Logo            string    `json:"logo" binding:"required"`
 }
+
+type InviteRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=recruiter viewer"`
+}
+
+type MemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=recruiter viewer"`
+}
+
+type TransferRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type VerificationRequest struct {
+	Method string `json:"method" binding:"required,oneof=email dns review"`
+	// address on the website domain for the email method
+	Email string `json:"email" binding:"required_if=Method email,omitempty,email"`
+	// what reviewers should look at for the review method
+	Note string `json:"note" binding:"max=2000"`
+}
+
+type ConfirmVerificationRequest struct {
+	Code string `json:"code"`
+}
+
+type ReviewRequest struct {
+	Approve *bool  `json:"approve" binding:"required"`
+	Note    string `json:"note" binding:"max=2000"`
+}
+
+// Employer is the public face of a company, nothing about its team or owner
+type Employer struct {
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Website       string     `json:"website"`
+	Logo          string     `json:"logo"`
+	Location      string     `json:"location"`
+	Established   time.Time  `json:"established"`
+	Industry      string     `json:"industry"`
+	EmployeesSize string     `json:"employees_size"`
+	Verified      bool       `json:"verified"`
+	VerifiedAt    *time.Time `json:"verified_at"`
+}
+
+// HiringStats covers the last year of hiring unless the name says otherwise
+type HiringStats struct {
+	OpenJobs      int64 `json:"open_jobs"`
+	JobsPosted    int64 `json:"jobs_posted"`
+	Applications  int64 `json:"applications"`
+	Hires         int64 `json:"hires"`
+	// share of applications that heard back, from 0 to 1
+	ResponseRate float64 `json:"response_rate"`
+	// days from applying to the first status change, nil until anyone heard back
+	AverageResponseDays *float64 `json:"average_response_days"`
+}
+
+type EmployerPage struct {
+	Employer Employer     `json:"employer"`
+	OpenJobs []models.Job `json:"open_jobs"`
+	Stats    HiringStats  `json:"stats"`
+}
@@ -8,35 +8,61 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-var everybody []models.RoleAllowed = []models.RoleAllowed{models.PosterRole, models.AdminRole, models.SuperAdminRole}
+var manageTeams = []models.Permission{models.CompanyUpdateCompany, models.CompanyUpdateAny}
 
+// CompanyRoutes serves companies under /organizations, /companies has always been where
+// the job routes live
 func CompanyRoutes(superRoute *gin.RouterGroup) {
-	companyRouter := superRoute.Group("/companies")
+	companyRouter := superRoute.Group("/organizations")
 
 	companyRouter.Use(jwt.Middleware())
-	companyRouter.POST("/", middleware.RolesMiddleware(everybody), create)
-	companyRouter.GET("/", middleware.RolesMiddleware(everybody), get)
+	companyRouter.POST("/", middleware.PermissionMiddleware(models.CompanyCreate), create)
+	companyRouter.GET("/", middleware.PermissionMiddleware(models.CompanyRead), get)
 	companyRouter.GET("/:id", getSingle)
-	companyRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), update)
-	companyRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), delete)
+	companyRouter.PATCH("/:id", middleware.PermissionMiddleware(models.CompanyUpdateCompany, models.CompanyUpdateAny), update)
+	companyRouter.DELETE("/:id", middleware.PermissionMiddleware(models.CompanyDeleteCompany, models.CompanyDeleteAny), delete)
+
+	companyRouter.POST("/invitations/accept", middleware.PermissionMiddleware(models.JobReadCompany), acceptInvitationHandler)
+	companyRouter.GET("/verifications", middleware.PermissionMiddleware(models.CompanyUpdateAny), getReviewQueueHandler)
+	companyRouter.POST("/verifications/:verificationId/review", middleware.PermissionMiddleware(models.CompanyUpdateAny), reviewHandler)
+	companyRouter.DELETE("/:id/verification", middleware.PermissionMiddleware(models.CompanyUpdateAny), revokeVerificationHandler)
+	SetupTeamRoutes(companyRouter.Group("/:id"))
 
 	SetupIndustryRoutes(companyRouter.Group("/industries"))
 	SetupSizesRoutes(companyRouter.Group("/sizes"))
 }
 
+// SetupTeamRoutes registers the member and invitation routes of a single company
+func SetupTeamRoutes(teamRouter *gin.RouterGroup) {
+	teamRouter.GET("/members", middleware.PermissionMiddleware(models.JobReadCompany, models.JobReadAny), getMembersHandler)
+	teamRouter.PATCH("/members/:userId", middleware.PermissionMiddleware(manageTeams...), updateMemberHandler)
+	teamRouter.DELETE("/members/:userId", middleware.PermissionMiddleware(manageTeams...), removeMemberHandler)
+	teamRouter.POST("/invitations", middleware.PermissionMiddleware(manageTeams...), inviteHandler)
+	teamRouter.GET("/invitations", middleware.PermissionMiddleware(manageTeams...), getInvitationsHandler)
+	teamRouter.DELETE("/invitations/:invitationId", middleware.PermissionMiddleware(manageTeams...), revokeInvitationHandler)
+	teamRouter.POST("/transfer", middleware.PermissionMiddleware(manageTeams...), transferHandler)
+	teamRouter.POST("/verification", middleware.PermissionMiddleware(manageTeams...), startVerificationHandler)
+	teamRouter.GET("/verification", middleware.PermissionMiddleware(manageTeams...), getVerificationsHandler)
+	teamRouter.POST("/verification/confirm", middleware.PermissionMiddleware(manageTeams...), confirmVerificationHandler)
+}
+
+// EmployerRoutes serves the public company pages, no account needed
+func EmployerRoutes(superRoute *gin.RouterGroup) {
+	superRoute.GET("/employers/:id", employerPage)
+}
+
 func SetupIndustryRoutes(industryRouter *gin.RouterGroup) {
-	industryRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), createIndustryHandler)
+	industryRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), createIndustryHandler)
 	industryRouter.GET("/", getIndustryHandler)
 	industryRouter.GET("/:id", getSingleIndustryHandler)
-	industryRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), updateIndustryHandler)
-	industryRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), deleteIndustryHandler)
+	industryRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), updateIndustryHandler)
+	industryRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), deleteIndustryHandler)
 }
 
 func SetupSizesRoutes(sizesRouter *gin.RouterGroup) {
-	sizesRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), createSizes)
+	sizesRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), createSizes)
 	sizesRouter.GET("/", getSizes)
 	sizesRouter.GET("/:id", getSingleSizes)
-	sizesRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), updateSizes)
-	sizesRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), deleteSizes)
+	sizesRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), updateSizes)
+	sizesRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), deleteSizes)
 }
@@ -1,22 +1,35 @@
 package company
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
-	"strconv"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
 	"job_board/models"
+	"job_board/notifications"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+var appURL string
+
+// Setup gives the package its database handle, the url emailed links point at and the dns
+// server verification challenges are checked against
+func Setup(db *gorm.DB, url string, dnsResolver string) {
+	database = db
+	appURL = url
+	useResolver(dnsResolver)
 }
 
 func createIndustry(Industry models.Industry) (*models.Industry, error) {
@@ -40,26 +53,7 @@ func createIndustry(Industry models.Industry) (*models.Industry, error) {
 	return &Industry, nil
 }
 
-func getIndustry(name string, pageSize string, pageNumber string) ([]models.Industry, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getIndustry(name string, params pagination.Params) (*pagination.Page[models.Industry], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Industry{})
 
@@ -68,25 +62,13 @@ func getIndustry(name string, pageSize string, pageNumber string) ([]models.Indu
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.Industry
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.Industry](db, params)
+	if err != nil {
 		log.Println("Error finding Industry :", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleIndustry(ID uuid.UUID) (*models.Industry, error) {
@@ -150,26 +132,7 @@ func createEmployeesSize(EmployeesSize models.EmployeesSize) (*models.EmployeesS
 	return &EmployeesSize, nil
 }
 
-func getEmployeesSize(name string, pageSize string, pageNumber string) ([]models.EmployeesSize, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getEmployeesSize(name string, params pagination.Params) (*pagination.Page[models.EmployeesSize], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.EmployeesSize{})
 
@@ -178,25 +141,13 @@ func getEmployeesSize(name string, pageSize string, pageNumber string) ([]models
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.EmployeesSize
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.EmployeesSize](db, params)
+	if err != nil {
 		log.Println("Error finding EmployeesSize:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleEmployeesSize(ID uuid.UUID) (*models.EmployeesSize, error) {
@@ -242,8 +193,8 @@ func deleteSingle(EmployeesSizeID uuid.UUID) error {
 
 /* company creation segment starts*/
 
-func createCompany(Company models.Company, user models.User) (*models.Company, error) {
-	tx := database.Begin()
+func createCompany(ctx context.Context, Company models.Company, user models.User) (*models.Company, error) {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -255,6 +206,12 @@ func createCompany(Company models.Company, user models.User) (*models.Company, e
 		return nil, fmt.Errorf("error creating a new Company experience: %w", err)
 	}
 
+	owner := models.CompanyMember{CompanyID: Company.ID, UserID: user.ID, Role: models.CompanyOwner}
+	if err := tx.Create(&owner).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error adding the company owner: %w", err)
+	}
+
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
@@ -263,26 +220,7 @@ func createCompany(Company models.Company, user models.User) (*models.Company, e
 	return &Company, nil
 }
 
-func getCompany(filter SearchCompanyRequest, pageSize string, pageNumber string) ([]models.Company, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getCompany(filter SearchCompanyRequest, params pagination.Params) (*pagination.Page[models.Company], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Company{})
 
@@ -316,25 +254,13 @@ func getCompany(filter SearchCompanyRequest, pageSize string, pageNumber string)
 		db = db.Where("esthablished >= ?", esthablishedStr)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.Company
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.Company](db, params)
+	if err != nil {
 		log.Println("Error finding Company:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleCompany(ID uuid.UUID, user models.User) (*models.Company, error) {
@@ -352,8 +278,8 @@ func getSingleCompany(ID uuid.UUID, user models.User) (*models.Company, error) {
 	return &record, nil
 }
 
-func updateCompany(ID uuid.UUID, user models.User, updates Request) (*models.Company, error) {
-	tx := database.Begin()
+func updateCompany(ctx context.Context, ID uuid.UUID, user models.User, updates Request) (*models.Company, error) {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -366,8 +292,9 @@ func updateCompany(ID uuid.UUID, user models.User, updates Request) (*models.Com
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.PosterRole && existingRecord.UserID != user.ID {
-		return nil, fmt.Errorf("you don't have permission to view this record")
+	if err := policy.Check(user, "company:update", policy.Company(existingRecord.ID, models.CompanyOwner)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
 	}
 
 	// Update the record with the provided updates
@@ -375,6 +302,12 @@ func updateCompany(ID uuid.UUID, user models.User, updates Request) (*models.Com
 		return nil, err // Error updating the record
 	}
 
+	// the company name is part of every job's search document
+	if err := models.RefreshJobSearch(tx, "company_id = ?", existingRecord.ID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error indexing company jobs: %w", err)
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
@@ -382,8 +315,8 @@ func updateCompany(ID uuid.UUID, user models.User, updates Request) (*models.Com
 	return &existingRecord, nil
 }
 
-func deleteSingleCompany(ID uuid.UUID, user models.User) error {
-	tx := database.Begin()
+func deleteSingleCompany(ctx context.Context, ID uuid.UUID, user models.User) error {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -396,8 +329,9 @@ func deleteSingleCompany(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName == models.PosterRole && existingRecord.UserID != user.ID {
-		return fmt.Errorf("you don't have permission to view this record")
+	if err := policy.Check(user, "company:delete", policy.Company(existingRecord.ID, models.CompanyOwner)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.Company{}, "id = ?", ID)
@@ -418,3 +352,347 @@ func deleteSingleCompany(ID uuid.UUID, user models.User) error {
 	}
 	return nil
 }
+
+/* team segment starts*/
+
+// invitations are good for a week
+const invitationTTL = 7 * 24 * time.Hour
+
+var errNotOwner = errors.New("only the company owner can manage its team")
+
+func hashInvitation(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func checkTeamOwner(companyID uuid.UUID, user models.User) error {
+	if err := policy.Check(user, "company:update", policy.Company(companyID, models.CompanyOwner)); err != nil {
+		return errNotOwner
+	}
+	return nil
+}
+
+func getMembers(companyID uuid.UUID, user models.User) ([]models.CompanyMember, error) {
+	if err := policy.Check(user, "job:read", policy.Company(companyID)); err != nil {
+		return nil, fmt.Errorf("you're not on this company's team")
+	}
+
+	var data []models.CompanyMember
+	if err := database.
+		Preload("User").
+		Where("company_id = ?", companyID).
+		Order("created_at ASC").
+		Find(&data).Error; err != nil {
+		log.Println("Error finding company members:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func invitationLink(token string) string {
+	return appURL + "/invitations/accept?token=" + url.QueryEscape(token)
+}
+
+func invitationNotification(invitation models.CompanyInvitation, inviter models.User, token string) notifications.Trigger {
+	// invitees may not have an account yet so their email doubles as the subscriber id
+	return notifications.Trigger{
+		EventID: "company-invitation",
+		To: map[string]interface{}{
+			"subscriberId": invitation.Email,
+			"email":        invitation.Email,
+		},
+		Data: map[string]interface{}{
+			"companyName": invitation.Company.Name,
+			"inviter":     inviter.Name,
+			"role":        invitation.Role,
+			"link":        invitationLink(token),
+		},
+	}
+}
+
+// inviteMember records an invitation and queues the email carrying its token, any earlier
+// pending invitation for the same address is replaced
+func inviteMember(companyID uuid.UUID, user models.User, email string, role models.CompanyRole) (*models.CompanyInvitation, error) {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return nil, err
+	}
+	if role == models.CompanyOwner {
+		return nil, errors.New("invite the new owner as a recruiter then transfer ownership")
+	}
+
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var company models.Company
+	if err := tx.First(&company, "id = ?", companyID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	var members int64
+	if err := tx.Model(&models.CompanyMember{}).
+		Joins("JOIN users ON users.id = company_members.user_id").
+		Where("company_members.company_id = ? AND LOWER(users.email) = ?", companyID, email).
+		Count(&members).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if members > 0 {
+		tx.Rollback()
+		return nil, errors.New("this person is already on the team")
+	}
+
+	if err := tx.Where("company_id = ? AND email = ? AND accepted_at IS NULL", companyID, email).
+		Delete(&models.CompanyInvitation{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error replacing invitation: %w", err)
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	invitation := models.CompanyInvitation{
+		CompanyID:   companyID,
+		Email:       email,
+		Role:        role,
+		TokenHash:   hashInvitation(token),
+		InvitedByID: user.ID,
+		ExpiresAt:   time.Now().Add(invitationTTL),
+	}
+	if err := tx.Create(&invitation).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error creating invitation: %w", err)
+	}
+	invitation.Company = &company
+	if err := notifications.Enqueue(tx, invitationNotification(invitation, user, token)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &invitation, nil
+}
+
+func getInvitations(companyID uuid.UUID, user models.User) ([]models.CompanyInvitation, error) {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return nil, err
+	}
+
+	var data []models.CompanyInvitation
+	if err := database.
+		Where("company_id = ? AND accepted_at IS NULL AND expires_at > ?", companyID, time.Now()).
+		Order("created_at DESC").
+		Find(&data).Error; err != nil {
+		log.Println("Error finding company invitations:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func revokeInvitation(companyID uuid.UUID, invitationID uuid.UUID, user models.User) error {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return err
+	}
+
+	result := database.Where("id = ? AND company_id = ? AND accepted_at IS NULL", invitationID, companyID).
+		Delete(&models.CompanyInvitation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("invitation with ID %s not found", invitationID)
+	}
+	return nil
+}
+
+// acceptInvitation adds the signed in user to the team, the invitation has to be for their email
+func acceptInvitation(ctx context.Context, token string, user models.User) (*models.CompanyMember, error) {
+	if !policy.Can(user, models.JobReadCompany) {
+		return nil, errors.New("only employer accounts can join a company team")
+	}
+
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var invitation models.CompanyInvitation
+	if err := tx.Where("token_hash = ?", hashInvitation(token)).First(&invitation).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, err
+	}
+	if invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		tx.Rollback()
+		return nil, errors.New("this invitation has expired")
+	}
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		tx.Rollback()
+		return nil, errors.New("this invitation was sent to a different email address")
+	}
+
+	member := models.CompanyMember{CompanyID: invitation.CompanyID, UserID: user.ID, Role: invitation.Role}
+	if err := tx.Create(&member).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("you're already on this team")
+		}
+		return nil, fmt.Errorf("error joining company: %w", err)
+	}
+
+	now := time.Now()
+	if err := tx.Model(&invitation).Update("accepted_at", &now).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error accepting invitation: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &member, nil
+}
+
+func updateMemberRole(ctx context.Context, companyID uuid.UUID, memberID uuid.UUID, user models.User, role models.CompanyRole) (*models.CompanyMember, error) {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return nil, err
+	}
+	if role == models.CompanyOwner {
+		return nil, errors.New("use an ownership transfer to make someone the owner")
+	}
+
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var member models.CompanyMember
+	if err := tx.First(&member, "company_id = ? AND user_id = ?", companyID, memberID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if member.Role == models.CompanyOwner {
+		tx.Rollback()
+		return nil, errors.New("transfer ownership before changing the owner's role")
+	}
+
+	if err := tx.Model(&member).Update("role", role).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error updating member: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &member, nil
+}
+
+// removeMember takes someone off the team, only the owner or an admin can
+func removeMember(ctx context.Context, companyID uuid.UUID, memberID uuid.UUID, user models.User) error {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return err
+	}
+
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var member models.CompanyMember
+	if err := tx.First(&member, "company_id = ? AND user_id = ?", companyID, memberID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if member.Role == models.CompanyOwner {
+		tx.Rollback()
+		return errors.New("the owner can't leave, transfer ownership first")
+	}
+
+	if err := tx.Delete(&member).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error removing member: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// transferOwnership hands the company to another member, the old owner stays on as a recruiter
+func transferOwnership(ctx context.Context, companyID uuid.UUID, newOwnerID uuid.UUID, user models.User) (*models.Company, error) {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return nil, err
+	}
+
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var company models.Company
+	if err := tx.First(&company, "id = ?", companyID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var member models.CompanyMember
+	if err := tx.First(&member, "company_id = ? AND user_id = ?", companyID, newOwnerID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("the new owner has to be on the team already")
+		}
+		return nil, err
+	}
+	if member.Role == models.CompanyOwner {
+		tx.Rollback()
+		return nil, errors.New("this member already owns the company")
+	}
+
+	if err := tx.Model(&models.CompanyMember{}).
+		Where("company_id = ? AND role = ?", companyID, models.CompanyOwner).
+		Update("role", models.CompanyRecruiter).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error demoting the current owner: %w", err)
+	}
+	if err := tx.Model(&member).Update("role", models.CompanyOwner).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error promoting the new owner: %w", err)
+	}
+	// the company keeps pointing at its current owner, UpdateColumn skips the Established hook
+	if err := tx.Model(&company).UpdateColumn("user_id", newOwnerID).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error transferring company: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &company, nil
+}
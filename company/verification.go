@@ -0,0 +1,411 @@
+package company
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"job_board/auth"
+	"job_board/models"
+	"job_board/notifications"
+	"job_board/pagination"
+)
+
+const (
+	emailCodeTTL      = 30 * time.Minute
+	dnsChallengeTTL   = 7 * 24 * time.Hour
+	maxCodeAttempts   = 5
+	dnsRecordPrefix   = "jobby-verification="
+	dnsLookupDeadline = 10 * time.Second
+)
+
+// Resolver looks up the TXT records of a domain, *net.Resolver satisfies it so tests and
+// deployments behind split DNS can bring their own
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+var resolver Resolver = net.DefaultResolver
+
+// free mailboxes anybody can sign up for prove nothing about a company
+var freeMailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "yahoo.com": true, "outlook.com": true,
+	"hotmail.com": true, "live.com": true, "icloud.com": true, "aol.com": true,
+	"proton.me": true, "protonmail.com": true, "gmx.com": true, "mail.com": true,
+	"yandex.com": true, "zoho.com": true,
+}
+
+var verificationSorts = pagination.Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	},
+}
+
+// useResolver points challenges at a specific dns server, e.g 1.1.1.1:53, empty keeps the system resolver
+func useResolver(address string) {
+	if address != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, address)
+			},
+		}
+	}
+}
+
+// websiteDomain is the host of the company's website without a leading www
+func websiteDomain(website string) (string, error) {
+	website = strings.TrimSpace(website)
+	if !strings.Contains(website, "://") {
+		website = "https://" + website
+	}
+	parsed, err := url.Parse(website)
+	if err != nil || parsed.Hostname() == "" {
+		return "", errors.New("the company needs a valid website to be verified")
+	}
+	domain := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if !strings.Contains(domain, ".") || net.ParseIP(domain) != nil {
+		return "", errors.New("the company website has to be on a domain name")
+	}
+	return domain, nil
+}
+
+// emailOnDomain accepts addresses on the domain itself or one of its subdomains
+func emailOnDomain(email string, domain string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	host := strings.ToLower(email[at+1:])
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func hasRecord(ctx context.Context, domain string, record string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dnsLookupDeadline)
+	defer cancel()
+	records, err := resolver.LookupTXT(ctx, domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, value := range records {
+		if strings.TrimSpace(value) == record {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func verificationCodeNotification(verification models.CompanyVerification, code string) notifications.Trigger {
+	return notifications.Trigger{
+		EventID: "company-verification-code",
+		To: map[string]interface{}{
+			"subscriberId": verification.Email,
+			"email":        verification.Email,
+		},
+		Data: map[string]interface{}{
+			"companyName": verification.Company.Name,
+			"otp":         code,
+		},
+	}
+}
+
+// startVerification opens a new challenge for the company, any earlier pending one is
+// dropped. For email the code is queued to the address
+func startVerification(companyID uuid.UUID, user models.User, req VerificationRequest) (*models.CompanyVerification, error) {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return nil, err
+	}
+
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var company models.Company
+	if err := tx.First(&company, "id = ?", companyID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if company.Verified {
+		tx.Rollback()
+		return nil, errors.New("this company is already verified")
+	}
+
+	verification := models.CompanyVerification{
+		CompanyID:     companyID,
+		Method:        models.VerificationMethod(req.Method),
+		Status:        models.VerificationPending,
+		RequestedByID: user.ID,
+	}
+	var code string
+	now := time.Now()
+	switch verification.Method {
+	case models.VerifyByEmail, models.VerifyByDNS:
+		domain, err := websiteDomain(company.Website)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		verification.Domain = domain
+		if verification.Method == models.VerifyByDNS {
+			token, err := newInvitationToken()
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			expiresAt := now.Add(dnsChallengeTTL)
+			verification.Record = dnsRecordPrefix + token[:32]
+			verification.ExpiresAt = &expiresAt
+			break
+		}
+
+		email := strings.ToLower(strings.TrimSpace(req.Email))
+		if freeMailDomains[domain] {
+			tx.Rollback()
+			return nil, errors.New("companies on a free email provider have to be verified by dns or review")
+		}
+		if !emailOnDomain(email, domain) {
+			tx.Rollback()
+			return nil, fmt.Errorf("the email has to be on the company's domain %s", domain)
+		}
+		if code, err = auth.GenerateOtp(6); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		expiresAt := now.Add(emailCodeTTL)
+		verification.Email = email
+		verification.CodeHash = hashInvitation(code)
+		verification.ExpiresAt = &expiresAt
+	case models.VerifyByReview:
+		if strings.TrimSpace(req.Note) == "" {
+			tx.Rollback()
+			return nil, errors.New("tell the reviewers how to check your company in the note")
+		}
+		verification.Note = req.Note
+	}
+
+	if err := tx.Where("company_id = ? AND status = ?", companyID, models.VerificationPending).
+		Delete(&models.CompanyVerification{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error replacing verification: %w", err)
+	}
+	if err := tx.Create(&verification).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error creating verification: %w", err)
+	}
+	verification.Company = &company
+	if verification.Method == models.VerifyByEmail {
+		if err := notifications.Enqueue(tx, verificationCodeNotification(verification, code)); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &verification, nil
+}
+
+func getVerifications(companyID uuid.UUID, user models.User) ([]models.CompanyVerification, error) {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return nil, err
+	}
+
+	var data []models.CompanyVerification
+	if err := database.
+		Where("company_id = ?", companyID).
+		Order("created_at DESC").
+		Limit(20).
+		Find(&data).Error; err != nil {
+		log.Println("Error finding company verifications:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// confirmVerification checks the emailed code or looks for the TXT record, review
+// requests are confirmed by an admin instead
+func confirmVerification(ctx context.Context, companyID uuid.UUID, user models.User, code string) (*models.CompanyVerification, error) {
+	if err := checkTeamOwner(companyID, user); err != nil {
+		return nil, err
+	}
+
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var verification models.CompanyVerification
+	if err := tx.
+		Where("company_id = ? AND status = ?", companyID, models.VerificationPending).
+		Order("created_at DESC").
+		First(&verification).Error; err != nil {
+		tx.Rollback()
+		return nil, errors.New("there is no verification in progress for this company")
+	}
+	if verification.ExpiresAt != nil && time.Now().After(*verification.ExpiresAt) {
+		tx.Rollback()
+		return nil, errors.New("this verification has expired, start a new one")
+	}
+
+	switch verification.Method {
+	case models.VerifyByReview:
+		tx.Rollback()
+		return nil, errors.New("this verification is waiting for an admin to review it")
+	case models.VerifyByEmail:
+		if verification.Attempts >= maxCodeAttempts {
+			tx.Rollback()
+			return nil, errors.New("too many wrong codes, start a new verification")
+		}
+		if hashInvitation(strings.TrimSpace(code)) != verification.CodeHash {
+			// the attempt has to stick even though the check failed
+			if err := tx.Model(&verification).UpdateColumn("attempts", verification.Attempts+1).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if err := tx.Commit().Error; err != nil {
+				return nil, fmt.Errorf("error committing transaction: %w", err)
+			}
+			return nil, errors.New("invalid verification code")
+		}
+	case models.VerifyByDNS:
+		found, err := hasRecord(ctx, verification.Domain, verification.Record)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error looking up %s: %w", verification.Domain, err)
+		}
+		if !found {
+			tx.Rollback()
+			return nil, fmt.Errorf("no TXT record %q found on %s yet, DNS changes can take a while to show up", verification.Record, verification.Domain)
+		}
+	}
+
+	now := time.Now()
+	if err := tx.Model(&verification).Updates(map[string]interface{}{
+		"status":       models.VerificationApproved,
+		"completed_at": &now,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error completing verification: %w", err)
+	}
+	if err := models.SetCompanyVerified(tx, companyID, true); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error verifying company: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &verification, nil
+}
+
+/* admin review queue */
+
+func getReviewQueue(status models.VerificationStatus, params pagination.Params) (*pagination.Page[models.CompanyVerification], error) {
+	db := database.Model(&models.CompanyVerification{}).
+		Preload("Company").
+		Where("method = ?", models.VerifyByReview)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	data, err := pagination.Find[models.CompanyVerification](db, params)
+	if err != nil {
+		log.Println("Error finding verification reviews:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func reviewVerification(ctx context.Context, ID uuid.UUID, user models.User, approve bool, note string) (*models.CompanyVerification, error) {
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var verification models.CompanyVerification
+	if err := tx.First(&verification, "id = ? AND method = ?", ID, models.VerifyByReview).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if verification.Status != models.VerificationPending {
+		tx.Rollback()
+		return nil, fmt.Errorf("this review was already %s", verification.Status)
+	}
+
+	status := models.VerificationRejected
+	if approve {
+		status = models.VerificationApproved
+	}
+	now := time.Now()
+	if err := tx.Model(&verification).Updates(map[string]interface{}{
+		"status":         status,
+		"review_note":    note,
+		"reviewed_by_id": user.ID,
+		"completed_at":   &now,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error reviewing verification: %w", err)
+	}
+	if approve {
+		if err := models.SetCompanyVerified(tx, verification.CompanyID, true); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error verifying company: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &verification, nil
+}
+
+// revokeVerification takes the badge away, e.g after the company changed hands
+func revokeVerification(ctx context.Context, companyID uuid.UUID) error {
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var company models.Company
+	if err := tx.First(&company, "id = ?", companyID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if !company.Verified {
+		tx.Rollback()
+		return errors.New("this company isn't verified")
+	}
+	if err := models.SetCompanyVerified(tx, companyID, false); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error revoking verification: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package completeness
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"job_board/models"
+)
+
+// a bio shorter than this gets half its points, a line or two says little about anybody
+const bioLength = 50
+
+// skillsWanted is how many skills earn the whole skills weight, each one short of it
+// costs its share
+const skillsWanted = 3
+
+// Suggestion is one thing the owner can add to raise the score, Points is what it's worth
+type Suggestion struct {
+	Section string `json:"section"`
+	Points  int    `json:"points"`
+	Message string `json:"message"`
+}
+
+// Result is a profile's score out of 100 and what's missing from it, the most valuable
+// suggestions come first
+type Result struct {
+	Score       int          `json:"score"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// rule scores one section, earned is out of weight. A rule that isn't fully earned
+// suggests message
+type rule struct {
+	section string
+	weight  int
+	earned  func(profile models.Profile) int
+	message func(profile models.Profile) string
+}
+
+func all(ok bool, weight int) int {
+	if ok {
+		return weight
+	}
+	return 0
+}
+
+// rules add up to 100 so the score reads as a percentage
+var rules = []rule{
+	{
+		section: "bio",
+		weight:  10,
+		earned: func(p models.Profile) int {
+			switch bio := strings.TrimSpace(p.Bio); {
+			case len(bio) >= bioLength:
+				return 10
+			case bio != "":
+				return 5
+			}
+			return 0
+		},
+		message: func(p models.Profile) string {
+			if strings.TrimSpace(p.Bio) == "" {
+				return "Write a short bio about what you do and what you're looking for"
+			}
+			return fmt.Sprintf("Expand your bio to at least %d characters, say what you do and what you're looking for", bioLength)
+		},
+	},
+	{
+		section: "skills",
+		weight:  15,
+		earned: func(p models.Profile) int {
+			if len(p.Skills) >= skillsWanted {
+				return 15
+			}
+			return 15 * len(p.Skills) / skillsWanted
+		},
+		message: func(p models.Profile) string {
+			missing := skillsWanted - len(p.Skills)
+			if missing == 1 {
+				return "Add 1 more skill so employers searching for it can find you"
+			}
+			return fmt.Sprintf("Add %d more skills so employers searching for them can find you", missing)
+		},
+	},
+	{
+		section: "resume",
+		weight:  10,
+		earned:  func(p models.Profile) int { return all(p.ResumeID != nil, 10) },
+		message: func(models.Profile) string { return "Upload your resume" },
+	},
+	{
+		section: "education",
+		weight:  15,
+		earned:  func(p models.Profile) int { return all(len(p.Educations) > 0, 15) },
+		message: func(models.Profile) string { return "Add the school or university you studied at" },
+	},
+	{
+		section: "experience",
+		weight:  15,
+		earned:  func(p models.Profile) int { return all(len(p.InternShipExperiences) > 0, 15) },
+		message: func(models.Profile) string { return "Add a job or internship you've had" },
+	},
+	{
+		section: "projects",
+		weight:  10,
+		earned:  func(p models.Profile) int { return all(len(p.ProjectsExperiences) > 0, 10) },
+		message: func(models.Profile) string { return "Add a project you worked on and what you built" },
+	},
+	{
+		section: "languages",
+		weight:  5,
+		earned:  func(p models.Profile) int { return all(len(p.ProfileLanguages) > 0, 5) },
+		message: func(models.Profile) string { return "Add the languages you speak and how well" },
+	},
+	{
+		section: "work_samples",
+		weight:  5,
+		earned:  func(p models.Profile) int { return all(len(p.WorkSamples) > 0, 5) },
+		message: func(models.Profile) string { return "Link a work sample employers can look at" },
+	},
+	{
+		section: "awards",
+		weight:  5,
+		earned:  func(p models.Profile) int { return all(len(p.Awards) > 0, 5) },
+		message: func(models.Profile) string { return "Add an award or certificate you've earned" },
+	},
+	{
+		section: "social_accounts",
+		weight:  5,
+		earned:  func(p models.Profile) int { return all(len(p.SocialMediaAccounts) > 0, 5) },
+		message: func(models.Profile) string { return "Link a social or professional account such as LinkedIn or GitHub" },
+	},
+	{
+		section: "salary",
+		weight:  5,
+		earned: func(p models.Profile) int {
+			return all(p.ExpectedSalary > 0 && p.ExpectedSalaryCurrencyID != nil, 5)
+		},
+		message: func(models.Profile) string { return "Set the salary you expect and its currency" },
+	},
+}
+
+// Score computes how complete the profile is. The profile needs its educations, experiences,
+// projects, work samples, awards, languages and social accounts loaded
+func Score(profile models.Profile) Result {
+	result := Result{Suggestions: []Suggestion{}}
+	for _, r := range rules {
+		earned := r.earned(profile)
+		result.Score += earned
+		if earned < r.weight {
+			result.Suggestions = append(result.Suggestions, Suggestion{
+				Section: r.section,
+				Points:  r.weight - earned,
+				Message: r.message(profile),
+			})
+		}
+	}
+	sort.SliceStable(result.Suggestions, func(i, j int) bool {
+		return result.Suggestions[i].Points > result.Suggestions[j].Points
+	})
+	return result
+}
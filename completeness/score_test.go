@@ -0,0 +1,127 @@
+package completeness
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"job_board/models"
+)
+
+func fullProfile() models.Profile {
+	resume, currency := uuid.New(), uuid.New()
+	return models.Profile{
+		Bio:                      strings.Repeat("I build payment systems. ", 3),
+		Skills:                   []string{"Go", "PostgreSQL", "Redis"},
+		ResumeID:                 &resume,
+		Educations:               []models.Education{{}},
+		InternShipExperiences:    []models.InternShipExperience{{}},
+		ProjectsExperiences:      []models.ProjectsExperience{{}},
+		ProfileLanguages:         []models.ProfileLanguage{{}},
+		WorkSamples:              []models.WorkSample{{}},
+		Awards:                   []models.Award{{}},
+		SocialMediaAccounts:      []models.SocialMediaAccount{{}},
+		ExpectedSalary:           60000,
+		ExpectedSalaryCurrencyID: &currency,
+	}
+}
+
+func TestRulesAddUpTo100(t *testing.T) {
+	total := 0
+	for _, r := range rules {
+		total += r.weight
+	}
+	if total != 100 {
+		t.Errorf("rule weights add up to %d", total)
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(p *models.Profile)
+		score int
+		// the suggestion for the section the edit took away, if any
+		section string
+		points  int
+	}{
+		{"complete profile", func(p *models.Profile) {}, 100, "", 0},
+		{"short bio", func(p *models.Profile) { p.Bio = "Go developer" }, 95, "bio", 5},
+		{"blank bio", func(p *models.Profile) { p.Bio = "   " }, 90, "bio", 10},
+		{"two skills", func(p *models.Profile) { p.Skills = p.Skills[:2] }, 95, "skills", 5},
+		{"one skill", func(p *models.Profile) { p.Skills = p.Skills[:1] }, 90, "skills", 10},
+		{"more skills than wanted", func(p *models.Profile) { p.Skills = append(p.Skills, "Docker") }, 100, "", 0},
+		{"no resume", func(p *models.Profile) { p.ResumeID = nil }, 90, "resume", 10},
+		{"no education", func(p *models.Profile) { p.Educations = nil }, 85, "education", 15},
+		{"no experience", func(p *models.Profile) { p.InternShipExperiences = nil }, 85, "experience", 15},
+		{"no projects", func(p *models.Profile) { p.ProjectsExperiences = nil }, 90, "projects", 10},
+		{"no languages", func(p *models.Profile) { p.ProfileLanguages = nil }, 95, "languages", 5},
+		{"no work samples", func(p *models.Profile) { p.WorkSamples = nil }, 95, "work_samples", 5},
+		{"no awards", func(p *models.Profile) { p.Awards = nil }, 95, "awards", 5},
+		{"no social accounts", func(p *models.Profile) { p.SocialMediaAccounts = nil }, 95, "social_accounts", 5},
+		{"salary without currency", func(p *models.Profile) { p.ExpectedSalaryCurrencyID = nil }, 95, "salary", 5},
+		{"currency without salary", func(p *models.Profile) { p.ExpectedSalary = 0 }, 95, "salary", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := fullProfile()
+			tt.edit(&profile)
+			result := Score(profile)
+			if result.Score != tt.score {
+				t.Errorf("score %d, want %d: %+v", result.Score, tt.score, result.Suggestions)
+			}
+			if tt.section == "" {
+				if len(result.Suggestions) != 0 {
+					t.Errorf("suggestions for a complete profile: %+v", result.Suggestions)
+				}
+				return
+			}
+			if len(result.Suggestions) != 1 || result.Suggestions[0].Section != tt.section || result.Suggestions[0].Points != tt.points {
+				t.Errorf("suggestions %+v, want %d points of %s", result.Suggestions, tt.points, tt.section)
+			}
+		})
+	}
+}
+
+func TestScoreEmptyProfile(t *testing.T) {
+	result := Score(models.Profile{})
+	if result.Score != 0 {
+		t.Errorf("empty profile scored %d", result.Score)
+	}
+	if len(result.Suggestions) != len(rules) {
+		t.Fatalf("got %d suggestions, want one per rule", len(result.Suggestions))
+	}
+
+	// the most valuable come first, ties keep the order of the rules
+	var sections []string
+	for i, suggestion := range result.Suggestions {
+		sections = append(sections, suggestion.Section)
+		if i > 0 && suggestion.Points > result.Suggestions[i-1].Points {
+			t.Errorf("%s (%d) comes after %s (%d)", suggestion.Section, suggestion.Points, result.Suggestions[i-1].Section, result.Suggestions[i-1].Points)
+		}
+	}
+	want := "skills education experience bio resume projects languages work_samples awards social_accounts salary"
+	if got := strings.Join(sections, " "); got != want {
+		t.Errorf("suggestions in order %s, want %s", got, want)
+	}
+	if msg := result.Suggestions[0].Message; msg != "Add 3 more skills so employers searching for them can find you" {
+		t.Errorf("skills suggestion %q", msg)
+	}
+}
+
+func TestScorePartialProfile(t *testing.T) {
+	currency := uuid.New()
+	profile := models.Profile{
+		Bio:                      "short",
+		Skills:                   []string{"Go"},
+		ResumeID:                 &uuid.UUID{},
+		ExpectedSalary:           1,
+		ExpectedSalaryCurrencyID: &currency,
+		Educations:               []models.Education{{}},
+	}
+	// 5 for the bio, 5 for one skill of three, 10 + 5 + 15
+	if result := Score(profile); result.Score != 40 {
+		t.Errorf("score %d, want 40: %+v", result.Score, result.Suggestions)
+	}
+}
@@ -0,0 +1,214 @@
+package completeness
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"job_board/inbox"
+	"job_board/models"
+	"job_board/notifications"
+)
+
+const (
+	// tickInterval is how often unscored profiles and due reminders are looked for
+	tickInterval = time.Hour
+	backfillSize = 100
+
+	// Complete is the score from which a profile no longer gets reminders
+	Complete = 80
+	// reminders stop this long after sign up whatever the profile looks like
+	reminderWindow = 30 * 24 * time.Hour
+)
+
+// reminderSchedule is when reminders go out, counted from sign up
+var reminderSchedule = []time.Duration{24 * time.Hour, 4 * 24 * time.Hour, 11 * 24 * time.Hour}
+
+var database *gorm.DB
+
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
+}
+
+var refreshQueue = make(chan uuid.UUID, 100)
+
+// Queue rescores the profile once it or one of its sections has been saved
+func Queue(profileID uuid.UUID) {
+	select {
+	case refreshQueue <- profileID:
+	default:
+		go func() { refreshQueue <- profileID }()
+	}
+}
+
+// Start rescores queued profiles in the background, scores the profiles saved before scores
+// were kept and reminds new users whose profile is still missing sections
+func Start() {
+	go func() {
+		for profileID := range refreshQueue {
+			if err := refresh(profileID); err != nil {
+				log.Printf("Failed to refresh the completeness of profile %s: %v", profileID, err)
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			if err := backfill(); err != nil {
+				log.Printf("Failed to score unscored profiles: %v", err)
+			}
+			if err := remind(time.Now()); err != nil {
+				log.Printf("Failed to send profile reminders: %v", err)
+			}
+		}
+	}()
+}
+
+// withSections preloads everything Score reads from a profile
+func withSections(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Educations").
+		Preload("InternShipExperiences").
+		Preload("ProjectsExperiences").
+		Preload("WorkSamples").
+		Preload("Awards").
+		Preload("ProfileLanguages").
+		Preload("SocialMediaAccounts")
+}
+
+func load(profileID uuid.UUID) (models.Profile, Result, error) {
+	var profile models.Profile
+	if err := withSections(database).First(&profile, "id = ?", profileID).Error; err != nil {
+		return profile, Result{}, err
+	}
+	return profile, Score(profile), nil
+}
+
+func refresh(profileID uuid.UUID) error {
+	_, result, err := load(profileID)
+	if err != nil {
+		return err
+	}
+	// UpdateColumn leaves updated_at alone, scoring doesn't change the profile
+	return database.Model(&models.Profile{}).
+		Where("id = ?", profileID).
+		UpdateColumn("completeness", result.Score).Error
+}
+
+func backfill() error {
+	var ids []uuid.UUID
+	if err := database.Model(&models.Profile{}).
+		Where("completeness IS NULL").
+		Limit(backfillSize).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := refresh(id); err != nil {
+			log.Printf("Failed to score profile %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// pending is a new user who may be due a reminder, ProfileID is nil until they make a profile
+type pending struct {
+	ID           uuid.UUID
+	Email        string
+	SubscriberID string
+	CreatedAt    time.Time
+	ProfileID    *uuid.UUID
+	Sent         int
+}
+
+func remind(now time.Time) error {
+	var users []pending
+	if err := database.Model(&models.User{}).
+		Select("users.id, users.email, users.subscriber_id, users.created_at, profiles.id AS profile_id, COALESCE(profile_reminders.sent, 0) AS sent").
+		Joins("LEFT JOIN profiles ON profiles.user_id = users.id AND profiles.deleted_at IS NULL").
+		Joins("LEFT JOIN profile_reminders ON profile_reminders.user_id = users.id").
+		Where("users.role_name = ? AND users.erased_at IS NULL AND users.created_at > ?", models.UserRole, now.Add(-reminderWindow)).
+		// a profile that hasn't been scored yet waits for the backfill
+		Where("profiles.id IS NULL OR profiles.completeness < ?", Complete).
+		Where("COALESCE(profile_reminders.sent, 0) < ?", len(reminderSchedule)).
+		Scan(&users).Error; err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if now.Before(user.CreatedAt.Add(reminderSchedule[user.Sent])) {
+			continue
+		}
+		if err := sendReminder(user, now); err != nil {
+			log.Printf("Failed to send a profile reminder to user %s: %v", user.ID, err)
+		}
+	}
+	return nil
+}
+
+func sendReminder(user pending, now time.Time) error {
+	notification := models.Notification{
+		UserID:   user.ID,
+		Category: models.ProfileReminderCategory,
+		Title:    "Create your profile",
+		Body:     "Employers can only find you and you can only apply once you have a profile",
+		Link:     "/api/v1/users/profiles",
+	}
+	data := map[string]interface{}{
+		"companyName":  "Jobby",
+		"completeness": 0,
+	}
+	if user.ProfileID != nil {
+		_, result, err := load(*user.ProfileID)
+		if err != nil {
+			return err
+		}
+		if result.Score >= Complete {
+			return nil
+		}
+		notification.Title = "Finish your profile"
+		notification.Body = fmt.Sprintf("Your profile is %d%% complete", result.Score)
+		if len(result.Suggestions) > 0 {
+			notification.Body += ". Next: " + result.Suggestions[0].Message
+		}
+		notification.Link = "/api/v1/users/profiles/" + user.ProfileID.String()
+
+		suggestions := make([]string, 0, 3)
+		for i := 0; i < len(result.Suggestions) && i < 3; i++ {
+			suggestions = append(suggestions, result.Suggestions[i].Message)
+		}
+		data["completeness"] = result.Score
+		data["suggestions"] = suggestions
+		data["profileId"] = user.ProfileID
+	}
+
+	return database.Transaction(func(tx *gorm.DB) error {
+		// the count only moves from what was read, a reminder sent in the meantime isn't sent twice
+		result := tx.Exec(`INSERT INTO profile_reminders (user_id, sent, last_sent_at) VALUES (?, 1, ?)
+			ON CONFLICT (user_id) DO UPDATE SET sent = profile_reminders.sent + 1, last_sent_at = EXCLUDED.last_sent_at
+			WHERE profile_reminders.sent = ?`, user.ID, now, user.Sent)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := inbox.Notify(tx, notification); err != nil {
+			return err
+		}
+		return notifications.Enqueue(tx, notifications.Trigger{
+			EventID: "profile-reminder",
+			To: map[string]interface{}{
+				"subscriberId": user.SubscriberID,
+				"email":        user.Email,
+			},
+			Data: data,
+		})
+	})
+}
@@ -0,0 +1,395 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// DefaultEnvFile is read when -env-file isn't given, it is fine for it to be missing
+const DefaultEnvFile = ".env"
+
+// Config is everything the api reads from its environment, it is loaded once in main and
+// handed to the packages that need it
+type Config struct {
+	Port int
+	// public url of the app, used in emailed links
+	AppURL string
+	// MigrateOnStart false leaves migrating to the migrate command, e.g in a release step
+	MigrateOnStart bool
+	// DNSResolver points company verification at a specific server, e.g 1.1.1.1:53
+	DNSResolver string
+	// ExportSigningKey signs data export download links, it is kept apart from the key
+	// access tokens are signed with
+	ExportSigningKey string
+
+	Database      Database
+	Redis         Redis
+	Auth          Auth
+	Admin         Admin
+	Storage       Storage
+	Notifications Notifications
+	Jobs          Jobs
+	Accounts      Accounts
+}
+
+type Database struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+}
+
+type Redis struct {
+	Host     string
+	Password string
+	DB       int
+}
+
+type Auth struct {
+	// SecretKey signs access tokens
+	SecretKey        string
+	RefreshTokenDays int
+	// AdminEmailOTP lets admins with an authenticator app still ask for an emailed code
+	AdminEmailOTP bool
+	Auth0         Auth0
+}
+
+// Auth0 is the social login provider, social login is off when Domain is empty
+type Auth0 struct {
+	Domain            string
+	ClientID          string
+	ClientSecret      string
+	CallbackURL       string
+	TokenClientID     string
+	TokenClientSecret string
+	TokenAudience     string
+}
+
+func (a Auth0) Enabled() bool {
+	return a.Domain != ""
+}
+
+// Admin is the super admin created on the first start, none is created when Email is empty
+type Admin struct {
+	Name         string
+	Email        string
+	Password     string
+	Picture      string
+	MobileNumber string
+}
+
+func (a Admin) Enabled() bool {
+	return a.Email != ""
+}
+
+type Storage struct {
+	// Driver is local or s3
+	Driver    string
+	MaxSize   int64
+	LocalRoot string
+	S3        S3
+}
+
+type S3 struct {
+	// Endpoint is left empty for AWS
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle is needed by minio and most self hosted servers
+	PathStyle bool
+}
+
+type Notifications struct {
+	// Provider is novu, smtp or log, it defaults to novu when NOVU_API_KEY is set and
+	// log otherwise
+	Provider   string
+	NovuAPIKey string
+	SMTP       SMTP
+	// LogFile is where the log provider writes, empty writes to the api's log
+	LogFile string
+}
+
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type Jobs struct {
+	// ExpiryDays is how long a job stays published
+	ExpiryDays int
+	// ExpiryNoticeDays is how long before expiry the poster hears about it
+	ExpiryNoticeDays int
+}
+
+type Accounts struct {
+	// ErasureGraceDays is how long a deleted account can be reinstated before it is erased
+	ErasureGraceDays int
+}
+
+// Default is the config before anything is read
+func Default() Config {
+	return Config{
+		Port:           3000,
+		MigrateOnStart: true,
+		Database: Database{
+			Port:    5432,
+			SSLMode: "require",
+		},
+		Auth: Auth{
+			RefreshTokenDays: 30,
+			AdminEmailOTP:    true,
+		},
+		Storage: Storage{
+			Driver:    "local",
+			MaxSize:   8 << 20,
+			LocalRoot: "./uploads",
+		},
+		Notifications: Notifications{
+			SMTP: SMTP{Port: 587},
+		},
+		Jobs: Jobs{
+			ExpiryDays:       30,
+			ExpiryNoticeDays: 3,
+		},
+		Accounts: Accounts{
+			ErasureGraceDays: 90,
+		},
+	}
+}
+
+// Load builds the config from the defaults, the env file, the environment and the flags,
+// each overriding the one before. It returns the arguments left after the flags, e.g the
+// migrate subcommand
+func Load(args []string) (*Config, []string, error) {
+	flags := flag.NewFlagSet("job_board", flag.ContinueOnError)
+	envFile := flags.String("env-file", DefaultEnvFile, "file to read environment variables from")
+	port := flags.Int("port", 0, "port to listen on, overrides PORT")
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	if err := godotenv.Load(*envFile); err != nil && (set["env-file"] || !errors.Is(err, os.ErrNotExist)) {
+		return nil, nil, fmt.Errorf("error loading %s: %w", *envFile, err)
+	}
+
+	cfg := Default()
+	if err := cfg.readEnv(); err != nil {
+		return nil, nil, err
+	}
+	if set["port"] {
+		cfg.Port = *port
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return &cfg, flags.Args(), nil
+}
+
+// env reads the variables into a config, the first bad value is kept in err
+type env struct {
+	err error
+}
+
+func (e *env) setString(name string, into *string) {
+	if value, ok := os.LookupEnv(name); ok && value != "" {
+		*into = value
+	}
+}
+
+func (e *env) setInt(name string, into *int) {
+	var raw string
+	e.setString(name, &raw)
+	if raw == "" {
+		return
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		e.fail(fmt.Errorf("%s has to be a number", name))
+		return
+	}
+	*into = value
+}
+
+func (e *env) setInt64(name string, into *int64) {
+	var raw string
+	e.setString(name, &raw)
+	if raw == "" {
+		return
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		e.fail(fmt.Errorf("%s has to be a number", name))
+		return
+	}
+	*into = value
+}
+
+func (e *env) setBool(name string, into *bool) {
+	var raw string
+	e.setString(name, &raw)
+	if raw == "" {
+		return
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		e.fail(fmt.Errorf("%s has to be true or false", name))
+		return
+	}
+	*into = value
+}
+
+func (e *env) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (c *Config) readEnv() error {
+	e := &env{}
+	e.setInt("PORT", &c.Port)
+	e.setString("APP_URL", &c.AppURL)
+	e.setBool("MIGRATE_ON_START", &c.MigrateOnStart)
+	e.setString("DNS_RESOLVER", &c.DNSResolver)
+	e.setString("EXPORT_SIGNING_KEY", &c.ExportSigningKey)
+
+	e.setString("DB_HOST", &c.Database.Host)
+	e.setInt("DB_PORT", &c.Database.Port)
+	e.setString("DB_USER", &c.Database.User)
+	e.setString("DB_PASSWORD", &c.Database.Password)
+	e.setString("DB_NAME", &c.Database.Name)
+	e.setString("DB_SSLMODE", &c.Database.SSLMode)
+
+	e.setString("REDIS_HOST", &c.Redis.Host)
+	e.setString("REDIS_PASSWORD", &c.Redis.Password)
+	e.setInt("REDIS_DB", &c.Redis.DB)
+
+	e.setString("SECRET_KEY", &c.Auth.SecretKey)
+	e.setInt("REFRESH_TOKEN_DAYS", &c.Auth.RefreshTokenDays)
+	e.setBool("ADMIN_EMAIL_OTP", &c.Auth.AdminEmailOTP)
+	e.setString("AUTH0_DOMAIN", &c.Auth.Auth0.Domain)
+	e.setString("AUTH0_CLIENT_ID", &c.Auth.Auth0.ClientID)
+	e.setString("AUTH0_CLIENT_SECRET", &c.Auth.Auth0.ClientSecret)
+	e.setString("AUTH0_CALLBACK_URL", &c.Auth.Auth0.CallbackURL)
+	e.setString("TOKEN_CLIENT_ID", &c.Auth.Auth0.TokenClientID)
+	e.setString("TOKEN_CLIENT_SECRET", &c.Auth.Auth0.TokenClientSecret)
+	e.setString("TOKEN_AUDIENCE", &c.Auth.Auth0.TokenAudience)
+
+	e.setString("ADMIN_NAME", &c.Admin.Name)
+	e.setString("ADMIN_EMAIL", &c.Admin.Email)
+	e.setString("ADMIN_PASSWORD", &c.Admin.Password)
+	e.setString("ADMIN_PICTURE", &c.Admin.Picture)
+	e.setString("ADMIN_MOBILE_NUMBER", &c.Admin.MobileNumber)
+
+	e.setString("STORAGE_DRIVER", &c.Storage.Driver)
+	e.setInt64("STORAGE_MAX_SIZE", &c.Storage.MaxSize)
+	e.setString("STORAGE_LOCAL_ROOT", &c.Storage.LocalRoot)
+	e.setString("S3_ENDPOINT", &c.Storage.S3.Endpoint)
+	e.setString("S3_REGION", &c.Storage.S3.Region)
+	e.setString("S3_BUCKET", &c.Storage.S3.Bucket)
+	e.setString("S3_ACCESS_KEY", &c.Storage.S3.AccessKey)
+	e.setString("S3_SECRET_KEY", &c.Storage.S3.SecretKey)
+	e.setBool("S3_PATH_STYLE", &c.Storage.S3.PathStyle)
+
+	e.setString("NOTIFICATION_PROVIDER", &c.Notifications.Provider)
+	e.setString("NOVU_API_KEY", &c.Notifications.NovuAPIKey)
+	e.setString("SMTP_HOST", &c.Notifications.SMTP.Host)
+	e.setInt("SMTP_PORT", &c.Notifications.SMTP.Port)
+	e.setString("SMTP_USERNAME", &c.Notifications.SMTP.Username)
+	e.setString("SMTP_PASSWORD", &c.Notifications.SMTP.Password)
+	e.setString("SMTP_FROM", &c.Notifications.SMTP.From)
+	e.setString("NOTIFICATION_LOG_FILE", &c.Notifications.LogFile)
+	if c.Notifications.Provider == "" {
+		c.Notifications.Provider = "log"
+		if c.Notifications.NovuAPIKey != "" {
+			c.Notifications.Provider = "novu"
+		}
+	}
+
+	e.setInt("JOB_EXPIRY_DAYS", &c.Jobs.ExpiryDays)
+	e.setInt("JOB_EXPIRY_NOTICE_DAYS", &c.Jobs.ExpiryNoticeDays)
+	e.setInt("ACCOUNT_ERASURE_GRACE_DAYS", &c.Accounts.ErasureGraceDays)
+	return e.err
+}
+
+// Validate lists every missing or invalid setting at once
+func (c Config) Validate() error {
+	var problems []string
+	check := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+
+	check(c.Port > 0 && c.Port < 65536, "PORT has to be between 1 and 65535")
+
+	check(c.Database.Host != "", "DB_HOST is required")
+	check(c.Database.User != "", "DB_USER is required")
+	check(c.Database.Password != "", "DB_PASSWORD is required")
+	check(c.Database.Name != "", "DB_NAME is required")
+	check(c.Database.Port > 0 && c.Database.Port < 65536, "DB_PORT has to be between 1 and 65535")
+
+	check(c.Redis.Host != "", "REDIS_HOST is required")
+	check(c.Redis.DB >= 0, "REDIS_DB can't be negative")
+
+	check(c.Auth.SecretKey != "", "SECRET_KEY is required")
+	check(c.ExportSigningKey != "", "EXPORT_SIGNING_KEY is required")
+	check(c.ExportSigningKey == "" || c.ExportSigningKey != c.Auth.SecretKey, "EXPORT_SIGNING_KEY can't be the same as SECRET_KEY")
+	check(c.Auth.RefreshTokenDays > 0, "REFRESH_TOKEN_DAYS has to be positive")
+	if c.Auth.Auth0.Enabled() {
+		check(c.Auth.Auth0.ClientID != "" && c.Auth.Auth0.ClientSecret != "" && c.Auth.Auth0.CallbackURL != "",
+			"AUTH0_CLIENT_ID, AUTH0_CLIENT_SECRET and AUTH0_CALLBACK_URL are required with AUTH0_DOMAIN")
+	}
+
+	if c.Admin.Enabled() {
+		check(c.Admin.Name != "" && c.Admin.Password != "" && c.Admin.Picture != "" && c.Admin.MobileNumber != "",
+			"ADMIN_NAME, ADMIN_PASSWORD, ADMIN_PICTURE and ADMIN_MOBILE_NUMBER are required with ADMIN_EMAIL")
+	}
+
+	check(c.Storage.MaxSize > 0, "STORAGE_MAX_SIZE has to be positive")
+	switch c.Storage.Driver {
+	case "local":
+		check(c.Storage.LocalRoot != "", "STORAGE_LOCAL_ROOT is required by the local driver")
+	case "s3":
+		check(c.Storage.S3.Bucket != "", "S3_BUCKET is required by the s3 driver")
+		check(c.Storage.S3.AccessKey != "" && c.Storage.S3.SecretKey != "", "S3_ACCESS_KEY and S3_SECRET_KEY are required by the s3 driver")
+	default:
+		problems = append(problems, "STORAGE_DRIVER has to be local or s3")
+	}
+
+	switch c.Notifications.Provider {
+	case "novu":
+		check(c.Notifications.NovuAPIKey != "", "NOVU_API_KEY is required by the novu provider")
+	case "smtp":
+		check(c.Notifications.SMTP.Host != "" && c.Notifications.SMTP.From != "", "SMTP_HOST and SMTP_FROM are required by the smtp provider")
+		check(c.Notifications.SMTP.Port > 0 && c.Notifications.SMTP.Port < 65536, "SMTP_PORT has to be between 1 and 65535")
+	case "log":
+	default:
+		problems = append(problems, "NOTIFICATION_PROVIDER has to be novu, smtp or log")
+	}
+
+	check(c.Jobs.ExpiryDays > 0, "JOB_EXPIRY_DAYS has to be positive")
+	check(c.Jobs.ExpiryNoticeDays > 0, "JOB_EXPIRY_NOTICE_DAYS has to be positive")
+	check(c.Accounts.ErasureGraceDays >= 0, "ACCOUNT_ERASURE_GRACE_DAYS can't be negative")
+
+	if len(problems) > 0 {
+		return errors.New("invalid config: " + strings.Join(problems, ", "))
+	}
+	return nil
+}
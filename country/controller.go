@@ -8,6 +8,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 func CreateCountry(ctx *gin.Context) {
@@ -58,7 +59,16 @@ func GetCountry(ctx *gin.Context) {
 		Name: ctx.Query("name"),
 	}
 
-	resp, total, page, perPage, err := getProject(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	resp, err := getProject(filter, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -70,12 +80,8 @@ func GetCountry(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched countries",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     resp,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       resp,
+		Links:      resp.Links(ctx),
 	})
 }
 
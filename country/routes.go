@@ -8,14 +8,12 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-
 func CountryRoutes(superRoute *gin.RouterGroup) {
 	countryRouter := superRoute.Group("/countries")
 
-	countryRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), CreateCountry)
+	countryRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), CreateCountry)
 	countryRouter.GET("/", GetCountry)
 	countryRouter.GET("/:id", GetSingleCountry)
-	countryRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), UpdateCountry)
-	countryRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), DeleteCountry)
+	countryRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), UpdateCountry)
+	countryRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), DeleteCountry)
 }
\ No newline at end of file
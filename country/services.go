@@ -3,19 +3,18 @@ package country
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func createProject(project models.Country, user models.User) (*models.Country, error) {
@@ -39,26 +38,7 @@ func createProject(project models.Country, user models.User) (*models.Country, e
 	return &project, nil
 }
 
-func getProject(filter Request, pageSize string, pageNumber string) ([]models.Country, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getProject(filter Request, params pagination.Params) (*pagination.Page[models.Country], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Country{})
 
@@ -67,25 +47,13 @@ func getProject(filter Request, pageSize string, pageNumber string) ([]models.Co
 		db = db.Where("name LIKE ?", "%"+filter.Name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.Country
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.Country](db, params)
+	if err != nil {
 		log.Println("Error finding project:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleProject(ID uuid.UUID) (*models.Country, error) {
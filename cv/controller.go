@@ -0,0 +1,115 @@
+package cv
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"job_board/helpers"
+	"job_board/models"
+	"job_board/policy"
+	"job_board/profile"
+)
+
+func ProfileCV(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	view, err := profile.ViewProfile(ID, *user)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, profile.ErrProfileNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, policy.ErrForbidden):
+			status = http.StatusForbidden
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
+	serve(ctx, *view)
+}
+
+func publicCV(ctx *gin.Context) {
+	view, err := profile.PublicProfile(ctx.Param("slug"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, profile.ErrProfileNotFound) {
+			status = http.StatusNotFound
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
+	serve(ctx, *view)
+}
+
+// serve renders the cv in the format and template the query asks for, a client that
+// already has this version gets a 304
+func serve(ctx *gin.Context, view profile.ProfileView) {
+	out, err := render(newDocument(view), ctx.DefaultQuery("template", defaultTemplate), ctx.DefaultQuery("format", "pdf"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		var unknown ErrUnknownOption
+		if errors.As(err, &unknown) {
+			status = http.StatusBadRequest
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
+	ctx.Header("ETag", out.ETag)
+	ctx.Header("Cache-Control", "private, no-cache")
+	if ctx.GetHeader("If-None-Match") == out.ETag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	disposition := "inline"
+	if ctx.Query("download") == "true" {
+		disposition = "attachment"
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, out.Filename))
+	ctx.Data(http.StatusOK, out.ContentType, out.Body)
+}
+
+func getTemplates(ctx *gin.Context) {
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched cv templates",
+		StatusCode: http.StatusOK,
+		Data:       templateList(),
+	})
+}
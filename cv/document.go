@@ -0,0 +1,142 @@
+package cv
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"job_board/profile"
+)
+
+// Document is a cv before it is rendered, every format and template draws the same one
+type Document struct {
+	ProfileID uuid.UUID `json:"profile_id"`
+	Name      string    `json:"name"`
+	Contact   []string  `json:"contact"`
+	Summary   string    `json:"summary"`
+	Skills    []string  `json:"skills"`
+	Sections  []Section `json:"sections"`
+}
+
+type Section struct {
+	Title   string  `json:"title"`
+	Entries []Entry `json:"entries"`
+}
+
+type Entry struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Period   string `json:"period"`
+	Body     string `json:"body"`
+	Link     string `json:"link"`
+	// sortKey puts the latest entries first
+	sortKey time.Time
+}
+
+// newDocument builds the cv from what the viewer may see of the profile, hidden sections
+// stay out of the cv as well
+func newDocument(view profile.ProfileView) Document {
+	doc := Document{
+		ProfileID: view.ID,
+		Name:      view.Name,
+		Summary:   strings.TrimSpace(view.Bio),
+		Skills:    view.Skills,
+	}
+	if view.Email != "" {
+		doc.Contact = append(doc.Contact, view.Email)
+	}
+	if view.MobileNumber != nil && *view.MobileNumber != "" {
+		doc.Contact = append(doc.Contact, *view.MobileNumber)
+	}
+	for _, account := range view.SocialMediaAccounts {
+		doc.Contact = append(doc.Contact, account.Link)
+	}
+
+	var education, experience, projects, samples, awards, languages []Entry
+	for _, e := range view.Educations {
+		title := e.Degree.Name
+		if e.FieldOFStudy != "" {
+			title = strings.TrimSpace(title + " in " + e.FieldOFStudy)
+		}
+		subtitle := e.InstitutionName
+		if e.AcademicRanking.Name != "" {
+			subtitle += ", " + e.AcademicRanking.Name
+		}
+		period := period(e.StartDate, e.EndDate, e.IsCurrent)
+		if period == "" && e.GraduationYear > 0 {
+			period = strconv.Itoa(e.GraduationYear)
+		}
+		education = append(education, Entry{Title: title, Subtitle: subtitle, Period: period, sortKey: e.StartDate})
+	}
+	for _, e := range view.Internships {
+		experience = append(experience, Entry{
+			Title:    e.Title,
+			Subtitle: e.CompanyName,
+			Period:   period(e.StartDate, e.EndDate, e.IsCurrent),
+			Body:     e.Description,
+			sortKey:  e.StartDate,
+		})
+	}
+	for _, e := range view.Projects {
+		projects = append(projects, Entry{
+			Title:    e.ProjectName,
+			Subtitle: e.Title,
+			Period:   period(e.StartDate, e.EndDate, nil),
+			Body:     e.Description,
+			Link:     e.Link,
+			sortKey:  e.StartDate,
+		})
+	}
+	for _, e := range view.WorkSamples {
+		samples = append(samples, Entry{Title: e.Description, Link: e.Link, sortKey: e.CreatedAt})
+	}
+	for _, e := range view.Awards {
+		awards = append(awards, Entry{
+			Title:   e.Title,
+			Period:  strconv.Itoa(e.Year),
+			Body:    e.Description,
+			sortKey: time.Date(e.Year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	for _, e := range view.Languages {
+		name := e.Language.Name
+		if name == "" {
+			name = e.Name
+		}
+		languages = append(languages, Entry{Title: name, Subtitle: e.LanguageProficiency.Name})
+	}
+
+	for _, section := range []Section{
+		{"Experience", experience},
+		{"Education", education},
+		{"Projects", projects},
+		{"Work samples", samples},
+		{"Awards", awards},
+		{"Languages", languages},
+	} {
+		if len(section.Entries) == 0 {
+			continue
+		}
+		sort.SliceStable(section.Entries, func(i, j int) bool {
+			return section.Entries[i].sortKey.After(section.Entries[j].sortKey)
+		})
+		doc.Sections = append(doc.Sections, section)
+	}
+	return doc
+}
+
+func period(start time.Time, end *time.Time, current *bool) string {
+	if start.IsZero() {
+		return ""
+	}
+	switch {
+	case current != nil && *current:
+		return start.Format("Jan 2006") + " – Present"
+	case end != nil && !end.IsZero():
+		return start.Format("Jan 2006") + " – " + end.Format("Jan 2006")
+	}
+	return start.Format("Jan 2006")
+}
@@ -0,0 +1,201 @@
+package cv
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+type pdfFont int
+
+const (
+	regularFont pdfFont = iota
+	boldFont
+	italicFont
+)
+
+// the three fonts are pdf standard fonts, readers have them so nothing is embedded
+var fontNames = [...]string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+
+// A4 in points
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+type color [3]float64
+
+var (
+	black = color{0.1, 0.1, 0.1}
+	grey  = color{0.42, 0.42, 0.42}
+)
+
+// pdf lays lines out from the top of the page down and starts a new page whenever the next
+// one doesn't fit
+type pdf struct {
+	margin float64
+	pages  []*bytes.Buffer
+	// y is the baseline of the next line, pdf coordinates start at the bottom of the page
+	y float64
+}
+
+func newPDF(margin float64) *pdf {
+	p := &pdf{margin: margin}
+	p.newPage()
+	return p
+}
+
+func (p *pdf) newPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+	p.y = pageHeight - p.margin
+}
+
+func (p *pdf) content() *bytes.Buffer {
+	return p.pages[len(p.pages)-1]
+}
+
+// ensure starts a new page unless height fits above the bottom margin
+func (p *pdf) ensure(height float64) {
+	if p.y-height < p.margin {
+		p.newPage()
+	}
+}
+
+func (p *pdf) space(height float64) {
+	p.y -= height
+}
+
+// text writes one line at the current baseline without moving down
+func (p *pdf) text(x float64, font pdfFont, size float64, c color, text []byte) {
+	writeText(p.content(), x, p.y, font, size, c, text)
+}
+
+// footers puts a right aligned line under the bottom margin of every page once the layout
+// is done and the page count is known
+func (p *pdf) footers(font pdfFont, size float64, c color, label func(page int, pages int) string) {
+	for i, page := range p.pages {
+		text := encode(label(i+1, len(p.pages)))
+		writeText(page, pageWidth-p.margin-textWidth(text, font, size), p.margin/2, font, size, c, text)
+	}
+}
+
+func writeText(content *bytes.Buffer, x float64, y float64, font pdfFont, size float64, c color, text []byte) {
+	fmt.Fprintf(content, "BT /F%d %.2f Tf %.3f %.3f %.3f rg %.2f %.2f Td (%s) Tj ET\n",
+		font+1, size, c[0], c[1], c[2], x, y, escape(text))
+}
+
+// rule draws a line across the text column at the current baseline
+func (p *pdf) rule(width float64, c color) {
+	fmt.Fprintf(p.content(), "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		c[0], c[1], c[2], width, p.margin, p.y, pageWidth-p.margin, p.y)
+}
+
+// paragraph wraps text to the width, keeping the line breaks it already has
+func (p *pdf) paragraph(x float64, width float64, font pdfFont, size float64, leading float64, c color, text string) {
+	for _, block := range strings.Split(text, "\n") {
+		for _, line := range wrap(encode(block), font, size, width) {
+			p.ensure(leading)
+			p.space(leading)
+			p.text(x, font, size, c, line)
+		}
+	}
+}
+
+// wrap breaks text into lines no wider than width, words longer than a line are cut
+func wrap(text []byte, font pdfFont, size float64, width float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+	for _, word := range bytes.Fields(text) {
+		candidate := word
+		if len(line) > 0 {
+			candidate = append(append(append([]byte{}, line...), ' '), word...)
+		}
+		if textWidth(candidate, font, size) <= width {
+			line = candidate
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		for textWidth(word, font, size) > width && len(word) > 1 {
+			cut := len(word) - 1
+			for cut > 1 && textWidth(word[:cut], font, size) > width {
+				cut--
+			}
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+		}
+		line = word
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func escape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// output writes out the document. Nothing in it depends on the time it was made, the same
+// content always gives the same file
+func (p *pdf) output(title string) ([]byte, error) {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// catalog, page tree, the fonts and the info dictionary come first, then a page and
+	// its content stream for every page
+	const firstPage = 7
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	for _, name := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	object(fmt.Sprintf("<< /Title (%s) >>", escape(encode(title))))
+
+	for i, page := range p.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes(), nil
+}
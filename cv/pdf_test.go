@@ -0,0 +1,177 @@
+package cv
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want string
+	}{
+		{[]byte("Ada Okafor"), "Ada Okafor"},
+		{[]byte("Go (Gin)"), `Go \(Gin\)`},
+		{[]byte(`C:\path`), `C:\\path`},
+		{[]byte(")("), `\)\(`},
+		{[]byte("a\nb\tc"), `a\012b\011c`},
+		{[]byte{'c', 'a', 'f', 0xe9}, `caf\351`},
+		{[]byte{0x96, 0x7f}, `\226\177`},
+		{[]byte{}, ""},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []byte
+	}{
+		{"plain", []byte("plain")},
+		{"café", []byte{'c', 'a', 'f', 0xe9}},
+		{"2019 – 2021 €", []byte{'2', '0', '1', '9', ' ', 0x96, ' ', '2', '0', '2', '1', ' ', 0x80}},
+		{"tab\there", []byte("tab here")},
+		{"日本", []byte("??")},
+		{"bell\a", []byte("bell")},
+	}
+	for _, tt := range tests {
+		if got := encode(tt.in); !bytes.Equal(got, tt.want) {
+			t.Errorf("encode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	// H 722, i 222 at 10 points, bold i is wider
+	if got := textWidth([]byte("Hi"), regularFont, 10); got != 9.44 {
+		t.Errorf("regular width %v, want 9.44", got)
+	}
+	if got := textWidth([]byte("Hi"), boldFont, 10); got != 10 {
+		t.Errorf("bold width %v, want 10", got)
+	}
+	if textWidth([]byte("Hi"), italicFont, 10) != textWidth([]byte("Hi"), regularFont, 10) {
+		t.Error("oblique doesn't share the regular widths")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width float64
+		want  []string
+	}{
+		{"fits on one line", "one two three", 500, []string{"one two three"}},
+		{"breaks between words", "one two three four", 60, []string{"one two", "three four"}},
+		{"collapses spaces", "  one   two  ", 500, []string{"one two"}},
+		{"empty", "", 100, nil},
+		{"cuts long words", "abcdefghijkl", 30, []string{"abcde", "fghijkl"}},
+		{"cut word carries on the line", "abcdefghijkl mn", 30, []string{"abcde", "fghijkl", "mn"}},
+		{"a character wider than the line", "WW", 5, []string{"W", "W"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := wrap([]byte(tt.text), regularFont, 10, tt.width)
+			var got []string
+			for _, line := range lines {
+				got = append(got, string(line))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("wrap(%q, %v) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapKeepsLinesInsideTheWidth(t *testing.T) {
+	text := []byte(strings.Repeat("Built the settlement service in Go and cut payout latency by 40% ", 20) + strings.Repeat("x", 200))
+	for _, width := range []float64{50, 120, 483.28} {
+		lines := wrap(text, boldFont, 10.5, width)
+		var words []string
+		for _, line := range lines {
+			if w := textWidth(line, boldFont, 10.5); w > width {
+				t.Errorf("line %q is %v wide, more than %v", line, w, width)
+			}
+			words = append(words, string(line))
+		}
+		// nothing is lost, long words are only cut
+		if got, want := strings.ReplaceAll(strings.Join(words, ""), " ", ""), strings.ReplaceAll(string(text), " ", ""); got != want {
+			t.Errorf("wrapping at %v lost text", width)
+		}
+	}
+}
+
+var objectHeader = regexp.MustCompile(`^(\d+) 0 obj\n`)
+
+func TestRenderPDF(t *testing.T) {
+	doc := Document{
+		Name:    "Ada (The) Okafor",
+		Contact: []string{"ada@example.com", "+234 803 555 0199"},
+		Summary: strings.Repeat("Backend engineer who likes boring, reliable systems. ", 10),
+		Skills:  []string{"Go", "PostgreSQL"},
+	}
+	// enough entries to run over several pages
+	section := Section{Title: "Experience"}
+	for i := 0; i < 60; i++ {
+		section.Entries = append(section.Entries, Entry{
+			Title:    fmt.Sprintf("Engineer %d", i),
+			Subtitle: "Paystack",
+			Period:   "Jan 2021 – Present",
+			Body:     "Built the settlement service\nCut payout latency by 40%",
+		})
+	}
+	doc.Sections = []Section{section}
+
+	for _, tmpl := range templateList() {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			out, err := renderPDF(doc, tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+				t.Fatal("not framed as a pdf")
+			}
+
+			// every xref entry points at the object it numbers
+			m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+			if m == nil {
+				t.Fatal("no startxref")
+			}
+			xref, _ := strconv.Atoi(string(m[1]))
+			table := strings.Split(string(out[xref:]), "\n")
+			if table[0] != "xref" {
+				t.Fatalf("startxref points at %q", table[0])
+			}
+			var count int
+			fmt.Sscanf(table[1], "0 %d", &count)
+			for i := 1; i < count; i++ {
+				offset, _ := strconv.Atoi(table[2+i][:10])
+				header := objectHeader.FindSubmatch(out[offset:])
+				if header == nil || string(header[1]) != strconv.Itoa(i) {
+					t.Errorf("xref entry %d points at %q", i, out[offset:offset+10])
+				}
+			}
+
+			pages := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(out)
+			if pages == nil || string(pages[1]) == "1" {
+				t.Errorf("60 entries fit on one page: %s", pages)
+			}
+			if !bytes.Contains(out, []byte(`/Title (Ada \(The\) Okafor - CV)`)) {
+				t.Error("title isn't escaped in the info dictionary")
+			}
+
+			again, _ := renderPDF(doc, tmpl)
+			if !bytes.Equal(out, again) {
+				t.Error("rendering the same document twice gave different files")
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package cv
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+func heading(title string, t Template) string {
+	if t.upperHeadings {
+		return strings.ToUpper(title)
+	}
+	return title
+}
+
+func renderPDF(doc Document, t Template) ([]byte, error) {
+	p := newPDF(t.margin)
+	width := pageWidth - 2*t.margin
+	line := t.fontSize * t.leading
+	small := t.fontSize * 0.9
+
+	p.space(t.nameSize)
+	p.text(t.margin, boldFont, t.nameSize, t.accent, encode(doc.Name))
+	p.space(t.nameSize * 0.3)
+	if len(doc.Contact) > 0 {
+		p.paragraph(t.margin, width, regularFont, small, small*t.leading, grey, strings.Join(doc.Contact, "  ·  "))
+	}
+	if t.rules {
+		p.space(t.fontSize * 0.8)
+		p.rule(1, t.accent)
+	}
+
+	section := func(title string) {
+		// a heading is never left alone at the bottom of a page
+		p.ensure(t.sectionGap + t.fontSize*1.2 + 3*line)
+		p.space(t.sectionGap + t.fontSize*1.2)
+		p.text(t.margin, boldFont, t.fontSize*1.2, t.accent, encode(heading(title, t)))
+		if t.rules {
+			p.space(t.fontSize * 0.4)
+			p.rule(0.5, grey)
+		}
+		p.space(t.fontSize * 0.3)
+	}
+
+	if doc.Summary != "" {
+		section("Summary")
+		p.paragraph(t.margin, width, regularFont, t.fontSize, line, black, doc.Summary)
+	}
+	if len(doc.Skills) > 0 {
+		section("Skills")
+		p.paragraph(t.margin, width, regularFont, t.fontSize, line, black, strings.Join(doc.Skills, ", "))
+	}
+
+	for _, s := range doc.Sections {
+		section(s.Title)
+		for i, entry := range s.Entries {
+			if i > 0 {
+				p.space(t.fontSize * 0.5)
+			}
+			period := encode(entry.Period)
+			periodWidth := textWidth(period, regularFont, small)
+			title := wrap(encode(entry.Title), boldFont, t.fontSize, width-periodWidth-12)
+			p.ensure(2 * line)
+			for j, text := range title {
+				p.space(line)
+				p.text(t.margin, boldFont, t.fontSize, black, text)
+				if j == 0 && len(period) > 0 {
+					p.text(pageWidth-t.margin-periodWidth, regularFont, small, grey, period)
+				}
+			}
+			if len(title) == 0 && len(period) > 0 {
+				p.space(line)
+				p.text(t.margin, regularFont, small, grey, period)
+			}
+			if entry.Subtitle != "" {
+				p.paragraph(t.margin, width, italicFont, t.fontSize, line, grey, entry.Subtitle)
+			}
+			if entry.Body != "" {
+				p.paragraph(t.margin, width, regularFont, t.fontSize, line, black, entry.Body)
+			}
+			if entry.Link != "" {
+				p.paragraph(t.margin, width, regularFont, small, line, t.accent, entry.Link)
+			}
+		}
+	}
+
+	if len(p.pages) > 1 {
+		p.footers(regularFont, small, grey, func(page int, pages int) string {
+			return fmt.Sprintf("%s  ·  page %d of %d", doc.Name, page, pages)
+		})
+	}
+	return p.output(doc.Name + " - CV")
+}
+
+var htmlTemplate = template.Must(template.New("cv").Funcs(template.FuncMap{
+	"heading": heading,
+	"join":    strings.Join,
+	"lines":   func(text string) []string { return strings.Split(text, "\n") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Doc.Name}} - CV</title>
+<style>
+body { font-family: {{.Template.FontFamily}}; font-size: {{.Template.FontSize}}pt; line-height: {{.Template.Leading}}; color: #1a1a1a; max-width: 48rem; margin: 2rem auto; padding: 0 1.5rem; }
+h1 { color: {{.Template.Accent}}; font-size: {{.Template.NameSize}}pt; margin: 0 0 .25rem; }
+h2 { color: {{.Template.Accent}}; font-size: 1.2em; margin: {{.Template.SectionGap}}pt 0 .4rem;{{if .Template.Rules}} border-bottom: 1px solid #999; padding-bottom: .15rem;{{end}}{{if .Template.UpperHeadings}} letter-spacing: .06em;{{end}} }
+.contact, .period, .subtitle { color: #6b6b6b; }
+.contact { font-size: .9em; }
+.entry { margin-bottom: .6rem; }
+.entry-head { display: flex; justify-content: space-between; gap: 1rem; }
+.title { font-weight: bold; }
+.period { font-size: .9em; white-space: nowrap; }
+.subtitle { font-style: italic; }
+p { margin: 0; }
+a { color: {{.Template.Accent}}; }
+@media print { body { margin: 0; max-width: none; } }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Doc.Name}}</h1>
+{{- if .Doc.Contact}}
+<div class="contact">{{join .Doc.Contact " · "}}</div>
+{{- end}}
+</header>
+{{- if .Doc.Summary}}
+<section>
+<h2>{{heading "Summary" .Layout}}</h2>
+{{- range lines .Doc.Summary}}
+<p>{{.}}</p>
+{{- end}}
+</section>
+{{- end}}
+{{- if .Doc.Skills}}
+<section>
+<h2>{{heading "Skills" .Layout}}</h2>
+<p>{{join .Doc.Skills ", "}}</p>
+</section>
+{{- end}}
+{{- range .Doc.Sections}}
+<section>
+<h2>{{heading .Title $.Layout}}</h2>
+{{- range .Entries}}
+<div class="entry">
+<div class="entry-head"><span class="title">{{.Title}}</span>{{if .Period}}<span class="period">{{.Period}}</span>{{end}}</div>
+{{- if .Subtitle}}
+<div class="subtitle">{{.Subtitle}}</div>
+{{- end}}
+{{- range lines .Body}}{{if .}}
+<p>{{.}}</p>
+{{- end}}{{end}}
+{{- if .Link}}
+<div><a href="{{.Link}}" rel="nofollow noopener">{{.Link}}</a></div>
+{{- end}}
+</div>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlStyle is what the html template needs to know of a template
+type htmlStyle struct {
+	FontFamily    template.CSS
+	FontSize      float64
+	NameSize      float64
+	Leading       float64
+	SectionGap    float64
+	Accent        template.CSS
+	Rules         bool
+	UpperHeadings bool
+}
+
+func renderHTML(doc Document, t Template) ([]byte, error) {
+	var out bytes.Buffer
+	err := htmlTemplate.Execute(&out, struct {
+		Doc      Document
+		Layout   Template
+		Template htmlStyle
+	}{
+		Doc:    doc,
+		Layout: t,
+		Template: htmlStyle{
+			FontFamily:    template.CSS(t.fontFamily),
+			FontSize:      t.fontSize,
+			NameSize:      t.nameSize,
+			Leading:       t.leading,
+			SectionGap:    t.sectionGap,
+			Accent:        template.CSS(t.accentHex),
+			Rules:         t.rules,
+			UpperHeadings: t.upperHeadings,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`,
+)
+
+func md(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// renderMarkdown has the one layout whatever the template, markdown leaves the looks to
+// whatever shows it
+func renderMarkdown(doc Document, _ Template) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("# " + md(doc.Name) + "\n\n")
+	if len(doc.Contact) > 0 {
+		contact := make([]string, len(doc.Contact))
+		for i, c := range doc.Contact {
+			contact[i] = md(c)
+		}
+		b.WriteString(strings.Join(contact, " · ") + "\n\n")
+	}
+	if doc.Summary != "" {
+		b.WriteString("## Summary\n\n")
+		for _, line := range strings.Split(doc.Summary, "\n") {
+			b.WriteString(md(line) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(doc.Skills) > 0 {
+		skills := make([]string, len(doc.Skills))
+		for i, s := range doc.Skills {
+			skills[i] = md(s)
+		}
+		b.WriteString("## Skills\n\n" + strings.Join(skills, ", ") + "\n\n")
+	}
+	for _, section := range doc.Sections {
+		b.WriteString("## " + md(section.Title) + "\n\n")
+		for _, entry := range section.Entries {
+			head := "### " + md(entry.Title)
+			if entry.Period != "" {
+				head += " (" + md(entry.Period) + ")"
+			}
+			b.WriteString(head + "\n\n")
+			if entry.Subtitle != "" {
+				b.WriteString("*" + md(entry.Subtitle) + "*\n\n")
+			}
+			if entry.Body != "" {
+				for _, line := range strings.Split(entry.Body, "\n") {
+					b.WriteString(md(line) + "\n")
+				}
+				b.WriteString("\n")
+			}
+			if entry.Link != "" {
+				b.WriteString("<" + entry.Link + ">\n\n")
+			}
+		}
+	}
+	return []byte(strings.TrimRight(b.String(), "\n") + "\n"), nil
+}
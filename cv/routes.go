@@ -0,0 +1,12 @@
+package cv
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// CVRoutes serves the cvs of public profiles and the templates to pick from, no sign in
+// needed. The cv of a profile by id is registered with the profile routes
+func CVRoutes(superRoute *gin.RouterGroup) {
+	superRoute.GET("/cv/templates", getTemplates)
+	superRoute.GET("/candidates/:slug/cv", publicCV)
+}
@@ -0,0 +1,126 @@
+package cv
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	cisredis "job_board/redis"
+)
+
+// rendered cvs are kept this long, a change to the profile changes the key so an old one
+// is never served, it only waits to expire
+const cacheTTL = 7 * 24 * time.Hour
+
+type format struct {
+	contentType string
+	extension   string
+	render      func(Document, Template) ([]byte, error)
+}
+
+var formats = map[string]format{
+	"pdf":  {"application/pdf", "pdf", renderPDF},
+	"html": {"text/html; charset=utf-8", "html", renderHTML},
+	"md":   {"text/markdown; charset=utf-8", "md", renderMarkdown},
+}
+
+type ErrUnknownOption struct {
+	Option string
+	Value  string
+	Valid  []string
+}
+
+func (e ErrUnknownOption) Error() string {
+	return fmt.Sprintf("unknown %s %q, use one of %s", e.Option, e.Value, strings.Join(e.Valid, ", "))
+}
+
+// Rendered is a cv ready to send, ETag changes whenever the cv would
+type Rendered struct {
+	Body        []byte
+	ContentType string
+	Filename    string
+	ETag        string
+}
+
+// fingerprint identifies the cv, it covers everything the output depends on
+func fingerprint(doc Document, templateName string, formatName string) (string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append(data, []byte("|"+templateName+"|"+formatName)...))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+func cacheKey(doc Document, sum string) string {
+	return "cv:" + doc.ProfileID.String() + ":" + sum
+}
+
+// render turns the document into the format with the template, reusing what was rendered
+// before when the profile hasn't changed since
+func render(doc Document, templateName string, formatName string) (*Rendered, error) {
+	t, ok := templates[templateName]
+	if !ok {
+		return nil, ErrUnknownOption{"template", templateName, templateNames()}
+	}
+	f, ok := formats[formatName]
+	if !ok {
+		return nil, ErrUnknownOption{"format", formatName, []string{"pdf", "html", "md"}}
+	}
+
+	sum, err := fingerprint(doc, t.Name, formatName)
+	if err != nil {
+		return nil, err
+	}
+	out := Rendered{
+		ContentType: f.contentType,
+		Filename:    filename(doc.Name) + "." + f.extension,
+		ETag:        `"` + sum + `"`,
+	}
+
+	client := cisredis.GetClient()
+	body, err := client.Get(context.Background(), cacheKey(doc, sum)).Bytes()
+	if err == nil {
+		out.Body = body
+		return &out, nil
+	}
+	if err != redis.Nil {
+		// a cache that can't be reached only costs the rendering
+		log.Println("Error reading a cached cv:", err)
+	}
+
+	if out.Body, err = f.render(doc, t); err != nil {
+		return nil, fmt.Errorf("error rendering cv: %w", err)
+	}
+	if err := client.Set(context.Background(), cacheKey(doc, sum), out.Body, cacheTTL).Err(); err != nil {
+		log.Println("Error caching a cv:", err)
+	}
+	return &out, nil
+}
+
+func templateNames() []string {
+	list := templateList()
+	names := make([]string, len(list))
+	for i, t := range list {
+		names[i] = t.Name
+	}
+	return names
+}
+
+var unsafeFilename = regexp.MustCompile(`[^a-z0-9]+`)
+
+func filename(name string) string {
+	base := strings.Trim(unsafeFilename.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" {
+		return "cv"
+	}
+	return base + "-cv"
+}
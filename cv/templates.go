@@ -0,0 +1,76 @@
+package cv
+
+import "sort"
+
+// Template is a cv layout, pdf and html follow it and markdown has only the one layout
+type Template struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	accent     color
+	accentHex  string
+	fontFamily string
+	margin     float64
+	fontSize   float64
+	nameSize   float64
+	// leading is the line height as a multiple of the font size
+	leading       float64
+	sectionGap    float64
+	upperHeadings bool
+	rules         bool
+}
+
+const defaultTemplate = "classic"
+
+var templates = map[string]Template{
+	"classic": {
+		Name:        "classic",
+		Description: "Black and white with ruled section headings",
+		accent:      black,
+		accentHex:   "#1a1a1a",
+		fontFamily:  "Georgia, 'Times New Roman', serif",
+		margin:      56,
+		fontSize:    10.5,
+		nameSize:    22,
+		leading:     1.4,
+		sectionGap:  14,
+		rules:       true,
+	},
+	"modern": {
+		Name:          "modern",
+		Description:   "Blue accents and spaced out capitalised headings",
+		accent:        color{0.12, 0.37, 0.72},
+		accentHex:     "#1f5eb8",
+		fontFamily:    "'Helvetica Neue', Helvetica, Arial, sans-serif",
+		margin:        50,
+		fontSize:      10,
+		nameSize:      26,
+		leading:       1.5,
+		sectionGap:    18,
+		upperHeadings: true,
+	},
+	"compact": {
+		Name:          "compact",
+		Description:   "Small type and tight spacing to fit more on a page",
+		accent:        color{0.06, 0.46, 0.43},
+		accentHex:     "#0f766e",
+		fontFamily:    "Helvetica, Arial, sans-serif",
+		margin:        36,
+		fontSize:      9,
+		nameSize:      17,
+		leading:       1.3,
+		sectionGap:    8,
+		upperHeadings: true,
+		rules:         true,
+	},
+}
+
+// templateList is the templates sorted by name
+func templateList() []Template {
+	list := make([]Template, 0, len(templates))
+	for _, t := range templates {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -8,14 +8,12 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-
 func DegreeRoutes(superRoute *gin.RouterGroup) {
 	degreeRouter := superRoute.Group("/degrees")
 
-	degreeRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), create)
+	degreeRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), create)
 	degreeRouter.GET("/", get)
 	degreeRouter.GET("/:id", getSingle)
-	degreeRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), update)
-	degreeRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), delete)
+	degreeRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), update)
+	degreeRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), delete)
 }
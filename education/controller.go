@@ -16,6 +16,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 	// "job_board/notifications"
 )
 
@@ -184,7 +185,16 @@ func GetEducation(ctx *gin.Context) {
 		IsCurrent:         isCurrent,
 	}
 
-	educations, total, page, perPage, err := getEducation(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	educations, err := getEducation(filter, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -197,12 +207,8 @@ func GetEducation(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched educations",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     educations,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       educations,
+		Links:      educations.Links(ctx),
 	})
 }
 
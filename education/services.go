@@ -4,19 +4,21 @@ import (
 	// "errors"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/completeness"
+	"job_board/matching"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func checkProfile(user models.User) bool {
@@ -44,29 +46,13 @@ func createEducation(education models.Education) (*models.Education, error) {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(education.ProfileID)
+	completeness.Queue(education.ProfileID)
+
 	return &education, nil
 }
 
-func getEducation(filter SearchEduction, pageSize string, pageNumber string) ([]models.Education, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getEducation(filter SearchEduction, params pagination.Params) (*pagination.Page[models.Education], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Education{})
 
@@ -98,25 +84,13 @@ func getEducation(filter SearchEduction, pageSize string, pageNumber string) ([]
 		db = db.Where("end_date <= ?", endDateStr)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.Education
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.Education](db, params)
+	if err != nil {
 		log.Println("Error finding education:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleEducation(search models.Education) (*models.Education, error) {
@@ -143,13 +117,9 @@ func updateEducation(educationID uuid.UUID, user models.User, updates Request) (
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole {
-		if profile := checkProfile(user); profile {
-			return nil, fmt.Errorf("you don't have a profile")
-		}
-		if existingRecord.ProfileID != user.Profile.ID {
-			return nil, fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:update", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
 	}
 
 	// Update the record with the provided updates
@@ -161,6 +131,9 @@ func updateEducation(educationID uuid.UUID, user models.User, updates Request) (
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
+
 	return &existingRecord, nil
 }
 
@@ -178,10 +151,9 @@ func deleteSingleEducation(educationID uuid.UUID, user models.User) error {
         return err
     }
 
-	if user.RoleName == models.UserRole {
-		if existingRecord.ProfileID != user.Profile.ID {
-			return  fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:delete", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.Education{}, "id = ?", educationID)
@@ -200,5 +172,8 @@ func deleteSingleEducation(educationID uuid.UUID, user models.User) error {
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
 	return nil
 }
@@ -0,0 +1,81 @@
+package erasure
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/gin-gonic/gin"
+
+	"job_board/helpers"
+	"job_board/models"
+)
+
+// eraseMe erases the signed in account without waiting for the grace period, there is no
+// reinstating it afterwards
+func eraseMe(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req EraseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	if !confirms(req, *user) {
+		message := "confirm the erasure with your account's email"
+		if user.Email == "" {
+			message = "confirm the erasure with your account's id"
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    message,
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	if user.RoleName == models.SuperAdminRole {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    "the super admin account can't be erased",
+			StatusCode: http.StatusForbidden,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := Erase(ctx.Request.Context(), user.ID, "requested by the user"); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Your account and its data were erased",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+// confirms reports whether the request names the account, by its email when it has one
+func confirms(req EraseRequest, user models.User) bool {
+	if user.Email != "" {
+		return strings.EqualFold(req.Email, user.Email)
+	}
+	id, err := uuid.Parse(req.UserID)
+	return err == nil && id != uuid.Nil && id == user.ID
+}
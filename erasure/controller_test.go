@@ -0,0 +1,34 @@
+package erasure
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"job_board/models"
+)
+
+func TestConfirms(t *testing.T) {
+	id := uuid.New()
+	withEmail := models.User{ID: id, Email: "Ada@Example.com"}
+	withoutEmail := models.User{ID: id}
+	tests := []struct {
+		name string
+		req  EraseRequest
+		user models.User
+		want bool
+	}{
+		{"the account's email", EraseRequest{Email: "ada@example.com"}, withEmail, true},
+		{"another email", EraseRequest{Email: "eve@example.com"}, withEmail, false},
+		{"the id when there is an email", EraseRequest{UserID: id.String()}, withEmail, false},
+		{"the id without an email", EraseRequest{UserID: id.String()}, withoutEmail, true},
+		{"another id", EraseRequest{UserID: uuid.NewString()}, withoutEmail, false},
+		{"an empty email without an email", EraseRequest{}, withoutEmail, false},
+		{"a nil id", EraseRequest{UserID: uuid.Nil.String()}, models.User{}, false},
+	}
+	for _, tt := range tests {
+		if got := confirms(tt.req, tt.user); got != tt.want {
+			t.Errorf("%s: confirms = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
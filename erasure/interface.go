@@ -0,0 +1,8 @@
+package erasure
+
+// EraseRequest confirms erasing the signed in account with the account's email, accounts
+// without one, like those signed in by phone, confirm with their id instead
+type EraseRequest struct {
+	Email  string `json:"email" binding:"omitempty,email"`
+	UserID string `json:"user_id"`
+}
@@ -0,0 +1,11 @@
+package erasure
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ErasureRoutes registers erasing the signed in account right away, the router is
+// expected to carry the jwt middleware
+func ErasureRoutes(userRouter *gin.RouterGroup) {
+	userRouter.POST("/user/erase", eraseMe)
+}
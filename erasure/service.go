@@ -0,0 +1,304 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"job_board/audit"
+	"job_board/config"
+	"job_board/files"
+	"job_board/jwt"
+	"job_board/models"
+	"job_board/notifications"
+)
+
+var database *gorm.DB
+
+// gracePeriod is how long a deleted account can be reinstated, Setup sets it from
+// ACCOUNT_ERASURE_GRACE_DAYS
+var gracePeriod = 90 * 24 * time.Hour
+
+// Setup gives the package its database handle and how long deleted accounts are kept
+func Setup(db *gorm.DB, cfg config.Accounts) {
+	database = db
+	gracePeriod = time.Duration(cfg.ErasureGraceDays) * 24 * time.Hour
+}
+
+var ErrAlreadyErased = errors.New("account was already erased")
+
+// keptUserColumns are the columns of users audit entries keep once the account is erased,
+// every other value is replaced
+var keptUserColumns = map[string]bool{
+	"id":         true,
+	"role_name":  true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+	"erased_at":  true,
+}
+
+// Erase removes the personal data of an account for good. The profile, applications,
+// saved searches, feed and uploads are deleted, the user row is kept anonymised since jobs,
+// companies and status histories still point at it, audit entries lose the personal values
+// and a tombstone entry records the erasure. reason ends up in the tombstone
+func Erase(ctx context.Context, userID uuid.UUID, reason string) error {
+	var user models.User
+	var keys []string
+	now := time.Now()
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the lock keeps the sweep and an erase request from erasing the same account twice
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		if user.ErasedAt != nil {
+			return ErrAlreadyErased
+		}
+
+		// lets the audit_logs trigger accept the scrubbing below, it ends with the transaction
+		if err := tx.Exec("SELECT set_config('job_board.erasure', 'on', true)").Error; err != nil {
+			return err
+		}
+
+		if err := deleteProfile(tx, user.ID); err != nil {
+			return fmt.Errorf("error deleting profile: %w", err)
+		}
+		if err := deleteApplications(tx, user.ID); err != nil {
+			return fmt.Errorf("error deleting applications: %w", err)
+		}
+		if err := deleteSavedSearches(tx, user.ID); err != nil {
+			return fmt.Errorf("error deleting saved searches: %w", err)
+		}
+		if err := deleteCompanyLinks(tx, user); err != nil {
+			return fmt.Errorf("error deleting company memberships: %w", err)
+		}
+		if err := deleteNotifications(tx, user); err != nil {
+			return fmt.Errorf("error deleting notifications: %w", err)
+		}
+
+		var err error
+		if keys, err = deleteFiles(tx, user.ID); err != nil {
+			return fmt.Errorf("error deleting files: %w", err)
+		}
+		if err := anonymise(tx, user.ID, now); err != nil {
+			return fmt.Errorf("error anonymising user: %w", err)
+		}
+		if err := scrubAuditLog(tx, user.ID); err != nil {
+			return fmt.Errorf("error scrubbing audit log: %w", err)
+		}
+		return tombstone(tx, user.ID, reason, now)
+	})
+	if err != nil {
+		return err
+	}
+
+	// the account is gone already, what's left outside the database is cleaned up best effort
+	if err := jwt.RevokeAllSessions(user.ProviderID); err != nil {
+		log.Printf("Failed to revoke the sessions of erased user %s: %v", user.ID, err)
+	}
+	if err := files.RemoveObjects(context.Background(), keys); err != nil {
+		log.Printf("Failed to remove the uploads of erased user %s: %v", user.ID, err)
+	}
+	return nil
+}
+
+func deleteProfile(tx *gorm.DB, userID uuid.UUID) error {
+	var profile models.Profile
+	if err := tx.Unscoped().Where("user_id = ?", userID).Limit(1).Find(&profile).Error; err != nil {
+		return err
+	}
+	if profile.ID == uuid.Nil {
+		return nil
+	}
+
+	for _, model := range []interface{}{
+		&models.Education{},
+		&models.InternShipExperience{},
+		&models.ProjectsExperience{},
+		&models.WorkSample{},
+		&models.Award{},
+		&models.ProfileLanguage{},
+		&models.SocialMediaAccount{},
+		&models.MatchScore{},
+	} {
+		if err := tx.Unscoped().Where("profile_id = ?", profile.ID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Delete(&profile).Error
+}
+
+func deleteApplications(tx *gorm.DB, userID uuid.UUID) error {
+	applications := tx.Unscoped().Model(&models.JobApplication{}).Select("id").Where("applicant_id = ?", userID)
+	if err := tx.Where("job_application_id IN (?)", applications).Delete(&models.ApplicationStatusHistory{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("applicant_id = ?", userID).Delete(&models.JobApplication{}).Error
+}
+
+func deleteSavedSearches(tx *gorm.DB, userID uuid.UUID) error {
+	searches := tx.Unscoped().Model(&models.SavedSearch{}).Select("id").Where("user_id = ?", userID)
+	if err := tx.Where("saved_search_id IN (?)", searches).Delete(&models.SavedSearchMatch{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("user_id = ?", userID).Delete(&models.SavedSearch{}).Error
+}
+
+// deleteCompanyLinks drops the user's memberships and the invitations sent to their email.
+// Ownerships stay, every company needs an owner and the anonymised one can be replaced
+func deleteCompanyLinks(tx *gorm.DB, user models.User) error {
+	if err := tx.Where("user_id = ? AND role <> ?", user.ID, models.CompanyOwner).Delete(&models.CompanyMember{}).Error; err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+	return tx.Where("LOWER(email) = LOWER(?)", user.Email).Delete(&models.CompanyInvitation{}).Error
+}
+
+// deleteNotifications clears the feed and the notifications still waiting in the outbox,
+// and queues removing the user from the provider
+func deleteNotifications(tx *gorm.DB, user models.User) error {
+	if err := tx.Where("user_id = ?", user.ID).Delete(&models.Notification{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", user.ID).Delete(&models.NotificationPreference{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", user.ID).Delete(&models.ProfileReminder{}).Error; err != nil {
+		return err
+	}
+
+	// sent and dead messages had their payload cleared already, whatever still names the
+	// user is dropped whether it is due or not
+	if user.SubscriberID != "" {
+		if err := tx.
+			Where("(payload->>'subscriberId' = ? OR payload->'to'->>'subscriberId' = ?)", user.SubscriberID, user.SubscriberID).
+			Delete(&models.OutboxMessage{}).Error; err != nil {
+			return err
+		}
+	}
+	if user.Email != "" {
+		if err := tx.
+			Where("(LOWER(payload->>'email') = LOWER(?) OR LOWER(payload->'to'->>'email') = LOWER(?))", user.Email, user.Email).
+			Delete(&models.OutboxMessage{}).Error; err != nil {
+			return err
+		}
+	}
+
+	if user.SubscriberID == "" {
+		return nil
+	}
+	return notifications.EnqueueForget(tx, user.SubscriberID)
+}
+
+// deleteFiles removes the rows of the user's uploads and data exports and returns the keys
+// to remove from storage once the transaction commits
+func deleteFiles(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	var uploads []models.File
+	if err := tx.Unscoped().Where("owner_id = ?", userID).Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(uploads))
+	for _, upload := range uploads {
+		keys = append(keys, upload.Key)
+	}
+	if err := tx.Unscoped().Where("owner_id = ?", userID).Delete(&models.File{}).Error; err != nil {
+		return nil, err
+	}
+
+	// data exports are copies of everything above
+	var exports []models.DataExport
+	if err := tx.Where("user_id = ?", userID).Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	for _, export := range exports {
+		if export.Key != "" {
+			keys = append(keys, export.Key)
+		}
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&models.DataExport{}).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// anonymise blanks the user row with raw sql, going through the model would have the
+// audit callbacks log the values being removed
+func anonymise(tx *gorm.DB, userID uuid.UUID, now time.Time) error {
+	return tx.Exec(`UPDATE users SET
+		name = 'Deleted user',
+		email = NULL,
+		picture = '',
+		provider_id = ?,
+		mobile_number = NULL,
+		subscriber_id = NULL,
+		verification_token = '',
+		email_verified_at = NULL,
+		totp_secret = NULL,
+		totp_enabled_at = NULL,
+		recovery_codes = NULL,
+		password = NULL,
+		deleted_at = COALESCE(deleted_at, ?),
+		erased_at = ?
+		WHERE id = ?`, "erased|"+userID.String(), now, now, userID).Error
+}
+
+// scrubAuditLog replaces the personal values in the user's audit entries and drops where
+// their requests came from
+func scrubAuditLog(tx *gorm.DB, userID uuid.UUID) error {
+	var entries []models.AuditLog
+	if err := tx.Where("resource_type = ? AND resource_id = ?", "user", userID.String()).Find(&entries).Error; err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := tx.Model(&models.AuditLog{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
+			"before": scrub(entry.Before),
+			"after":  scrub(entry.After),
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&models.AuditLog{}).
+		Where("actor_id = ? AND (ip IS NOT NULL OR user_agent IS NOT NULL)", userID).
+		Updates(map[string]interface{}{"ip": nil, "user_agent": nil}).Error
+}
+
+func scrub(values models.AuditValues) models.AuditValues {
+	if values == nil {
+		return nil
+	}
+	out := models.AuditValues{}
+	for column, value := range values {
+		if !keptUserColumns[column] && value != nil {
+			value = "[erased]"
+		}
+		out[column] = value
+	}
+	return out
+}
+
+// tombstone records that the account was erased, it is filed under the user like the rest
+// of their entries and holds nothing personal
+func tombstone(tx *gorm.DB, userID uuid.UUID, reason string, now time.Time) error {
+	request := audit.FromContext(tx.Statement.Context)
+	entry := models.AuditLog{
+		ActorID:      request.ActorID,
+		ActorRole:    request.ActorRole,
+		Action:       models.AuditErase,
+		ResourceType: "user",
+		ResourceID:   userID.String(),
+		After:        models.AuditValues{"erased_at": now.UTC(), "reason": reason},
+		Method:       request.Method,
+		Path:         request.Path,
+		RequestID:    request.RequestID,
+	}
+	return tx.Create(&entry).Error
+}
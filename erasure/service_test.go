@@ -0,0 +1,133 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"job_board/models"
+)
+
+// mockDatabase points the package at a sqlmock connection for the length of the test, the
+// functions under test run inside Erase's transaction so gorm adds none of its own
+func mockDatabase(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database
+	database = db
+	t.Cleanup(func() {
+		database = previous
+		conn.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return mock
+}
+
+func TestScrub(t *testing.T) {
+	erasedAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	got := scrub(models.AuditValues{
+		"id":            "6f1c",
+		"role_name":     "user",
+		"name":          "Ada Okafor",
+		"email":         "ada@example.com",
+		"mobile_number": nil,
+		"erased_at":     erasedAt,
+	})
+	want := models.AuditValues{
+		"id":            "6f1c",
+		"role_name":     "user",
+		"name":          "[erased]",
+		"email":         "[erased]",
+		"mobile_number": nil,
+		"erased_at":     erasedAt,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("scrub = %v, want %v", got, want)
+	}
+	for column, value := range want {
+		if got[column] != value {
+			t.Errorf("%s = %v, want %v", column, got[column], value)
+		}
+	}
+	if scrub(nil) != nil {
+		t.Error("an entry without values got some")
+	}
+}
+
+func TestDeleteNotificationsClearsTheOutbox(t *testing.T) {
+	mock := mockDatabase(t)
+	user := models.User{ID: uuid.New(), Email: "Ada@Example.com", SubscriberID: "sub-1"}
+
+	for _, table := range []string{"notifications", "notification_preferences", "profile_reminders"} {
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "`+table+`" WHERE user_id = $1`)).
+			WithArgs(user.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	// due or dead, any message naming the user goes
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "outbox_messages" WHERE (payload->>'subscriberId' = $1 OR payload->'to'->>'subscriberId' = $2)`)).
+		WithArgs("sub-1", "sub-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "outbox_messages" WHERE (LOWER(payload->>'email') = LOWER($1) OR LOWER(payload->'to'->>'email') = LOWER($2))`)).
+		WithArgs(user.Email, user.Email).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// then the provider is asked to forget them
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_messages"`)).
+		WithArgs(models.OutboxForget, "forget", `{"subscriberId":"sub-1","name":"","email":"","avatar":"","data":null}`, models.OutboxPending,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+
+	if err := deleteNotifications(database, user); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteNotificationsWithoutRecipients(t *testing.T) {
+	mock := mockDatabase(t)
+	user := models.User{ID: uuid.New()}
+
+	for _, table := range []string{"notifications", "notification_preferences", "profile_reminders"} {
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "` + table + `"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	// nothing in the outbox can name the user and the provider never knew them
+
+	if err := deleteNotifications(database, user); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEraseTwice(t *testing.T) {
+	mock := mockDatabase(t)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 ORDER BY "users"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "erased_at"}).AddRow(userID, time.Now()))
+	mock.ExpectRollback()
+
+	if err := Erase(context.Background(), userID, "test"); !errors.Is(err, ErrAlreadyErased) {
+		t.Errorf("erasing an erased account: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package erasure
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"job_board/models"
+)
+
+const (
+	// sweepInterval is how often deleted accounts past the grace period are looked for
+	sweepInterval = time.Hour
+	sweepBatch    = 100
+)
+
+// StartSweeper erases the accounts that were deleted longer ago than the grace period
+func StartSweeper() {
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			if err := sweep(time.Now()); err != nil {
+				log.Printf("Failed to erase deleted accounts: %v", err)
+			}
+		}
+	}()
+}
+
+func sweep(now time.Time) error {
+	var ids []uuid.UUID
+	if err := database.Unscoped().Model(&models.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at <= ? AND erased_at IS NULL", now.Add(-gracePeriod)).
+		Order("deleted_at").
+		Limit(sweepBatch).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := Erase(context.Background(), id, "grace period over"); err != nil && err != ErrAlreadyErased {
+			log.Printf("Failed to erase user %s: %v", id, err)
+		}
+	}
+	return nil
+}
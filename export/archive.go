@@ -0,0 +1,234 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"job_board/files"
+	"job_board/models"
+)
+
+// archive is everything kept about a user, data.json holds all of it and every list also
+// gets a csv
+type archive struct {
+	ExportedAt         time.Time                         `json:"exported_at"`
+	User               models.User                       `json:"user"`
+	Profile            *models.Profile                   `json:"profile"`
+	Applications       []models.JobApplication           `json:"applications"`
+	ApplicationHistory []models.ApplicationStatusHistory `json:"application_history"`
+	Companies          []models.Company                  `json:"companies"`
+	Memberships        []models.CompanyMember            `json:"company_memberships"`
+	Jobs               []models.Job                      `json:"jobs"`
+	Notifications      []models.Notification             `json:"notifications"`
+	Files              []models.File                     `json:"files"`
+}
+
+func gather(userID uuid.UUID, now time.Time) (*archive, error) {
+	data := archive{ExportedAt: now.UTC()}
+	if err := database.First(&data.User, "id = ?", userID).Error; err != nil {
+		return nil, fmt.Errorf("error fetching user: %w", err)
+	}
+
+	var profiles []models.Profile
+	if err := database.
+		Preload("Resume").
+		Preload("Gender").
+		Preload("Educations").
+		Preload("InternShipExperiences").
+		Preload("ProjectsExperiences").
+		Preload("WorkSamples").
+		Preload("Awards").
+		Preload("ProfileLanguages").
+		Preload("SocialMediaAccounts").
+		Where("user_id = ?", userID).
+		Limit(1).
+		Find(&profiles).Error; err != nil {
+		return nil, fmt.Errorf("error fetching profile: %w", err)
+	}
+	if len(profiles) > 0 {
+		data.Profile = &profiles[0]
+	}
+
+	if err := database.Preload("Job").Preload("Stage").Where("applicant_id = ?", userID).Order("created_at").Find(&data.Applications).Error; err != nil {
+		return nil, fmt.Errorf("error fetching applications: %w", err)
+	}
+	applications := database.Model(&models.JobApplication{}).Select("id").Where("applicant_id = ?", userID)
+	if err := database.Where("job_application_id IN (?)", applications).Order("created_at").Find(&data.ApplicationHistory).Error; err != nil {
+		return nil, fmt.Errorf("error fetching application history: %w", err)
+	}
+
+	if err := database.Where("user_id = ?", userID).Order("created_at").Find(&data.Memberships).Error; err != nil {
+		return nil, fmt.Errorf("error fetching company memberships: %w", err)
+	}
+	memberOf := database.Model(&models.CompanyMember{}).Select("company_id").Where("user_id = ?", userID)
+	if err := database.Where("user_id = ? OR id IN (?)", userID, memberOf).Order("created_at").Find(&data.Companies).Error; err != nil {
+		return nil, fmt.Errorf("error fetching companies: %w", err)
+	}
+	if err := database.Where("user_id = ?", userID).Order("created_at").Find(&data.Jobs).Error; err != nil {
+		return nil, fmt.Errorf("error fetching jobs: %w", err)
+	}
+
+	if err := database.Where("user_id = ?", userID).Order("created_at").Find(&data.Notifications).Error; err != nil {
+		return nil, fmt.Errorf("error fetching notifications: %w", err)
+	}
+	if err := database.Where("owner_id = ?", userID).Order("created_at").Find(&data.Files).Error; err != nil {
+		return nil, fmt.Errorf("error fetching files: %w", err)
+	}
+	return &data, nil
+}
+
+// write zips the archive up, uploads are added under uploads/ as they were stored
+func (a *archive) write(ctx context.Context, out io.Writer) error {
+	zw := zip.NewWriter(out)
+
+	entry, err := zw.Create("data.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(a); err != nil {
+		return fmt.Errorf("error encoding data.json: %w", err)
+	}
+
+	tables := []struct {
+		name    string
+		records interface{}
+	}{
+		{"user.csv", []models.User{a.User}},
+		{"applications.csv", a.Applications},
+		{"application_history.csv", a.ApplicationHistory},
+		{"companies.csv", a.Companies},
+		{"company_memberships.csv", a.Memberships},
+		{"jobs.csv", a.Jobs},
+		{"notifications.csv", a.Notifications},
+		{"files.csv", a.Files},
+	}
+	if profile := a.Profile; profile != nil {
+		tables = append(tables, []struct {
+			name    string
+			records interface{}
+		}{
+			{"profile.csv", []models.Profile{*profile}},
+			{"profile_educations.csv", profile.Educations},
+			{"profile_internships.csv", profile.InternShipExperiences},
+			{"profile_projects.csv", profile.ProjectsExperiences},
+			{"profile_work_samples.csv", profile.WorkSamples},
+			{"profile_awards.csv", profile.Awards},
+			{"profile_languages.csv", profile.ProfileLanguages},
+			{"profile_social_accounts.csv", profile.SocialMediaAccounts},
+		}...)
+	}
+	for _, table := range tables {
+		if err := writeCSV(zw, table.name, table.records); err != nil {
+			return fmt.Errorf("error writing %s: %w", table.name, err)
+		}
+	}
+
+	for _, file := range a.Files {
+		if err := addUpload(ctx, zw, file); err != nil {
+			// the listing in files.csv still says it exists
+			log.Printf("Failed to add file %s to a data export: %v", file.ID, err)
+		}
+	}
+	return zw.Close()
+}
+
+func addUpload(ctx context.Context, zw *zip.Writer, file models.File) error {
+	content, err := files.OpenObject(ctx, file.Driver, file.Key)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	entry, err := zw.Create("uploads/" + file.ID.String() + path.Ext(file.Key))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, content)
+	return err
+}
+
+// writeCSV writes a list of records with a column per field. Nested records are left to
+// data.json, lists of plain values are joined with semicolons
+func writeCSV(zw *zip.Writer, name string, records interface{}) error {
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	var columns []string
+	for _, row := range rows {
+		for column, value := range row {
+			// a null can't tell a plain field from a nested record
+			if _, ok := cell(value); ok && value != nil && !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	entry, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(entry)
+	if err := w.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			line[i], _ = cell(row[column])
+		}
+		if err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// cell formats a json value for a csv cell, false for values that don't fit in one
+func cell(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			part, ok := cell(item)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, ";"), true
+	default:
+		return "", false
+	}
+}
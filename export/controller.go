@@ -0,0 +1,164 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"job_board/files"
+	"job_board/helpers"
+	"job_board/models"
+	"job_board/pagination"
+)
+
+func create(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := requestExport(*user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusConflict,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Your data export is being prepared, check its status for the download link",
+		StatusCode: http.StatusAccepted,
+		Data:       resp,
+	})
+}
+
+func get(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	params, err := pagination.FromContext(ctx, exportSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getExports(*user, params)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched data exports",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+		Links:      resp.Links(ctx),
+	})
+}
+
+func getSingle(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getSingleExport(ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusNotFound,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched data export",
+		StatusCode: http.StatusOK,
+		Data:       withLink(*resp, time.Now()),
+	})
+}
+
+func download(ctx *gin.Context) {
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	export, err := downloadable(ID, ctx.Query("expires"), ctx.Query("signature"), time.Now())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrInvalidLink) {
+			status = http.StatusForbidden
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
+	content, err := files.OpenObject(ctx.Request.Context(), export.Driver, export.Key)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+	defer content.Close()
+
+	name := fmt.Sprintf("data-export-%s.zip", export.CreatedAt.Format("2006-01-02"))
+	ctx.DataFromReader(http.StatusOK, export.Size, "application/zip", content, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", name),
+		"Cache-Control":       "no-store",
+	})
+}
@@ -0,0 +1,22 @@
+package export
+
+import (
+	"time"
+
+	"job_board/models"
+	"job_board/pagination"
+)
+
+var exportSorts = pagination.Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at": "created_at",
+	},
+}
+
+// ExportResponse is an export with a fresh download link once it is ready
+type ExportResponse struct {
+	models.DataExport
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+package export
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"job_board/jwt"
+)
+
+func ExportRoutes(superRoute *gin.RouterGroup) {
+	exportRouter := superRoute.Group("/exports")
+
+	// download links are signed and short lived so they can be opened without a jwt
+	exportRouter.GET("/:id/download", download)
+
+	exportRouter.POST("/", jwt.Middleware(), create)
+	exportRouter.GET("/", jwt.Middleware(), get)
+	exportRouter.GET("/:id", jwt.Middleware(), getSingle)
+}
@@ -0,0 +1,180 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"job_board/models"
+	"job_board/pagination"
+)
+
+const (
+	// retention is how long a built archive is kept
+	retention = 7 * 24 * time.Hour
+	// linkTTL is how long a download link works, a new one comes with every status check
+	linkTTL = time.Hour
+	// an export running for longer than this is taken to have lost its worker and is retried
+	staleAfter = 15 * time.Minute
+)
+
+var database *gorm.DB
+
+var signingKey []byte
+
+// Setup gives the package its database handle and the secret download links are signed with
+func Setup(db *gorm.DB, secret string) {
+	database = db
+	sum := sha256.Sum256([]byte("data-export:" + secret))
+	signingKey = sum[:]
+}
+
+var ErrInvalidLink = errors.New("this download link is invalid or has expired")
+
+func requestExport(user models.User) (*models.DataExport, error) {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var running int64
+	if err := tx.Model(&models.DataExport{}).
+		Where("user_id = ? AND status IN ?", user.ID, []models.ExportStatus{models.ExportPending, models.ExportRunning}).
+		Count(&running).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if running > 0 {
+		tx.Rollback()
+		return nil, errors.New("an export of your data is already being prepared")
+	}
+
+	export := models.DataExport{UserID: user.ID, Status: models.ExportPending}
+	if err := tx.Create(&export).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error requesting a data export: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &export, nil
+}
+
+func getExports(user models.User, params pagination.Params) (*pagination.Page[models.DataExport], error) {
+	db := database.Model(&models.DataExport{}).Where("user_id = ?", user.ID)
+	data, err := pagination.Find[models.DataExport](db, params)
+	if err != nil {
+		log.Println("Error finding data exports:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func getSingleExport(ID uuid.UUID, user models.User) (*models.DataExport, error) {
+	var record models.DataExport
+	if err := database.First(&record, "id = ? AND user_id = ?", ID, user.ID).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// withLink adds a download link to exports that can be downloaded
+func withLink(export models.DataExport, now time.Time) ExportResponse {
+	resp := ExportResponse{DataExport: export}
+	if export.Status != models.ExportReady {
+		return resp
+	}
+	expires := now.Add(linkTTL)
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(expires) {
+		expires = *export.ExpiresAt
+	}
+	resp.DownloadURL = fmt.Sprintf("/api/v1/exports/%s/download?expires=%d&signature=%s", export.ID, expires.Unix(), sign(export.ID, expires.Unix()))
+	resp.DownloadExpiresAt = &expires
+	return resp
+}
+
+func sign(ID uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, signingKey)
+	fmt.Fprintf(mac, "%s.%d", ID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// downloadable checks the signature of a download link and returns the export it is for
+func downloadable(ID uuid.UUID, expires string, signature string, now time.Time) (*models.DataExport, error) {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > unix {
+		return nil, ErrInvalidLink
+	}
+	if !hmac.Equal([]byte(sign(ID, unix)), []byte(signature)) {
+		return nil, ErrInvalidLink
+	}
+
+	var record models.DataExport
+	if err := database.First(&record, "id = ? AND status = ?", ID, models.ExportReady).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidLink
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// claim takes the oldest export waiting to be built, or one whose worker went away
+func claim(now time.Time) (*models.DataExport, error) {
+	var exports []models.DataExport
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND started_at < ?)", models.ExportPending, models.ExportRunning, now.Add(-staleAfter)).
+			Order("created_at").
+			Limit(1).
+			Find(&exports).Error; err != nil {
+			return err
+		}
+		if len(exports) == 0 {
+			return nil
+		}
+		exports[0].Status = models.ExportRunning
+		exports[0].StartedAt = &now
+		return tx.Model(&exports[0]).Updates(map[string]interface{}{
+			"status":     models.ExportRunning,
+			"started_at": now,
+		}).Error
+	})
+	if err != nil || len(exports) == 0 {
+		return nil, err
+	}
+	return &exports[0], nil
+}
+
+func complete(export models.DataExport, key string, driver string, size int64, now time.Time) error {
+	return database.Model(&models.DataExport{}).Where("id = ?", export.ID).Updates(map[string]interface{}{
+		"status":       models.ExportReady,
+		"key":          key,
+		"driver":       driver,
+		"size":         size,
+		"error":        "",
+		"completed_at": now,
+		"expires_at":   now.Add(retention),
+	}).Error
+}
+
+func fail(export models.DataExport, cause error, now time.Time) error {
+	return database.Model(&models.DataExport{}).Where("id = ?", export.ID).Updates(map[string]interface{}{
+		"status":       models.ExportFailed,
+		"error":        cause.Error(),
+		"completed_at": now,
+	}).Error
+}
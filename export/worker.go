@@ -0,0 +1,105 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"job_board/files"
+	"job_board/models"
+)
+
+// workerInterval is how often the worker looks for exports to build and archives to remove
+const workerInterval = 5 * time.Second
+
+// StartWorker builds requested exports in the background and removes the expired ones
+func StartWorker() {
+	go func() {
+		ticker := time.NewTicker(workerInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			if err := buildNext(time.Now()); err != nil {
+				log.Printf("Failed to build data export: %v", err)
+			}
+			if err := removeExpired(time.Now()); err != nil {
+				log.Printf("Failed to remove expired data exports: %v", err)
+			}
+		}
+	}()
+}
+
+func buildNext(now time.Time) error {
+	export, err := claim(now)
+	if err != nil || export == nil {
+		return err
+	}
+
+	key, driver, size, err := build(*export, now)
+	if err != nil {
+		if err := fail(*export, err, time.Now()); err != nil {
+			log.Printf("Failed to mark data export %s as failed: %v", export.ID, err)
+		}
+		return fmt.Errorf("error building export %s: %w", export.ID, err)
+	}
+	return complete(*export, key, driver, size, time.Now())
+}
+
+// build writes the archive to a temporary file first, storages want to know the size
+func build(export models.DataExport, now time.Time) (string, string, int64, error) {
+	data, err := gather(export.UserID, now)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	tmp, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return "", "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	ctx := context.Background()
+	if err := data.write(ctx, tmp); err != nil {
+		return "", "", 0, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", "", 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", "", 0, err
+	}
+
+	key := "exports/" + export.UserID.String() + "/" + export.ID.String() + ".zip"
+	driver, err := files.PutObject(ctx, key, tmp, size, "application/zip")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("error storing archive: %w", err)
+	}
+	return key, driver, size, nil
+}
+
+func removeExpired(now time.Time) error {
+	var exports []models.DataExport
+	if err := database.
+		Where("status = ? AND expires_at <= ?", models.ExportReady, now).
+		Find(&exports).Error; err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if err := files.RemoveObjects(context.Background(), []string{export.Key}); err != nil {
+			log.Printf("Failed to remove data export %s: %v", export.ID, err)
+			continue
+		}
+		if err := database.Model(&models.DataExport{}).Where("id = ?", export.ID).Updates(map[string]interface{}{
+			"status": models.ExportExpired,
+			"key":    "",
+		}).Error; err != nil {
+			log.Printf("Failed to expire data export %s: %v", export.ID, err)
+		}
+	}
+	return nil
+}
@@ -1,57 +1,169 @@
 package files
 
 import (
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-
-	"encoding/json"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
-	"log"
 	"net/http"
-	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	"job_board/helpers"
+	"job_board/models"
 )
 
-type Error struct {
-	Status  bool   `json:"status"`
-	Message string `json:"message"`
+var errTooLarge = errors.New("file is too large")
+
+// limitReader fails once more than max bytes have been read, so storage never keeps a partial upload
+type limitReader struct {
+	r   io.Reader
+	n   int64
+	max int64
 }
 
-var uploadFlyKey string
+func (l *limitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.max {
+		return n, errTooLarge
+	}
+	return n, err
+}
 
-func init() {
-	err := godotenv.Load()
+func uploadHandler(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
 	if err != nil {
-		log.Println("Error loading .env file")
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
 	}
 
-	uploadFlyKey = os.Getenv("UPLOAD_FLY")
-	if uploadFlyKey == "" {
-		panic("Error loading upload fly key")
+	// read the body as a stream instead of letting gin buffer the whole form
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
 	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    err.Error(),
+				StatusCode: http.StatusBadRequest,
+				Data:       nil,
+			})
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+		defer part.Close()
+
+		limited := &limitReader{r: part, max: maxSize}
+		buffered := bufio.NewReaderSize(limited, sniffLen)
+		// the client's content type can't be trusted, look at the bytes instead
+		head, err := buffered.Peek(sniffLen)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    err.Error(),
+				StatusCode: http.StatusBadRequest,
+				Data:       nil,
+			})
+			return
+		}
+		contentType := sniffType(head)
+		ext, ok := allowedTypes[contentType]
+		if !ok {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    fmt.Sprintf("file type %s is not allowed, upload a pdf, docx, txt, jpg or png", contentType),
+				StatusCode: http.StatusUnsupportedMediaType,
+				Data:       nil,
+			})
+			return
+		}
+
+		hash := sha256.New()
+		key := user.ID.String() + "/" + uuid.NewString() + ext
+		if err := storage.Put(ctx.Request.Context(), key, io.TeeReader(buffered, hash), -1, contentType); err != nil {
+			if errors.Is(err, errTooLarge) {
+				helpers.CreateResponse(ctx, helpers.Response{
+					Message:    fmt.Sprintf("file is larger than %d bytes", maxSize),
+					StatusCode: http.StatusRequestEntityTooLarge,
+					Data:       nil,
+				})
+				return
+			}
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    err.Error(),
+				StatusCode: http.StatusInternalServerError,
+				Data:       nil,
+			})
+			return
+		}
+
+		file, err := createFile(models.File{
+			OwnerID:     user.ID,
+			Name:        filepath.Base(part.FileName()),
+			Key:         key,
+			Driver:      storage.Name(),
+			ContentType: contentType,
+			Size:        limited.n,
+			Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		})
+		if err != nil {
+			storage.Delete(ctx.Request.Context(), key)
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    err.Error(),
+				StatusCode: http.StatusInternalServerError,
+				Data:       nil,
+			})
+			return
+		}
+
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    "successfully uploaded file",
+			StatusCode: http.StatusCreated,
+			Data:       file,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "no file field in the request",
+		StatusCode: http.StatusBadRequest,
+		Data:       nil,
+	})
 }
 
-func uploadHandler(ctx *gin.Context) {
-	file, err := ctx.FormFile("file")
+func download(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
-			StatusCode: http.StatusBadRequest,
+			StatusCode: http.StatusUnauthorized,
 			Data:       nil,
 		})
 		return
 	}
-	log.Println(file.Filename)
-
-	newClient := NewAPIClient(uploadFlyKey)
-	resp, err := newClient.Post(UploadRequest{
-		File:             *file,
-		Filename:         file.Filename,
-		MaxFileSize:      "8MB",
-		AllowedFileTypes: ".jpg,.jpeg,.png,.pdf",
-	})
+
+	ID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -60,38 +172,79 @@ func uploadHandler(ctx *gin.Context) {
 		})
 		return
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+
+	file, err := getSingleFile(ID, *user)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
-			StatusCode: http.StatusInternalServerError,
+			StatusCode: http.StatusNotFound,
 			Data:       nil,
 		})
 		return
 	}
 
-	//check for response
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		var failed Error
-		err = json.Unmarshal(body, &failed)
-		if err != nil {
-			helpers.CreateResponse(ctx, helpers.Response{
-				Message:    err.Error(),
-				StatusCode: http.StatusInternalServerError,
-				Data:       nil,
-			})
-			return
+	if file.Driver != storage.Name() {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    fmt.Sprintf("file is kept by the %s storage which isn't configured", file.Driver),
+			StatusCode: http.StatusServiceUnavailable,
+			Data:       nil,
+		})
+		return
+	}
+
+	content, err := storage.Open(ctx.Request.Context(), file.Key)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrNotFound) {
+			status = http.StatusNotFound
 		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+	defer content.Close()
+
+	ctx.DataFromReader(http.StatusOK, file.Size, file.ContentType, content, map[string]string{
+		"Content-Disposition": fmt.Sprintf("inline; filename=%q", file.Name),
+	})
+}
+
+func delete(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusUnauthorized,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
 
+	if err := deleteSingleFile(ID, *user); err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
-			Message:    errors.New("received non-200 response" + failed.Message).Error(),
-			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
 			Data:       nil,
 		})
 		return
 	}
 
-	// Upload the file to specific dst.
-	// ctx.SaveUploadedFile(file, dst)
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "successfully deleted file",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
 }
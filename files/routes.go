@@ -2,10 +2,17 @@ package files
 
 import (
 	"github.com/gin-gonic/gin"
+
+	"job_board/jwt"
+	"job_board/middleware"
+	"job_board/models"
 )
 
 func FileRoutes(superRoute *gin.RouterGroup) {
 	fileRouter := superRoute.Group("/upload")
-	
-	fileRouter.POST("/", uploadHandler)
+	fileRouter.Use(jwt.Middleware())
+
+	fileRouter.POST("/", middleware.PermissionMiddleware(models.FileCreate), uploadHandler)
+	fileRouter.GET("/:id", middleware.PermissionMiddleware(models.FileReadOwn, models.FileReadCompany, models.FileReadAny), download)
+	fileRouter.DELETE("/:id", middleware.PermissionMiddleware(models.FileDeleteOwn, models.FileDeleteAny), delete)
 }
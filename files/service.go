@@ -0,0 +1,152 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"job_board/config"
+	"job_board/models"
+	"job_board/policy"
+)
+
+var database *gorm.DB
+
+// Setup gives the package its database handle and the storage uploads go to
+func Setup(db *gorm.DB, cfg config.Storage) error {
+	database = db
+	return setupStorage(cfg)
+}
+
+func createFile(file models.File) (*models.File, error) {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(&file).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error creating a new file: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return &file, nil
+}
+
+func getSingleFile(ID uuid.UUID, user models.User) (*models.File, error) {
+	var file models.File
+	if err := database.First(&file, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+
+	// candidates only get their own uploads, posters and admins read resumes they're shown
+	scopes := []policy.Scope{policy.Own(file.OwnerID)}
+	if sharedWithEmployers(file.OwnerID) {
+		scopes = append(scopes, policy.Applicant(file.OwnerID))
+	}
+	if err := policy.Check(user, "file:read", scopes...); err != nil {
+		return nil, fmt.Errorf("you don't have permission to view this file")
+	}
+	return &file, nil
+}
+
+// sharedWithEmployers reports whether the teams of the companies the owner applied to may
+// read their uploads, a private profile or hidden contact details keep them to the owner
+func sharedWithEmployers(ownerID uuid.UUID) bool {
+	var profile models.Profile
+	err := database.Select("visibility", "hide_contact").First(&profile, "user_id = ?", ownerID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	return profile.Visibility != models.ProfilePrivate && !profile.HideContact
+}
+
+func deleteSingleFile(ID uuid.UUID, user models.User) error {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var file models.File
+	if err := tx.First(&file, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := policy.Check(user, "file:delete", policy.Own(file.OwnerID)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this file")
+	}
+
+	var references int64
+	if err := tx.Model(&models.Profile{}).Where("resume_id = ?", ID).Count(&references).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if references == 0 {
+		if err := tx.Model(&models.WorkSample{}).Where("attachment_id = ?", ID).Count(&references).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if references > 0 {
+		tx.Rollback()
+		return fmt.Errorf("file is still used by your profile or work samples")
+	}
+
+	if err := tx.Delete(&file).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	// the row is gone already, a leftover object only costs space
+	if err := storage.Delete(context.Background(), file.Key); err != nil {
+		return fmt.Errorf("error removing stored file: %w", err)
+	}
+	return nil
+}
+
+// PutObject keeps r under key in the configured storage and returns the storage's name
+func PutObject(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	if err := storage.Put(ctx, key, r, size, contentType); err != nil {
+		return "", err
+	}
+	return storage.Name(), nil
+}
+
+// OpenObject reads what PutObject or an upload stored, objects of a storage that isn't
+// configured anymore can't be read
+func OpenObject(ctx context.Context, driver string, key string) (io.ReadCloser, error) {
+	if driver != storage.Name() {
+		return nil, fmt.Errorf("file is kept by the %s storage which isn't configured", driver)
+	}
+	return storage.Open(ctx, key)
+}
+
+// RemoveObjects deletes the stored uploads behind keys once their rows are gone, it goes
+// through every key and returns the first failure
+func RemoveObjects(ctx context.Context, keys []string) error {
+	var first error
+	for _, key := range keys {
+		if err := storage.Delete(ctx, key); err != nil && first == nil {
+			first = fmt.Errorf("error removing stored file: %w", err)
+		}
+	}
+	return first
+}
@@ -0,0 +1,87 @@
+package files
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"job_board/config"
+)
+
+// DefaultMaxSize is the upload limit when STORAGE_MAX_SIZE isn't set, 8mb like the multipart memory
+const DefaultMaxSize = 8 << 20
+
+// ErrNotFound is returned by backends when the key doesn't exist
+var ErrNotFound = errors.New("file not found")
+
+// Storage is where uploads end up, keys are generated by the files service and never come from clients
+type Storage interface {
+	// Name is stored with every file so a driver switch doesn't orphan older uploads
+	Name() string
+	// Put streams r under key, size is -1 when the length isn't known up front
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// docxType is a word document, http.DetectContentType only knows it as a zip
+const docxType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// sniffLen is how much of an upload is read to tell its type, enough to reach the part names
+// of a word document past the zip's first entries
+const sniffLen = 4096
+
+// allowedTypes are the sniffed content types resumes and work samples may have, the resume
+// import reads the pdf, word and plain text ones
+var allowedTypes = map[string]string{
+	"application/pdf":           ".pdf",
+	docxType:                    ".docx",
+	"text/plain; charset=utf-8": ".txt",
+	"image/jpeg":                ".jpg",
+	"image/png":                 ".png",
+}
+
+// sniffType is the content type of an upload from its first bytes, a zip is only taken for a
+// word document when it has the parts of one
+func sniffType(head []byte) string {
+	contentType := http.DetectContentType(head)
+	if contentType == "application/zip" &&
+		bytes.Contains(head, []byte("[Content_Types].xml")) && bytes.Contains(head, []byte("word/")) {
+		return docxType
+	}
+	return contentType
+}
+
+var (
+	storage Storage
+	maxSize int64 = DefaultMaxSize
+)
+
+// setupStorage picks the backend uploads go to
+func setupStorage(cfg config.Storage) error {
+	maxSize = cfg.MaxSize
+
+	var err error
+	switch cfg.Driver {
+	case "local":
+		storage, err = NewLocalStorage(cfg.LocalRoot)
+	case "s3":
+		storage, err = NewS3Storage(S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			PathStyle: cfg.S3.PathStyle,
+		})
+	default:
+		err = errors.New("unsupported storage driver " + cfg.Driver)
+	}
+	if err != nil {
+		return fmt.Errorf("error loading file storage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package files
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+// officeFile zips parts in the order word processors write them
+func officeFile(t *testing.T, parts ...string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for _, name := range parts {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Types/>`))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func head(data []byte) []byte {
+	if len(data) > sniffLen {
+		return data[:sniffLen]
+	}
+	return data
+}
+
+func TestSniffType(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		allowed bool
+	}{
+		{"pdf", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), "application/pdf", true},
+		{"word document", officeFile(t, "[Content_Types].xml", "_rels/.rels", "word/document.xml"), docxType, true},
+		{"spreadsheet", officeFile(t, "[Content_Types].xml", "_rels/.rels", "xl/workbook.xml"), "application/zip", false},
+		{"plain zip", officeFile(t, "notes.txt"), "application/zip", false},
+		{"utf-8 text", []byte("Ada Okafor\nBackend engineer, Lagos – Nigeria\n"), "text/plain; charset=utf-8", true},
+		{"utf-16 text", []byte("\xfe\xff\x00A\x00d\x00a"), "text/plain; charset=utf-16be", false},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png", true},
+		{"html", []byte("<!DOCTYPE html><html><script>alert(1)</script>"), "text/html; charset=utf-8", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sniffType(head(tt.data))
+			if got != tt.want {
+				t.Errorf("sniffType = %s, want %s", got, tt.want)
+			}
+			if _, ok := allowedTypes[got]; ok != tt.allowed {
+				t.Errorf("%s allowed = %v, want %v", got, ok, tt.allowed)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 /* gender segment  starts*/
@@ -43,9 +44,16 @@ func createGender(ctx *gin.Context) {
 
 func getGenders(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	genders, total, page, perPage, err := get(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	genders, err := get(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -57,12 +65,8 @@ func getGenders(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched genders",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     genders,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       genders,
+		Links:      genders.Links(ctx),
 	})
 }
 
@@ -8,14 +8,12 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-
 func GenderRoutes(superRoute *gin.RouterGroup) {
 	genderRouter := superRoute.Group("/genders")
 
-	genderRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), createGender)
+	genderRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), createGender)
 	genderRouter.GET("/", getGenders)
 	genderRouter.GET("/:id", getSingleGender)
-	genderRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), updateGender)
-	genderRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), deleteGender)
+	genderRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), updateGender)
+	genderRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), deleteGender)
 }
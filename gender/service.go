@@ -4,19 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func create(gender models.Gender) (*models.Gender, error) {
@@ -40,26 +39,7 @@ func create(gender models.Gender) (*models.Gender, error) {
 	return &gender, nil
 }
 
-func get(name string, pageSize string, pageNumber string) ([]models.Gender, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func get(name string, params pagination.Params) (*pagination.Page[models.Gender], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Gender{})
 
@@ -68,25 +48,13 @@ func get(name string, pageSize string, pageNumber string) ([]models.Gender, int6
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.Gender
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.Gender](db, params)
+	if err != nil {
 		log.Println("Error finding gender:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingle(ID uuid.UUID) (*models.Gender, error) {
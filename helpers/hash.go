@@ -1,9 +1,15 @@
 package helpers
 
+import (
+	"errors"
+	"strings"
+	"unicode"
 
+	"golang.org/x/crypto/bcrypt"
+)
 
-import "golang.org/x/crypto/bcrypt" 
-
+// PasswordCost is the bcrypt cost every stored password is hashed with
+const PasswordCost = 12
 
 func HashPassword(password string, cost int) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), cost)
@@ -13,4 +19,35 @@ func HashPassword(password string, cost int) (string, error) {
 func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
-}
\ No newline at end of file
+}
+
+// ValidatePassword enforces the strength rules for passwords users choose themselves
+func ValidatePassword(password string, email string) error {
+	if len(password) < 8 {
+		return errors.New("password must be at least 8 characters long")
+	}
+	// bcrypt ignores everything after 72 bytes
+	if len(password) > 72 {
+		return errors.New("password must be at most 72 characters long")
+	}
+
+	var upper, lower, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		return errors.New("password must contain an uppercase letter, a lowercase letter and a digit")
+	}
+
+	if name := strings.Split(strings.ToLower(email), "@")[0]; len(name) >= 3 && strings.Contains(strings.ToLower(password), name) {
+		return errors.New("password must not contain your email address")
+	}
+	return nil
+}
@@ -8,13 +8,24 @@ type Response struct {
 	Message    string      `json:"message"`
 	StatusCode int         `json:"statusCode"`
 	Data       interface{} `json:"data"`
+	Links      *Links      `json:"links,omitempty"`
+}
+
+// Links points to the neighbouring pages of a paginated response
+type Links struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
 }
 
 func CreateResponse(ctx *gin.Context, response Response) {
-	ctx.JSON(response.StatusCode, gin.H{
+	body := gin.H{
 		"message":    response.Message,
 		"data":       response.Data,
 		"statusCode": response.StatusCode,
-	})
+	}
+	if response.Links != nil {
+		body["links"] = response.Links
+	}
+	ctx.JSON(response.StatusCode, body)
 	ctx.Abort()
 }
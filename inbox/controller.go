@@ -0,0 +1,351 @@
+package inbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"job_board/helpers"
+	"job_board/models"
+	"job_board/pagination"
+)
+
+const (
+	// streamInterval is how often an open stream looks for new notifications
+	streamInterval = 3 * time.Second
+	// heartbeatInterval keeps proxies from closing a quiet stream
+	heartbeatInterval = 25 * time.Second
+)
+
+func get(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	params, err := pagination.FromContext(ctx, notificationSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	var filter Filter
+	if unread := ctx.Query("unread"); unread != "" {
+		filter.Unread, err = strconv.ParseBool(unread)
+		if err != nil {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    "unread must be true or false",
+				StatusCode: http.StatusBadRequest,
+				Data:       nil,
+			})
+			return
+		}
+	}
+	if category := ctx.Query("category"); category != "" {
+		filter.Category, err = models.ParseNotificationCategory(category)
+		if err != nil {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    err.Error(),
+				StatusCode: http.StatusBadRequest,
+				Data:       nil,
+			})
+			return
+		}
+	}
+
+	resp, err := getNotifications(*user, filter, params)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched notifications",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+		Links:      resp.Links(ctx),
+	})
+}
+
+func unreadCount(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	count, err := countUnread(*user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully counted unread notifications",
+		StatusCode: http.StatusOK,
+		Data:       UnreadCount{Unread: count},
+	})
+}
+
+func markRead(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req MarkReadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	// an empty list marks everything, so it has to be asked for explicitly
+	if len(req.IDs) == 0 && !req.All {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    "provide the ids to mark as read or set all to true",
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	if req.All {
+		req.IDs = nil
+	}
+
+	updated, err := markNotificationsRead(*user, req.IDs)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    fmt.Sprintf("Marked %d notifications as read", updated),
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func markSingleRead(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := markNotificationRead(ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusNotFound,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully marked notification as read",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func getPreferences(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getNotificationPreferences(*user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched notification preferences",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func updatePreference(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req PreferenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	category, err := models.ParseNotificationCategory(req.Category)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := saveNotificationPreference(*user, category, *req.Enabled)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully updated notification preference",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+// stream sends new notifications as server sent events until the client goes away. Each
+// event carries the notification id, so a client reconnecting with Last-Event-ID gets
+// what it missed
+func stream(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	cursor := resumeFrom(*user, ctx.GetHeader("Last-Event-ID"))
+	count, err := countUnread(*user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// nginx buffers responses unless told otherwise
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	writeEvent(ctx.Writer, "", "unread", UnreadCount{Unread: count})
+	ctx.Writer.Flush()
+
+	poll := time.NewTicker(streamInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case <-poll.C:
+			notifications, err := notificationsAfter(*user, cursor)
+			if err != nil {
+				log.Println("Error polling notifications:", err)
+				return false
+			}
+			for _, notification := range notifications {
+				writeEvent(w, notification.ID.String(), "notification", notification)
+				cursor.advance(notification)
+			}
+		}
+		return true
+	})
+}
+
+func writeEvent(w io.Writer, id string, event string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, raw)
+}
@@ -0,0 +1,35 @@
+package inbox
+
+import (
+	"github.com/google/uuid"
+
+	"job_board/models"
+	"job_board/pagination"
+)
+
+var notificationSorts = pagination.Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at": "created_at",
+	},
+}
+
+type Filter struct {
+	Unread   bool
+	Category models.NotificationCategory
+}
+
+// MarkReadRequest marks the listed notifications as read, or every unread one with all
+type MarkReadRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"omitempty,max=100"`
+	All bool        `json:"all" binding:"omitempty"`
+}
+
+type PreferenceRequest struct {
+	Category string `json:"category" binding:"required"`
+	Enabled  *bool  `json:"enabled" binding:"required"`
+}
+
+type UnreadCount struct {
+	Unread int64 `json:"unread"`
+}
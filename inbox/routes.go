@@ -0,0 +1,17 @@
+package inbox
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// InboxRoutes registers the feed of the signed in user, the router is expected to carry
+// the jwt middleware
+func InboxRoutes(inboxRouter *gin.RouterGroup) {
+	inboxRouter.GET("/", get)
+	inboxRouter.GET("/unread-count", unreadCount)
+	inboxRouter.GET("/stream", stream)
+	inboxRouter.POST("/read", markRead)
+	inboxRouter.PATCH("/:id/read", markSingleRead)
+	inboxRouter.GET("/preferences", getPreferences)
+	inboxRouter.PUT("/preferences", updatePreference)
+}
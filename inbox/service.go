@@ -0,0 +1,210 @@
+package inbox
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"job_board/models"
+	"job_board/pagination"
+)
+
+var database *gorm.DB
+
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
+}
+
+// Notify adds a notification to the user's feed in tx, so it only shows up once the change
+// it is about commits. Nothing is added when the user turned the category off
+func Notify(tx *gorm.DB, notification models.Notification) error {
+	var disabled int64
+	if err := tx.Model(&models.NotificationPreference{}).
+		Where("user_id = ? AND category = ? AND enabled = ?", notification.UserID, notification.Category, false).
+		Count(&disabled).Error; err != nil {
+		return fmt.Errorf("error reading notification preferences: %w", err)
+	}
+	if disabled > 0 {
+		return nil
+	}
+	if err := tx.Create(&notification).Error; err != nil {
+		return fmt.Errorf("error creating %s notification: %w", notification.Category, err)
+	}
+	return nil
+}
+
+func getNotifications(user models.User, filter Filter, params pagination.Params) (*pagination.Page[models.Notification], error) {
+	db := database.Model(&models.Notification{}).Where("user_id = ?", user.ID)
+	if filter.Unread {
+		db = db.Where("read_at IS NULL")
+	}
+	if filter.Category != "" {
+		db = db.Where("category = ?", filter.Category)
+	}
+
+	data, err := pagination.Find[models.Notification](db, params)
+	if err != nil {
+		log.Println("Error finding notifications:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func countUnread(user models.User) (int64, error) {
+	var count int64
+	if err := database.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", user.ID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// markNotificationsRead marks the user's notifications in ids as read, every unread one
+// when ids is empty, and returns how many changed
+func markNotificationsRead(user models.User, ids []uuid.UUID) (int64, error) {
+	db := database.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", user.ID)
+	if len(ids) > 0 {
+		db = db.Where("id IN ?", ids)
+	}
+	result := db.Update("read_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("error marking notifications as read: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func markNotificationRead(ID uuid.UUID, user models.User) (*models.Notification, error) {
+	var record models.Notification
+	if err := database.First(&record, "id = ? AND user_id = ?", ID, user.ID).Error; err != nil {
+		return nil, err
+	}
+	if record.ReadAt != nil {
+		return &record, nil
+	}
+
+	now := time.Now()
+	if err := database.Model(&record).Update("read_at", now).Error; err != nil {
+		return nil, fmt.Errorf("error marking notification as read: %w", err)
+	}
+	record.ReadAt = &now
+	return &record, nil
+}
+
+// getNotificationPreferences lists every category with whether it is on for the user
+func getNotificationPreferences(user models.User) ([]models.NotificationPreference, error) {
+	var stored []models.NotificationPreference
+	if err := database.Where("user_id = ?", user.ID).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	byCategory := make(map[models.NotificationCategory]models.NotificationPreference, len(stored))
+	for _, preference := range stored {
+		byCategory[preference.Category] = preference
+	}
+	data := make([]models.NotificationPreference, 0, len(models.NotificationCategories))
+	for _, category := range models.NotificationCategories {
+		preference, ok := byCategory[category]
+		if !ok {
+			preference = models.NotificationPreference{UserID: user.ID, Category: category, Enabled: true}
+		}
+		data = append(data, preference)
+	}
+	return data, nil
+}
+
+func saveNotificationPreference(user models.User, category models.NotificationCategory, enabled bool) (*models.NotificationPreference, error) {
+	preference := models.NotificationPreference{
+		UserID:   user.ID,
+		Category: category,
+		Enabled:  enabled,
+	}
+	if err := database.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preference).Error; err != nil {
+		return nil, fmt.Errorf("error saving notification preference: %w", err)
+	}
+	return &preference, nil
+}
+
+// streamOverlap is how far back every poll of the stream looks again. created_at is set
+// before the insert commits, so a notification can show up after a newer one was streamed
+const streamOverlap = 10 * time.Second
+
+// streamCursor is how far a stream got, the newest created_at it sent and the ids it sent
+// within the overlap, those are skipped when the overlap finds them again
+type streamCursor struct {
+	since time.Time
+	sent  map[uuid.UUID]time.Time
+}
+
+func newStreamCursor(since time.Time) *streamCursor {
+	return &streamCursor{since: since, sent: map[uuid.UUID]time.Time{}}
+}
+
+// advance records a streamed notification and forgets those the overlap no longer reaches
+func (c *streamCursor) advance(notification models.Notification) {
+	c.sent[notification.ID] = notification.CreatedAt
+	if notification.CreatedAt.After(c.since) {
+		c.since = notification.CreatedAt
+	}
+	for ID, createdAt := range c.sent {
+		if !createdAt.After(c.from()) {
+			delete(c.sent, ID)
+		}
+	}
+}
+
+// from is where the next poll starts looking
+func (c *streamCursor) from() time.Time {
+	return c.since.Add(-streamOverlap)
+}
+
+// notificationsAfter returns the user's notifications the stream hasn't sent yet, oldest
+// first, it is what the stream sends when it polls
+func notificationsAfter(user models.User, cursor *streamCursor) ([]models.Notification, error) {
+	db := database.Where("user_id = ? AND created_at > ?", user.ID, cursor.from())
+	if len(cursor.sent) > 0 {
+		sent := make([]uuid.UUID, 0, len(cursor.sent))
+		for ID := range cursor.sent {
+			sent = append(sent, ID)
+		}
+		db = db.Where("id NOT IN ?", sent)
+	}
+
+	var data []models.Notification
+	if err := db.
+		Order("created_at ASC, id ASC").
+		Limit(pagination.MaxPageSize).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// resumeFrom is where a reconnecting stream picks up, the last notification the client
+// saw or now when it can't be found. Notifications in the overlap before the last one
+// are sent again, clients drop the ids they already have
+func resumeFrom(user models.User, lastEventID string) *streamCursor {
+	ID, err := uuid.Parse(lastEventID)
+	if err != nil {
+		return newStreamCursor(time.Now())
+	}
+	var record models.Notification
+	if err := database.First(&record, "id = ? AND user_id = ?", ID, user.ID).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("Error finding last streamed notification:", err)
+		}
+		return newStreamCursor(time.Now())
+	}
+	cursor := newStreamCursor(record.CreatedAt)
+	cursor.advance(record)
+	return cursor
+}
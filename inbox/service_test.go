@@ -0,0 +1,44 @@
+package inbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"job_board/models"
+)
+
+func TestStreamCursor(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cursor := newStreamCursor(start)
+	at := func(seconds int) models.Notification {
+		return models.Notification{ID: uuid.New(), CreatedAt: start.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	first, second := at(1), at(1)
+	cursor.advance(first)
+	cursor.advance(second)
+	if !cursor.since.Equal(first.CreatedAt) || len(cursor.sent) != 2 {
+		t.Fatalf("two notifications of the same instant: since %s, sent %v", cursor.since, cursor.sent)
+	}
+	if want := start.Add(time.Second - streamOverlap); !cursor.from().Equal(want) {
+		t.Errorf("from %s, want %s", cursor.from(), want)
+	}
+
+	// one that committed late doesn't take the cursor back
+	late := at(-2)
+	cursor.advance(late)
+	if !cursor.since.Equal(first.CreatedAt) {
+		t.Errorf("a late notification moved since to %s", cursor.since)
+	}
+	if _, ok := cursor.sent[late.ID]; !ok {
+		t.Error("a late notification wasn't recorded as sent")
+	}
+
+	// once the overlap moves past them they are forgotten
+	cursor.advance(at(60))
+	if len(cursor.sent) != 1 {
+		t.Errorf("sent %d ids, only the newest is inside the overlap", len(cursor.sent))
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 /* internship experience segment  starts*/
@@ -114,7 +115,16 @@ func GetInternShipExperience(ctx *gin.Context) {
 		EndDate:     endDate,
 	}
 
-	internships, total, page, perPage, err := getInternship(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	internships, err := getInternship(filter, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -126,12 +136,8 @@ func GetInternShipExperience(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched internships",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     internships,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       internships,
+		Links:      internships.Links(ctx),
 	})
 
 }
@@ -3,19 +3,21 @@ package internship
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/completeness"
+	"job_board/matching"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func checkProfile(user models.User) bool {
@@ -47,29 +49,13 @@ func createInternship(internship models.InternShipExperience, user models.User)
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(internship.ProfileID)
+	completeness.Queue(internship.ProfileID)
+
 	return &internship, nil
 }
 
-func getInternship(filter Search, pageSize string, pageNumber string) ([]models.InternShipExperience, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getInternship(filter Search, params pagination.Params) (*pagination.Page[models.InternShipExperience], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.InternShipExperience{})
 
@@ -95,25 +81,13 @@ func getInternship(filter Search, pageSize string, pageNumber string) ([]models.
 		db = db.Where("end_date <= ?", endDateStr)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.InternShipExperience
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.InternShipExperience](db, params)
+	if err != nil {
 		log.Println("Error finding education:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleInternship(ID uuid.UUID, user models.User) (*models.InternShipExperience, error) {
@@ -127,7 +101,7 @@ func getSingleInternship(ID uuid.UUID, user models.User) (*models.InternShipExpe
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole && record.ProfileID != user.Profile.ID {
+	if err := policy.Check(user, "profile:read", policy.OwnProfile(record.ProfileID)); err != nil {
 		return nil, fmt.Errorf("you don't have permission to view this record")
 	}
 
@@ -148,13 +122,9 @@ func updateInternship(ID uuid.UUID, user models.User, updates Request) (*models.
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole {
-		if profile := checkProfile(user); profile {
-			return nil, fmt.Errorf("you don't have a profile")
-		}
-		if existingRecord.ProfileID != user.Profile.ID {
-			return nil, fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:update", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
 	}
 
 	// Update the record with the provided updates
@@ -166,6 +136,9 @@ func updateInternship(ID uuid.UUID, user models.User, updates Request) (*models.
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
+
 	return &existingRecord, nil
 }
 
@@ -183,10 +156,9 @@ func deleteSingleInternship(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName == models.UserRole {
-		if existingRecord.ProfileID != user.Profile.ID {
-			return fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:delete", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.InternShipExperience{}, "id = ?", ID)
@@ -205,5 +177,8 @@ func deleteSingleInternship(ID uuid.UUID, user models.User) error {
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
 	return nil
 }
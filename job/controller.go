@@ -12,6 +12,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 func create(ctx *gin.Context) {
@@ -35,25 +36,63 @@ func create(ctx *gin.Context) {
 		return
 	}
 
+	payPeriod, err := models.ParsePayPeriod(string(req.PayPeriod))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
 	newJob := models.Job{
-		UserID:      user.ID,
-		Title:       req.Title,
-		Description: req.Description,
-		CountryID:   req.CountryID,
-		Salary:      req.Salary,
-		JobTypeID:   req.JobTypeID,
-		LevelID:     req.LevelID,
-		Skills:      pq.StringArray(req.Skills),
-		CompanyID:   req.CompanyID,
+		UserID:           user.ID,
+		Title:            req.Title,
+		Description:      req.Description,
+		CountryID:        req.CountryID,
+		SalaryMin:        req.SalaryMin,
+		SalaryMax:        req.SalaryMax,
+		SalaryCurrencyID: req.SalaryCurrencyID,
+		PayPeriod:        payPeriod,
+		SalaryHidden:     req.SalaryHidden != nil && *req.SalaryHidden,
+		JobTypeID:        req.JobTypeID,
+		LevelID:          req.LevelID,
+		Skills:           pq.StringArray(req.Skills),
+		CompanyID:        req.CompanyID,
+	}
+
+	// without a draft flag or a future publish date the job goes live right away
+	now := time.Now()
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    "expires_at must be in the future",
+				StatusCode: http.StatusBadRequest,
+				Data:       nil,
+			})
+			return
+		}
+		newJob.ExpiresAt = req.ExpiresAt
+	}
+	switch {
+	case req.Draft:
+		newJob.State = models.Draft
+	case req.PublishAt != nil && req.PublishAt.After(now):
+		newJob.State = models.Scheduled
+		newJob.PublishAt = req.PublishAt
+	default:
+		newJob.Publish(now)
 	}
 
-	resp, err := createJob(newJob)
+	resp, err := createJob(ctx.Request.Context(), newJob, *user)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
 			StatusCode: http.StatusBadRequest,
 			Data:       nil,
 		})
+		return
 	}
 
 	helpers.CreateResponse(ctx, helpers.Response{
@@ -61,8 +100,6 @@ func create(ctx *gin.Context) {
 		StatusCode: http.StatusOK,
 		Data:       resp,
 	})
-
-	/* job alerts for everyone in this location */
 }
 
 func get(ctx *gin.Context) {
@@ -72,7 +109,6 @@ func get(ctx *gin.Context) {
 		levelID   uuid.UUID
 		companyID uuid.UUID
 		skills    []string
-		salary    float64
 		err       error
 		errsArr   []string
 	)
@@ -101,13 +137,16 @@ func get(ctx *gin.Context) {
 		}
 	}
 	if skill := ctx.Query("skill"); skill != "" {
-		skills = append(skills, skill)
+		skills = append(skills, strings.Split(skill, ",")...)
 	}
-	if val := ctx.Query("salary"); val != "" {
-		if salary, err = strconv.ParseFloat(val, 10); err != nil {
+	var state models.JobState
+	if val := ctx.Query("state"); val != "" {
+		if state, err = models.ParseJobState(val); err != nil {
 			errsArr = append(errsArr, err.Error())
 		}
 	}
+	salary, salaryErrs := parseSalaryFilter(ctx)
+	errsArr = append(errsArr, salaryErrs...)
 	if len(errsArr) > 0 {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    strings.Join(errsArr, ","),
@@ -123,12 +162,21 @@ func get(ctx *gin.Context) {
 		LevelID:     levelID,
 		CountryID:   countryID,
 		Skills:      skills,
-		Salary:      salary,
 		Title:       ctx.Query("title"),
 		Description: ctx.Query("description"),
 	}
 
-	resp, total, page, perPage, err := getJob(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, jobSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	user, _ := models.GetUserFromContext(ctx)
+	resp, err := getJob(filter, salary, state, user, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -137,19 +185,164 @@ func get(ctx *gin.Context) {
 		})
 		return
 	}
+
+	for i := range resp.Data {
+		resp.Data[i].MaskSalary(user)
+	}
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched jobs",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     resp,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       resp,
+		Links:      resp.Links(ctx),
 	})
 
 }
 
+func search(ctx *gin.Context) {
+	var (
+		countryID uuid.UUID
+		jobTypeID uuid.UUID
+		levelID   uuid.UUID
+		companyID uuid.UUID
+		skillsAny []string
+		skillsAll []string
+		err       error
+		errsArr   []string
+	)
+
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" {
+		errsArr = append(errsArr, "q is required")
+	}
+
+	mode, err := models.ParseSearchMode(ctx.Query("mode"))
+	if err != nil {
+		errsArr = append(errsArr, err.Error())
+	}
+
+	if id := ctx.Query("country_id"); id != "" {
+		if countryID, err = uuid.Parse(id); err != nil {
+			errsArr = append(errsArr, err.Error())
+		}
+	}
+
+	if id := ctx.Query("job_type_id"); id != "" {
+		if jobTypeID, err = uuid.Parse(id); err != nil {
+			errsArr = append(errsArr, err.Error())
+		}
+	}
+
+	if id := ctx.Query("level_id"); id != "" {
+		if levelID, err = uuid.Parse(id); err != nil {
+			errsArr = append(errsArr, err.Error())
+		}
+	}
+
+	if id := ctx.Query("company_id"); id != "" {
+		if companyID, err = uuid.Parse(id); err != nil {
+			errsArr = append(errsArr, err.Error())
+		}
+	}
+	if val := ctx.Query("skills_any"); val != "" {
+		skillsAny = strings.Split(val, ",")
+	}
+	if val := ctx.Query("skills_all"); val != "" {
+		skillsAll = strings.Split(val, ",")
+	}
+	salary, salaryErrs := parseSalaryFilter(ctx)
+	errsArr = append(errsArr, salaryErrs...)
+	if len(errsArr) > 0 {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    strings.Join(errsArr, ","),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	filter := SearchRequest{
+		Query:     query,
+		Mode:      mode,
+		CountryID: countryID,
+		JobTypeID: jobTypeID,
+		LevelID:   levelID,
+		CompanyID: companyID,
+		SkillsAny: skillsAny,
+		SkillsAll: skillsAll,
+		Salary:    salary,
+	}
+
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	resp, err := searchJobs(filter, params)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	user, _ := models.GetUserFromContext(ctx)
+	for i := range resp.Data {
+		resp.Data[i].Job.MaskSalary(user)
+	}
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "successfully searched jobs",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+		Links:      resp.Links(ctx),
+	})
+}
+
+// parseSalaryFilter reads salary_min, salary_max, salary_currency_id and pay_period, the old
+// salary parameter is kept as the top of the range
+func parseSalaryFilter(ctx *gin.Context) (SalaryFilter, []string) {
+	var (
+		filter  SalaryFilter
+		err     error
+		errsArr []string
+	)
+
+	if val := ctx.Query("salary_min"); val != "" {
+		if filter.Min, err = strconv.ParseFloat(val, 64); err != nil {
+			errsArr = append(errsArr, err.Error())
+		}
+	}
+	if val := ctx.Query("salary_max"); val != "" {
+		if filter.Max, err = strconv.ParseFloat(val, 64); err != nil {
+			errsArr = append(errsArr, err.Error())
+		}
+	} else if val := ctx.Query("salary"); val != "" {
+		if filter.Max, err = strconv.ParseFloat(val, 64); err != nil {
+			errsArr = append(errsArr, err.Error())
+		}
+	}
+	if filter.Max != 0 && filter.Min > filter.Max {
+		errsArr = append(errsArr, "salary_min can't be more than salary_max")
+	}
+	if id := ctx.Query("salary_currency_id"); id != "" {
+		currencyID, err := uuid.Parse(id)
+		if err != nil {
+			errsArr = append(errsArr, err.Error())
+		}
+		filter.CurrencyID = &currencyID
+	}
+	if filter.PayPeriod, err = models.ParsePayPeriod(ctx.Query("pay_period")); err != nil {
+		errsArr = append(errsArr, err.Error())
+	}
+
+	return filter, errsArr
+}
+
 func getSingle(ctx *gin.Context) {
 	user, err := models.GetUserFromContext(ctx)
 	if err != nil {
@@ -180,6 +373,7 @@ func getSingle(ctx *gin.Context) {
 		})
 		return
 	}
+	resp.MaskSalary(user)
 
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "Successfully fetched job",
@@ -218,8 +412,18 @@ func update(ctx *gin.Context) {
 		})
 		return
 	}
+	if req.PayPeriod != "" {
+		if _, err := models.ParsePayPeriod(string(req.PayPeriod)); err != nil {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    err.Error(),
+				StatusCode: http.StatusBadRequest,
+				Data:       nil,
+			})
+			return
+		}
+	}
 
-	resp, err := updateJob(ID, *user, req)
+	resp, err := updateJob(ctx.Request.Context(), ID, *user, req)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -238,6 +442,64 @@ func update(ctx *gin.Context) {
 	/* if the country is being change and the job is less than 24 hours send alert*/
 }
 
+func updateState(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req JobStateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	state, err := models.ParseJobState(req.State)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := changeJobState(ctx.Request.Context(), ID, *user, state, req.PublishAt, req.ExpiresAt)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully updated job state",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
 func delete(ctx *gin.Context) {
 	user, err := models.GetUserFromContext(ctx)
 	if err != nil {
@@ -259,7 +521,7 @@ func delete(ctx *gin.Context) {
 		return
 	}
 
-	err = deleteSingleJob(ID, *user)
+	err = deleteSingleJob(ctx.Request.Context(), ID, *user)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -307,9 +569,16 @@ func createLevel(ctx *gin.Context) {
 
 func getLevel(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	levels, total, page, perPage, err := getLevelFunc(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	levels, err := getLevelFunc(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -321,12 +590,8 @@ func getLevel(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched levels",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     levels,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       levels,
+		Links:      levels.Links(ctx),
 	})
 }
 
@@ -448,9 +713,16 @@ func createType(ctx *gin.Context) {
 
 func getType(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	types, total, page, perPage, err := getJobType(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	types, err := getJobType(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -462,12 +734,8 @@ func getType(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched types",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     types,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       types,
+		Links:      types.Links(ctx),
 	})
 }
 
@@ -582,11 +850,11 @@ func createApplication(ctx *gin.Context) {
 	newProject := models.JobApplication{
 		JobID:       req.JobID,
 		ApplicantID: user.ID,
-		Status:      models.Pending,
+		Status:      models.Applied,
 		AppliedAt:   time.Now(),
 	}
 
-	resp, err := createJobApplication(newProject, *user)
+	resp, err := createJobApplication(ctx.Request.Context(), newProject, *user)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -663,7 +931,16 @@ func getApplication(ctx *gin.Context) {
 		AppliedAt:   appliedAt,
 	}
 
-	resp, total, page, perPage, err := getJobApplication(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	resp, err := getJobApplication(filter, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -675,12 +952,8 @@ func getApplication(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched application",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     resp,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       resp,
+		Links:      resp.Links(ctx),
 	})
 }
 
@@ -705,7 +978,16 @@ func getPosterJobApplication(ctx *gin.Context) {
 		return
 	}
 
-	resp, total, page, perPage, err := getApplications(ID, *user, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	resp, err := getApplications(ID, *user, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -717,12 +999,8 @@ func getPosterJobApplication(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched applications",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     resp,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       resp,
+		Links:      resp.Links(ctx),
 	})
 }
 
@@ -796,6 +1074,34 @@ func updateApplication(ctx *gin.Context) {
 	}
 
 	status, err := models.ParseStatus(req.Status)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := updateJobApplication(ctx.Request.Context(), ID, *user, status, req.StageID, req.Note)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully updated status",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func deleteApplication(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -805,7 +1111,36 @@ func updateApplication(ctx *gin.Context) {
 		return
 	}
 
-	if err = models.CheckStatus(status); err != nil {
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	err = deleteSingleJobApplication(ctx.Request.Context(), ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully deleted application",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+func getApplicationHistoryHandler(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
 			StatusCode: http.StatusInternalServerError,
@@ -814,7 +1149,7 @@ func updateApplication(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := updateJobApplication(ID, *user, status)
+	ID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -824,14 +1159,24 @@ func updateApplication(ctx *gin.Context) {
 		return
 	}
 
+	resp, err := getApplicationHistory(ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
 	helpers.CreateResponse(ctx, helpers.Response{
-		Message:    "Successfully updated status",
+		Message:    "Successfully fetched application history",
 		StatusCode: http.StatusOK,
 		Data:       resp,
 	})
 }
 
-func deleteApplication(ctx *gin.Context) {
+func getApplicantProfileHandler(ctx *gin.Context) {
 	user, err := models.GetUserFromContext(ctx)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
@@ -852,7 +1197,60 @@ func deleteApplication(ctx *gin.Context) {
 		return
 	}
 
-	err = deleteSingleJobApplication(ID, *user)
+	resp, err := getApplicantProfile(ID, *user)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched applicant profile",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func createStageHandler(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req StageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	status, err := models.ParseStatus(req.Status)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := createStage(models.PipelineStage{
+		CompanyID: req.CompanyID,
+		Name:      req.Name,
+		Status:    status,
+		Position:  req.Position,
+	}, *user)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -863,7 +1261,134 @@ func deleteApplication(ctx *gin.Context) {
 	}
 
 	helpers.CreateResponse(ctx, helpers.Response{
-		Message:    "Successfully deleted application",
+		Message:    "Successfully created stage",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func getStagesHandler(ctx *gin.Context) {
+	companyID, err := uuid.Parse(ctx.Query("company_id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := getStages(companyID)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched stages",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func updateStageHandler(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req StageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	status, err := models.ParseStatus(req.Status)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := updateStage(ID, *user, map[string]interface{}{
+		"name":     req.Name,
+		"status":   status,
+		"position": req.Position,
+	})
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully updated stage",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func deleteStageHandler(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	if err := deleteSingleStage(ID, *user); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully deleted stage",
 		StatusCode: http.StatusOK,
 		Data:       nil,
 	})
@@ -3,10 +3,21 @@ package job
 import (
 	"github.com/google/uuid"
 	"job_board/models"
+	"job_board/pagination"
 
 	"time"
 )
 
+var jobSorts = pagination.Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"title":      "title",
+		"salary":     "salary_sort",
+	},
+}
+
 type Request struct {
 	Name string `json:"name" binding:"required"`
 }
@@ -15,11 +26,55 @@ type JobRequest struct {
 	Title       string    `json:"title" binding:"required"`
 	Description string    `json:"description" binding:"required"`
 	CountryID   uuid.UUID `json:"country_id" binding:"required"`
-	Salary      float64   `json:"country_id" binding:"required"`
 	JobTypeID   uuid.UUID `json:"job_type_id" binding:"required"`
 	LevelID     uuid.UUID `json:"level_id" binding:"required"`
 	Skills      []string  `json:"skills" binding:"required"`
 	CompanyID   uuid.UUID `json:"company_id" binding:"required"`
+
+	SalaryMin        float64          `json:"salary_min" binding:"omitempty,gte=0"`
+	SalaryMax        float64          `json:"salary_max" binding:"omitempty,gte=0"`
+	SalaryCurrencyID *uuid.UUID       `json:"salary_currency_id" binding:"omitempty"`
+	PayPeriod        models.PayPeriod `json:"pay_period" binding:"omitempty"`
+	SalaryHidden     *bool            `json:"salary_hidden" binding:"omitempty"`
+
+	// lifecycle is only set on create, afterwards it goes through the state endpoint
+	Draft     bool       `json:"draft" binding:"omitempty" gorm:"-"`
+	PublishAt *time.Time `json:"publish_at" binding:"omitempty" gorm:"-"`
+	ExpiresAt *time.Time `json:"expires_at" binding:"omitempty" gorm:"-"`
+}
+
+type JobStateRequest struct {
+	State     string     `json:"state" binding:"required"`
+	PublishAt *time.Time `json:"publish_at" binding:"omitempty"`
+	ExpiresAt *time.Time `json:"expires_at" binding:"omitempty"`
+}
+
+// SalaryFilter is the salary range a search asks for, in its own currency and pay period
+type SalaryFilter struct {
+	Min        float64
+	Max        float64
+	CurrencyID *uuid.UUID
+	PayPeriod  models.PayPeriod
+}
+
+type SearchRequest struct {
+	Query     string
+	Mode      models.SearchMode
+	CountryID uuid.UUID
+	JobTypeID uuid.UUID
+	LevelID   uuid.UUID
+	CompanyID uuid.UUID
+	SkillsAny []string
+	SkillsAll []string
+	Salary    SalaryFilter
+}
+
+type JobSearchResult struct {
+	JobID          uuid.UUID   `json:"-"`
+	Rank           float64     `json:"rank"`
+	TitleHighlight string      `json:"title_highlight"`
+	Snippet        string      `json:"snippet"`
+	Job            *models.Job `json:"job" gorm:"-"`
 }
 
 type ApplicationRequest struct {
@@ -27,9 +82,17 @@ type ApplicationRequest struct {
 }
 
 type UpdateApplicationRequest struct {
-	Status      string`json:"status" binding:"required"`
+	Status  string     `json:"status" binding:"required"`
+	StageID *uuid.UUID `json:"stage_id" binding:"omitempty"`
+	Note    string     `json:"note" binding:"omitempty"`
 }
 
+type StageRequest struct {
+	CompanyID uuid.UUID `json:"company_id" binding:"required"`
+	Name      string    `json:"name" binding:"required"`
+	Status    string    `json:"status" binding:"required"`
+	Position  int       `json:"position" binding:"omitempty"`
+}
 
 type SearchApplication struct {
 	JobID       uuid.UUID     `json:"job_id" binding:"required"`
@@ -37,4 +100,3 @@ type SearchApplication struct {
 	Status      models.Status `json:"status" binding:"required"`
 	AppliedAt   time.Time     `json:"time" binding:"required"`
 }
-
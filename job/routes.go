@@ -8,45 +8,62 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-var everybody []models.RoleAllowed = []models.RoleAllowed{models.PosterRole, models.AdminRole, models.SuperAdminRole}
+var readApplications = []models.Permission{models.ApplicationReadOwn, models.ApplicationReadCompany, models.ApplicationReadAny}
+var managePipelines = []models.Permission{models.PipelineManageCompany, models.PipelineManageAny}
 
+// JobRoutes serves jobs under /companies, where clients have always found them, and
+// under /jobs as an alias
 func JobRoutes(superRoute *gin.RouterGroup) {
-	jobRouter := superRoute.Group("/companies")
+	setupJobRoutes(superRoute.Group("/companies"))
+	setupJobRoutes(superRoute.Group("/jobs"))
+}
 
+func setupJobRoutes(jobRouter *gin.RouterGroup) {
 	jobRouter.Use(jwt.Middleware())
-	jobRouter.POST("/", middleware.RolesMiddleware(everybody), create)
-	jobRouter.GET("/", middleware.RolesMiddleware(everybody), get)
+	jobRouter.POST("/", middleware.PermissionMiddleware(models.JobCreate), create)
+	jobRouter.GET("/", middleware.PermissionMiddleware(models.JobReadCompany, models.JobReadAny), get)
+	jobRouter.GET("/search", search)
 	jobRouter.GET("/:id", getSingle)
-	jobRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), update)
-	jobRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), delete)
+	jobRouter.PATCH("/:id", middleware.PermissionMiddleware(models.JobUpdateCompany, models.JobUpdateAny), update)
+	jobRouter.PATCH("/:id/state", middleware.PermissionMiddleware(models.JobUpdateCompany, models.JobUpdateAny), updateState)
+	jobRouter.DELETE("/:id", middleware.PermissionMiddleware(models.JobDeleteCompany, models.JobDeleteAny), delete)
 
 	setupLevelRoutes(jobRouter.Group("/levels"))
 	setupTypeRoutes(jobRouter.Group("/types"))
 	setupApplicationRoutes(jobRouter.Group("/applications"))
+	setupStageRoutes(jobRouter.Group("/stages"))
 }
 
 func setupLevelRoutes(levelRouter *gin.RouterGroup) {
-	levelRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), createLevel)
+	levelRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), createLevel)
 	levelRouter.GET("/", getLevel)
 	levelRouter.GET("/:id", getSingleLevel)
-	levelRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), updateLevel)
-	levelRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), deleteLevel)
+	levelRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), updateLevel)
+	levelRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), deleteLevel)
 }
 
 func setupTypeRoutes(sizesRouter *gin.RouterGroup) {
-	sizesRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), createType)
+	sizesRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), createType)
 	sizesRouter.GET("/", getType)
 	sizesRouter.GET("/:id", getSingleType)
-	sizesRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), updateType)
-	sizesRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), deleteType)
+	sizesRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), updateType)
+	sizesRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), deleteType)
 }
 
 func setupApplicationRoutes(sizesRouter *gin.RouterGroup) {
-	sizesRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware([]models.RoleAllowed{models.UserRole}), createApplication)
-	sizesRouter.GET("/", jwt.Middleware(), middleware.RolesMiddleware([]models.RoleAllowed{models.AdminRole, models.SuperAdminRole}), getApplication)
-	sizesRouter.GET("/application/:id", jwt.Middleware(), middleware.RolesMiddleware([]models.RoleAllowed{models.PosterRole}), getPosterJobApplication)
-	sizesRouter.GET("/:id", middleware.RolesMiddleware([]models.RoleAllowed{models.UserRole, models.PosterRole, models.AdminRole, models.SuperAdminRole}), getSingleType)
-	sizesRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(everybody), updateType)
-	sizesRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(everybody), deleteType)
+	sizesRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.ApplicationCreate), createApplication)
+	sizesRouter.GET("/", jwt.Middleware(), middleware.PermissionMiddleware(models.ApplicationReadAny), getApplication)
+	sizesRouter.GET("/application/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.ApplicationReadCompany, models.ApplicationReadAny), getPosterJobApplication)
+	sizesRouter.GET("/:id", middleware.PermissionMiddleware(readApplications...), getSingleApplication)
+	sizesRouter.GET("/:id/history", middleware.PermissionMiddleware(readApplications...), getApplicationHistoryHandler)
+	sizesRouter.GET("/:id/profile", middleware.PermissionMiddleware(readApplications...), getApplicantProfileHandler)
+	sizesRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.ApplicationWithdrawOwn, models.ApplicationUpdateCompany, models.ApplicationUpdateAny), updateApplication)
+	sizesRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.ApplicationDeleteAny), deleteApplication)
+}
+
+func setupStageRoutes(stageRouter *gin.RouterGroup) {
+	stageRouter.POST("/", middleware.PermissionMiddleware(managePipelines...), createStageHandler)
+	stageRouter.GET("/", getStagesHandler)
+	stageRouter.PATCH("/:id", middleware.PermissionMiddleware(managePipelines...), updateStageHandler)
+	stageRouter.DELETE("/:id", middleware.PermissionMiddleware(managePipelines...), deleteStageHandler)
 }
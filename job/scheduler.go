@@ -0,0 +1,129 @@
+package job
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"job_board/alert"
+	"job_board/inbox"
+	"job_board/models"
+	"job_board/notifications"
+)
+
+// schedulerInterval is how often scheduled, expiring and expired jobs are looked for
+const schedulerInterval = time.Minute
+
+// expiryNotice is how long before expiry the poster hears about it, Setup sets it from
+// JOB_EXPIRY_NOTICE_DAYS
+var expiryNotice = 3 * 24 * time.Hour
+
+// announceJob tells saved searches about a job that just went live
+func announceJob(jobID uuid.UUID) {
+	alert.QueueJob(jobID)
+}
+
+// StartScheduler runs the job lifecycle in the background, it publishes scheduled jobs,
+// warns posters of jobs about to expire and expires the ones past their date
+func StartScheduler() {
+	go func() {
+		ticker := time.NewTicker(schedulerInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			now := time.Now()
+			if err := publishScheduledJobs(now); err != nil {
+				log.Printf("Failed to publish scheduled jobs: %v", err)
+			}
+			if err := notifyExpiringJobs(now); err != nil {
+				log.Printf("Failed to notify posters of expiring jobs: %v", err)
+			}
+			if err := expireJobs(now); err != nil {
+				log.Printf("Failed to expire jobs: %v", err)
+			}
+		}
+	}()
+}
+
+func publishScheduledJobs(now time.Time) error {
+	var jobs []models.Job
+	if err := database.
+		Where("state = ? AND publish_at <= ?", models.Scheduled, now).
+		Find(&jobs).Error; err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		job.Publish(now)
+		// the state check keeps a job the poster moved in the meantime as it is
+		result := database.Model(&models.Job{}).
+			Where("id = ? AND state = ?", job.ID, models.Scheduled).
+			Updates(map[string]interface{}{
+				"state":              job.State,
+				"published_at":       job.PublishedAt,
+				"expires_at":         job.ExpiresAt,
+				"expiry_notified_at": nil,
+			})
+		if result.Error != nil {
+			log.Printf("Failed to publish job %s: %v", job.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected > 0 {
+			announceJob(job.ID)
+		}
+	}
+	return nil
+}
+
+func notifyExpiringJobs(now time.Time) error {
+	var jobs []models.Job
+	if err := database.
+		Preload("User").
+		Where("state = ? AND expiry_notified_at IS NULL", models.Published).
+		Where("expires_at > ? AND expires_at <= ?", now, now.Add(expiryNotice)).
+		Find(&jobs).Error; err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		notification := notifications.Trigger{
+			EventID: "job-expiring",
+			To: map[string]interface{}{
+				"subscriberId": job.User.SubscriberID,
+				"email":        job.User.Email,
+			},
+			Data: map[string]interface{}{
+				"companyName": "Jobby",
+				"jobId":       job.ID,
+				"jobTitle":    job.Title,
+				"expiresAt":   job.ExpiresAt,
+			},
+		}
+		if err := database.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.Job{}).Where("id = ?", job.ID).Update("expiry_notified_at", now).Error; err != nil {
+				return err
+			}
+			if err := inbox.Notify(tx, models.Notification{
+				UserID:   job.UserID,
+				Category: models.JobExpiryCategory,
+				Title:    job.Title + " is about to expire",
+				Body:     fmt.Sprintf("%s stops taking applications on %s", job.Title, job.ExpiresAt.Format("2 Jan 2006 15:04 MST")),
+				Link:     "/api/v1/jobs/" + job.ID.String(),
+			}); err != nil {
+				return err
+			}
+			return notifications.Enqueue(tx, notification)
+		}); err != nil {
+			log.Printf("Failed to queue expiry notification for job %s: %v", job.ID, err)
+		}
+	}
+	return nil
+}
+
+func expireJobs(now time.Time) error {
+	return database.Model(&models.Job{}).
+		Where("state = ? AND expires_at <= ?", models.Published, now).
+		Update("state", models.Expired).Error
+}
@@ -1,23 +1,32 @@
 package job
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
-	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/config"
+	"job_board/inbox"
+	"job_board/matching"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
+	"job_board/profile"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle and how long before expiry posters are warned
+func Setup(db *gorm.DB, cfg config.Jobs) {
+	database = db
+	if cfg.ExpiryNoticeDays > 0 {
+		expiryNotice = time.Duration(cfg.ExpiryNoticeDays) * 24 * time.Hour
+	}
 }
 
 func createLevelFunc(Level models.Level) (*models.Level, error) {
@@ -41,26 +50,7 @@ func createLevelFunc(Level models.Level) (*models.Level, error) {
 	return &Level, nil
 }
 
-func getLevelFunc(name string, pageSize string, pageNumber string) ([]models.Level, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getLevelFunc(name string, params pagination.Params) (*pagination.Page[models.Level], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Level{})
 
@@ -69,25 +59,13 @@ func getLevelFunc(name string, pageSize string, pageNumber string) ([]models.Lev
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.Level
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.Level](db, params)
+	if err != nil {
 		log.Println("Error finding Level:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleLevelFunc(ID uuid.UUID) (*models.Level, error) {
@@ -108,11 +86,13 @@ func updateLevelFunc(ID uuid.UUID, name string) (*models.Level, error) {
 	}()
 	var existingRecord models.Level
 	if err := tx.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
 		return nil, err // Record not found or other database error
 	}
 
 	// Update the record with the provided updates
 	if err := tx.Model(&existingRecord).Update("name", name).Error; err != nil {
+		tx.Rollback()
 		return nil, err // Error updating the record
 	}
 
@@ -152,26 +132,7 @@ func createJobType(JobType models.JobType) (*models.JobType, error) {
 	return &JobType, nil
 }
 
-func getJobType(name string, pageSize string, pageNumber string) ([]models.JobType, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getJobType(name string, params pagination.Params) (*pagination.Page[models.JobType], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.JobType{})
 
@@ -180,25 +141,13 @@ func getJobType(name string, pageSize string, pageNumber string) ([]models.JobTy
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.JobType
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.JobType](db, params)
+	if err != nil {
 		log.Println("Error finding JobType:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleJobType(ID uuid.UUID) (*models.JobType, error) {
@@ -219,11 +168,13 @@ func updateJobType(ID uuid.UUID, name string) (*models.JobType, error) {
 	}()
 	var existingRecord models.JobType
 	if err := tx.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
 		return nil, err // Record not found or other database error
 	}
 
 	// Update the record with the provided updates
 	if err := tx.Model(&existingRecord).Update("name", name).Error; err != nil {
+		tx.Rollback()
 		return nil, err // Error updating the record
 	}
 
@@ -244,55 +195,111 @@ func deleteSingleJobType(JobTypeID uuid.UUID) error {
 
 /*job services start here*/
 
-func createJob(Job models.Job) (*models.Job, error) {
-	tx := database.Begin()
+func createJob(ctx context.Context, Job models.Job, user models.User) (*models.Job, error) {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
 		}
 	}()
 
+	// posting is an update to the company's jobs, only its owner and recruiters may do it
+	if err := policy.Check(user, "job:update", policy.Company(Job.CompanyID, models.Hiring...)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you can only post jobs for companies you recruit for")
+	}
+
+	var company models.Company
+	if err := tx.Select("id", "verified").First(&company, "id = ?", Job.CompanyID).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error fetching company: %w", err)
+	}
+	Job.CompanyVerified = company.Verified
+
+	if Job.SalaryMax != 0 && Job.SalaryMin > Job.SalaryMax {
+		tx.Rollback()
+		return nil, errors.New("salary_min can't be more than salary_max")
+	}
+
 	if err := tx.Create(&Job).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("error creating a new Job: %w", err)
 	}
 
+	if err := models.RefreshJobSearch(tx, "id = ?", Job.ID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error indexing Job: %w", err)
+	}
+
+	if err := models.RefreshJobSalaries(tx, "id = ?", Job.ID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error normalising Job salary: %w", err)
+	}
+
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	// drafts and scheduled jobs are announced by the scheduler once they go live
+	if Job.IsLive() {
+		announceJob(Job.ID)
+	}
+	matching.QueueJob(Job.ID)
+
 	return &Job, nil
 }
 
-func getJob(filter JobRequest, pageSize string, pageNumber string) ([]models.Job, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
+// applySalaryFilter keeps the jobs whose salary range overlaps the one asked for, both sides are
+// compared as yearly amounts in the base currency. Hidden salaries never match a salary filter
+func applySalaryFilter(db *gorm.DB, filter SalaryFilter) (*gorm.DB, error) {
+	if filter.Min == 0 && filter.Max == 0 {
+		return db, nil
+	}
 
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
+	db = db.Where("salary_hidden = ?", false).Where(models.NormalisedSalaryKnown)
+	if filter.Min != 0 {
+		min, err := models.NormaliseSalary(database, filter.Min, filter.CurrencyID, filter.PayPeriod)
+		if err != nil {
+			return nil, err
 		}
+		db = db.Where("salary_max_base >= ?", min)
 	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
+	if filter.Max != 0 {
+		max, err := models.NormaliseSalary(database, filter.Max, filter.CurrencyID, filter.PayPeriod)
+		if err != nil {
+			return nil, err
 		}
+		db = db.Where("salary_min_base <= ?", max)
 	}
+	return db, nil
+}
 
-	// Calculate offset
-	offset := (page - 1) * perPage
+// visibleJobs limits everybody to live jobs, job:read:company adds every job of the companies
+// the user is on the team of in any state and job:read:any shows everything
+func visibleJobs(db *gorm.DB, user *models.User) *gorm.DB {
+	if user != nil && policy.Can(*user, models.JobReadAny) {
+		return db
+	}
+	if user != nil && policy.Can(*user, models.JobReadCompany) {
+		return db.Where("("+models.LiveJobs+") OR jobs.company_id IN (?)", models.MemberCompanies(database, user.ID))
+	}
+	return db.Where(models.LiveJobs)
+}
 
+func getJob(filter JobRequest, salary SalaryFilter, state models.JobState, user *models.User, params pagination.Params) (*pagination.Page[models.Job], error) {
 	// Initialize database model with filtering conditions
-	db := database.Model(&models.Job{})
+	db := visibleJobs(database.Model(&models.Job{}), user)
+
+	if state != "" {
+		db = db.Where("state = ?", state)
+	}
 
 	if filter.Title != "" {
 		db = db.Where("title LIKE ?", "%"+filter.Title+"%")
 	}
 	if filter.Description != "" {
-		db = db.Where("description = ?", filter.Description)
+		db = db.Where("description ILIKE ?", "%"+filter.Description+"%")
 	}
 
 	if filter.CountryID != uuid.Nil {
@@ -311,44 +318,121 @@ func getJob(filter JobRequest, pageSize string, pageNumber string) ([]models.Job
 	}
 
 	if len(filter.Skills) > 0 {
-		db = db.Where("skills IN (?)", pq.StringArray(filter.Skills))
+		db = db.Where("skills && ?", pq.StringArray(filter.Skills))
 	}
-	if filter.Salary != 0.0 {
-		db = db.Where("salary <= ?", filter.Salary)
+
+	db, err := applySalaryFilter(db, salary)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := pagination.Find[models.Job](db, params)
+	if err != nil {
+		log.Println("Error finding Job:", err)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// searchJobs orders by relevance so it pages by offset, cursors only work on stored columns
+func searchJobs(filter SearchRequest, params pagination.Params) (*pagination.Page[JobSearchResult], error) {
+	queryExpr, queryArg := models.TsQuery(filter.Query, filter.Mode)
+	db := database.Model(&models.Job{}).
+		Where(models.LiveJobs).
+		Where("search_vector @@ "+queryExpr, queryArg)
+
+	if filter.CountryID != uuid.Nil {
+		db = db.Where("country_id = ?", filter.CountryID)
+	}
+	if filter.JobTypeID != uuid.Nil {
+		db = db.Where("job_type_id = ?", filter.JobTypeID)
+	}
+	if filter.LevelID != uuid.Nil {
+		db = db.Where("level_id = ?", filter.LevelID)
+	}
+	if filter.CompanyID != uuid.Nil {
+		db = db.Where("company_id = ?", filter.CompanyID)
+	}
+	// any of the skills (overlap) and all of the skills (containment)
+	if len(filter.SkillsAny) > 0 {
+		db = db.Where("skills && ?", pq.StringArray(filter.SkillsAny))
+	}
+	if len(filter.SkillsAll) > 0 {
+		db = db.Where("skills @> ?", pq.StringArray(filter.SkillsAll))
+	}
+
+	db, err := applySalaryFilter(db, filter.Salary)
+	if err != nil {
+		return nil, err
 	}
 
-	// Count total number of profiles
 	var total int64
 	if err := db.Count(&total).Error; err != nil {
 		log.Println("Error counting jobs:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	// Retrieve profiles with preloaded associations
-	var data []models.Job
+	headline := "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+	var ranked []JobSearchResult
 	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+		Select("id AS job_id, "+
+			"ts_rank_cd(search_vector, "+queryExpr+") AS rank, "+
+			"ts_headline('english', title, "+queryExpr+", 'HighlightAll=true') AS title_highlight, "+
+			"ts_headline('english', description, "+queryExpr+", ?) AS snippet",
+			queryArg, queryArg, queryArg, headline).
+		Order("rank DESC, created_at DESC").
+		Limit(params.PageSize).
+		Offset((params.Page - 1) * params.PageSize).
+		Scan(&ranked).Error; err != nil {
+		log.Println("Error searching jobs:", err)
+		return nil, err
+	}
+
+	page := &pagination.Page[JobSearchResult]{
+		Data:    ranked,
+		Total:   total,
+		Page:    params.Page,
+		PerPage: params.PageSize,
+	}
+	if len(ranked) == 0 {
+		return page, nil
+	}
+
+	ids := make([]uuid.UUID, len(ranked))
+	for i, result := range ranked {
+		ids[i] = result.JobID
+	}
+	var jobs []models.Job
+	if err := database.Preload("Company").Where("id IN ?", ids).Find(&jobs).Error; err != nil {
 		log.Println("Error finding Job:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.Job, len(jobs))
+	for _, job := range jobs {
+		byID[job.ID] = job
+	}
+	for i := range ranked {
+		job := byID[ranked[i].JobID]
+		ranked[i].Job = &job
 	}
 
-	return data, total, page, perPage, nil
+	return page, nil
 }
 
 func getSingleJob(ID uuid.UUID, user models.User) (*models.Job, error) {
 	var record models.Job
-	if err := database.
+	if err := visibleJobs(database, &user).
 		First(&record, "id = ?", ID).Error; err != nil {
 		return nil, err
 	}
 	return &record, nil
 }
 
-func updateJob(ID uuid.UUID, user models.User, updates JobRequest) (*models.Job, error) {
-	tx := database.Begin()
+// changeJobState moves a job through its lifecycle, a published job can also be given
+// a new expiry date without changing state
+func changeJobState(ctx context.Context, ID uuid.UUID, user models.User, state models.JobState, publishAt, expiresAt *time.Time) (*models.Job, error) {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -357,28 +441,130 @@ func updateJob(ID uuid.UUID, user models.User, updates JobRequest) (*models.Job,
 
 	var existingRecord models.Job
 	if err := tx.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := policy.Check(user, "job:update", policy.Company(existingRecord.CompanyID, models.Hiring...)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		tx.Rollback()
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	extending := state == models.Published && existingRecord.State == models.Published && expiresAt != nil
+	if !extending {
+		if err := models.CheckJobTransition(existingRecord.State, state); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	wasLive := existingRecord.IsLive()
+	switch state {
+	case models.Draft:
+		existingRecord.State = models.Draft
+		existingRecord.PublishAt = nil
+	case models.Scheduled:
+		if publishAt == nil || !publishAt.After(now) {
+			tx.Rollback()
+			return nil, errors.New("publish_at must be in the future to schedule a job")
+		}
+		existingRecord.State = models.Scheduled
+		existingRecord.PublishAt = publishAt
+	case models.Published:
+		if expiresAt != nil {
+			existingRecord.ExpiresAt = expiresAt
+			existingRecord.ExpiryNotifiedAt = nil
+		}
+		if !extending {
+			existingRecord.Publish(now)
+		}
+	case models.Paused:
+		existingRecord.State = models.Paused
+	case models.Closed:
+		existingRecord.State = models.Closed
+		existingRecord.ClosedAt = &now
+	}
+
+	if err := tx.Model(&existingRecord).
+		Select("state", "publish_at", "published_at", "expires_at", "expiry_notified_at", "closed_at").
+		Updates(&existingRecord).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error updating job state: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	if !wasLive && existingRecord.IsLive() {
+		announceJob(existingRecord.ID)
+	}
+
+	return &existingRecord, nil
+}
+
+func updateJob(ctx context.Context, ID uuid.UUID, user models.User, updates JobRequest) (*models.Job, error) {
+	tx := database.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var existingRecord models.Job
+	if err := tx.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
 		return nil, err // Record not found or other database error
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.PosterRole && existingRecord.UserID != user.ID {
+	if err := policy.Check(user, "job:update", policy.Company(existingRecord.CompanyID, models.Hiring...)); err != nil {
+		tx.Rollback()
 		return nil, fmt.Errorf("you don't have permission to update this record")
 	}
 
 	// Update the record with the provided updates
 	if err := tx.Model(&existingRecord).Updates(updates).Error; err != nil {
+		tx.Rollback()
 		return nil, err // Error updating the record
 	}
 
+	if err := models.RefreshJobSearch(tx, "id = ?", existingRecord.ID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error indexing Job: %w", err)
+	}
+
+	// the range is checked once both ends are known, either may come from the stored job
+	if err := tx.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if existingRecord.SalaryMax != 0 && existingRecord.SalaryMin > existingRecord.SalaryMax {
+		tx.Rollback()
+		return nil, errors.New("salary_min can't be more than salary_max")
+	}
+	if err := models.RefreshJobSalaries(tx, "id = ?", existingRecord.ID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error normalising Job salary: %w", err)
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueJob(existingRecord.ID)
+
 	return &existingRecord, nil
 }
 
-func deleteSingleJob(ID uuid.UUID, user models.User) error {
-	tx := database.Begin()
+func deleteSingleJob(ctx context.Context, ID uuid.UUID, user models.User) error {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -391,8 +577,9 @@ func deleteSingleJob(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName == models.PosterRole && existingRecord.UserID != user.ID {
-		return fmt.Errorf("you don't have permission to update this record")
+	if err := policy.Check(user, "job:delete", policy.Company(existingRecord.CompanyID, models.Hiring...)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.Job{}, "id = ?", ID)
@@ -414,19 +601,48 @@ func deleteSingleJob(ID uuid.UUID, user models.User) error {
 	return nil
 }
 
-func createJobApplication(JobApplication models.JobApplication, user models.User) (*models.JobApplication, error) {
-	tx := database.Begin()
+func createJobApplication(ctx context.Context, JobApplication models.JobApplication, user models.User) (*models.JobApplication, error) {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
 		}
 	}()
 
+	var job models.Job
+	if err := tx.First(&job, "id = ?", JobApplication.JobID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if !job.IsLive() {
+		tx.Rollback()
+		return nil, errors.New("this job is not accepting applications")
+	}
+
 	if err := tx.Create(&JobApplication).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("error creating a new application: %w", err)
 	}
 
+	// everyone on the company's team can read its applications, so all of them hear about it
+	var team []uuid.UUID
+	if err := tx.Model(&models.CompanyMember{}).Where("company_id = ?", job.CompanyID).Pluck("user_id", &team).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error finding the company team: %w", err)
+	}
+	for _, memberID := range team {
+		if err := inbox.Notify(tx, models.Notification{
+			UserID:   memberID,
+			Category: models.NewApplicantCategory,
+			Title:    "New applicant for " + job.Title,
+			Body:     fmt.Sprintf("%s applied to %s", user.Name, job.Title),
+			Link:     applicationLink(JobApplication.ID),
+		}); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
@@ -435,82 +651,36 @@ func createJobApplication(JobApplication models.JobApplication, user models.User
 	return &JobApplication, nil
 }
 
-func getApplications(jobID uuid.UUID, user models.User, pageSize string, pageNumber string) ([]models.JobApplication, int64, int, int, error) {
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
+func getApplications(jobID uuid.UUID, user models.User, params pagination.Params) (*pagination.Page[models.JobApplication], error) {
 	var record models.Job
 	if err := database.
 		First(&record, "id = ?", jobID).Error; err != nil {
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
-	if record.UserID != user.ID {
-		return nil, 0, 0, 0, errors.New("you did not create this job")
+	if err := policy.Check(user, "application:read", policy.Company(record.CompanyID)); err != nil {
+		return nil, errors.New("you're not on the team hiring for this job")
 	}
 
 	db := database.Model(&models.JobApplication{})
 	db = db.Where("job_id = ?", jobID)
 
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	offset := (page - 1) * perPage
-	// Retrieve profiles with preloaded associations
-	var data []models.JobApplication
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.JobApplication](db, params)
+	if err != nil {
 		log.Println("Error finding JobApplication:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
-func getJobApplication(filter SearchApplication, pageSize string, pageNumber string) ([]models.JobApplication, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getJobApplication(filter SearchApplication, params pagination.Params) (*pagination.Page[models.JobApplication], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.JobApplication{})
 
-	if filter.JobID  != uuid.Nil {
+	if filter.JobID != uuid.Nil {
 		db = db.Where("job_id = ?", filter.JobID)
 	}
-	if filter.ApplicantID  != uuid.Nil {
+	if filter.ApplicantID != uuid.Nil {
 		db = db.Where("applicant_id = ?", filter.ApplicantID)
 	}
 	if filter.Status != "" {
@@ -520,25 +690,13 @@ func getJobApplication(filter SearchApplication, pageSize string, pageNumber str
 		db = db.Where("applied_at >= ?", filter.AppliedAt)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.JobApplication
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.JobApplication](db, params)
+	if err != nil {
 		log.Println("Error finding JobApplication:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleJobApplication(ID uuid.UUID, user models.User) (*models.JobApplication, error) {
@@ -550,17 +708,15 @@ func getSingleJobApplication(ID uuid.UUID, user models.User) (*models.JobApplica
 		return nil, err
 	}
 
-	if user.RoleName != models.AdminRole && user.RoleName != models.SuperAdminRole {
-		if record.Job.UserID != user.ID && record.ApplicantID != user.ID {
-			return nil, fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "application:read", policy.Own(record.ApplicantID), policy.Company(record.Job.CompanyID)); err != nil {
+		return nil, fmt.Errorf("you don't have permission to view this record")
 	}
 
 	return &record, nil
 }
 
-func updateJobApplication(ID uuid.UUID, user models.User, status models.Status) (*models.JobApplication, error) {
-	tx := database.Begin()
+func updateJobApplication(ctx context.Context, ID uuid.UUID, user models.User, status models.Status, stageID *uuid.UUID, note string) (*models.JobApplication, error) {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -569,19 +725,86 @@ func updateJobApplication(ID uuid.UUID, user models.User, status models.Status)
 
 	var existingRecord models.JobApplication
 	if err := tx.Preload("Job").First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
 		return nil, err // Record not found or other database error
 	}
 
-	// Check if the user has permission to update the record
-	if existingRecord.Job.UserID != user.ID {
-		return nil, fmt.Errorf("you don't have permission to update this record")
+	// Check if the user has permission to update the record, applicants can only withdraw
+	if policy.Check(user, "application:update", policy.Company(existingRecord.Job.CompanyID, models.Hiring...)) != nil {
+		if policy.Check(user, "application:withdraw", policy.Own(existingRecord.ApplicantID)) != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("you don't have permission to update this record")
+		}
+		if status != models.Withdrawn {
+			tx.Rollback()
+			return nil, fmt.Errorf("you can only withdraw your application")
+		}
+	}
+
+	if stageID != nil {
+		var stage models.PipelineStage
+		if err := tx.First(&stage, "id = ?", *stageID).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error fetching stage: %w", err)
+		}
+		if stage.CompanyID != existingRecord.Job.CompanyID {
+			tx.Rollback()
+			return nil, fmt.Errorf("stage %s does not belong to this company's pipeline", stage.Name)
+		}
+		if stage.Status != status {
+			tx.Rollback()
+			return nil, fmt.Errorf("stage %s belongs to the %s status not %s", stage.Name, stage.Status, status)
+		}
+	}
+
+	// moving to the same status is only allowed when it changes the custom stage, and only
+	// while the status isn't final. Applications from before the pipeline are compared by
+	// the status their legacy one stands for
+	current := existingRecord.Status.Current()
+	sameStatus := current == status
+	if sameStatus && sameStage(existingRecord.StageID, stageID) {
+		tx.Rollback()
+		return nil, fmt.Errorf("application is already in this stage")
+	}
+	if !sameStatus || status.IsFinal() {
+		if err := models.CheckTransition(current, status); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	history := models.ApplicationStatusHistory{
+		JobApplicationID: existingRecord.ID,
+		FromStatus:       current,
+		ToStatus:         status,
+		FromStageID:      existingRecord.StageID,
+		ToStageID:        stageID,
+		ChangedByID:      user.ID,
+		Note:             note,
 	}
 
 	// Update the record with the provided updates
-	if err := tx.Model(&existingRecord).Update("status", status).Error; err != nil {
+	if err := tx.Model(&existingRecord).Updates(map[string]interface{}{
+		"status":   status,
+		"stage_id": stageID,
+	}).Error; err != nil {
+		tx.Rollback()
 		return nil, err // Error updating the record
 	}
 
+	if err := tx.Create(&history).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error recording status history: %w", err)
+	}
+
+	// applicants aren't told about their own withdrawal
+	if user.ID != existingRecord.ApplicantID {
+		if err := inbox.Notify(tx, statusNotification(existingRecord, history)); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
@@ -589,8 +812,62 @@ func updateJobApplication(ID uuid.UUID, user models.User, status models.Status)
 	return &existingRecord, nil
 }
 
-func deleteSingleJobApplication(ID uuid.UUID, user models.User) error {
-	tx := database.Begin()
+// applicationLink is the api path of an application, notifications point at it
+func applicationLink(ID uuid.UUID) string {
+	return "/api/v1/jobs/applications/" + ID.String()
+}
+
+func statusNotification(application models.JobApplication, history models.ApplicationStatusHistory) models.Notification {
+	body := fmt.Sprintf("Your application moved from %s to %s", history.FromStatus, history.ToStatus)
+	if history.FromStatus == history.ToStatus {
+		body = fmt.Sprintf("Your application moved to another %s stage", history.ToStatus)
+	}
+	return models.Notification{
+		UserID:   application.ApplicantID,
+		Category: models.ApplicationStatusCategory,
+		Title:    "Your application for " + application.Job.Title + " was updated",
+		Body:     body,
+		Link:     applicationLink(application.ID),
+	}
+}
+
+func sameStage(a *uuid.UUID, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func getApplicationHistory(ID uuid.UUID, user models.User) ([]models.ApplicationStatusHistory, error) {
+	// reuse the single application lookup for its permission check
+	if _, err := getSingleJobApplication(ID, user); err != nil {
+		return nil, err
+	}
+
+	var data []models.ApplicationStatusHistory
+	if err := database.
+		Preload("ChangedBy").
+		Where("job_application_id = ?", ID).
+		Order("created_at ASC").
+		Find(&data).Error; err != nil {
+		log.Println("Error finding application history:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// getApplicantProfile is the applicant's profile for whoever can see the application, what
+// they get of it is up to the applicant's privacy settings
+func getApplicantProfile(ID uuid.UUID, user models.User) (interface{}, error) {
+	record, err := getSingleJobApplication(ID, user)
+	if err != nil {
+		return nil, err
+	}
+	return profile.ApplicantProfile(record.ApplicantID, user)
+}
+
+func deleteSingleJobApplication(ctx context.Context, ID uuid.UUID, user models.User) error {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -603,8 +880,9 @@ func deleteSingleJobApplication(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName != models.AdminRole || user.RoleName != models.SuperAdminRole {
-		return fmt.Errorf("you don't have permission to update this record")
+	if err := policy.Check(user, "application:delete"); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.JobApplication{}, "id = ?", ID)
@@ -625,3 +903,124 @@ func deleteSingleJobApplication(ID uuid.UUID, user models.User) error {
 	}
 	return nil
 }
+
+/* pipeline stage services start here*/
+
+// checkCompanyTeam lets the company's owner and recruiters manage its pipeline
+func checkCompanyTeam(tx *gorm.DB, companyID uuid.UUID, user models.User) error {
+	var company models.Company
+	if err := tx.First(&company, "id = ?", companyID).Error; err != nil {
+		return fmt.Errorf("error fetching company: %w", err)
+	}
+	if err := policy.Check(user, "pipeline:manage", policy.Company(company.ID, models.Hiring...)); err != nil {
+		return fmt.Errorf("you don't have permission to manage this company's pipeline")
+	}
+	return nil
+}
+
+func createStage(stage models.PipelineStage, user models.User) (*models.PipelineStage, error) {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkCompanyTeam(tx, stage.CompanyID, user); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Create(&stage).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errors.New("stage with the same name already exists for this company")
+		}
+		return nil, fmt.Errorf("error creating a new stage: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return &stage, nil
+}
+
+func getStages(companyID uuid.UUID) ([]models.PipelineStage, error) {
+	var data []models.PipelineStage
+	if err := database.
+		Where("company_id = ?", companyID).
+		Order("position ASC").
+		Find(&data).Error; err != nil {
+		log.Println("Error finding stages:", err)
+		return nil, err
+	}
+	return data, nil
+}
+
+func updateStage(ID uuid.UUID, user models.User, updates map[string]interface{}) (*models.PipelineStage, error) {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var existingRecord models.PipelineStage
+	if err := tx.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err // Record not found or other database error
+	}
+
+	if err := checkCompanyTeam(tx, existingRecord.CompanyID, user); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Model(&existingRecord).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		return nil, err // Error updating the record
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return &existingRecord, nil
+}
+
+func deleteSingleStage(ID uuid.UUID, user models.User) error {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var existingRecord models.PipelineStage
+	if err := tx.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkCompanyTeam(tx, existingRecord.CompanyID, user); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// applications sitting in this stage fall back to the plain status
+	if err := tx.Model(&models.JobApplication{}).Where("stage_id = ?", ID).Update("stage_id", nil).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&models.PipelineStage{}, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
@@ -2,9 +2,7 @@ package jwt
 
 import (
 	"fmt"
-	"log"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -13,7 +11,8 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/go-redis/redis/v8"
-	"job_board/db"
+	"job_board/audit"
+	"job_board/config"
 	"job_board/helpers"
 	"job_board/models"
 	cisredis "job_board/redis"
@@ -22,28 +21,23 @@ import (
 var SecretKey []byte
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
-	secretKey := os.Getenv("SECRET_KEY")
-	if secretKey == "" {
-		log.Fatal("Error loading jwt secret")
+// Setup gives the package its database handle and the secret tokens are signed with
+func Setup(db *gorm.DB, cfg config.Auth) {
+	database = db
+	SecretKey = []byte(cfg.SecretKey)
+	if cfg.RefreshTokenDays > 0 {
+		refreshTTL = time.Duration(cfg.RefreshTokenDays) * 24 * time.Hour
 	}
-	SecretKey = []byte(secretKey)
 }
 
-func GenerateJWT(providerID string, isMobile bool) (string, error) {
+// GenerateJWT signs a short lived access token tied to a session, use StartSession to sign someone in
+func GenerateJWT(providerID string, sessionID string) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
 	claims := token.Claims.(jwt.MapClaims)
 	claims["authorized"] = true
 	claims["provider_id"] = providerID
-
-	if isMobile {
-		// Set expiration time to 1 year from now for mobile users
-		claims["exp"] = time.Now().Add(time.Hour * 24 * 365).Unix()
-	} else {
-		// Set expiration time to 30 minutes from now for web users
-		claims["exp"] = time.Now().Add(time.Minute * 30).Unix()
-	}
+	claims["sid"] = sessionID
+	claims["exp"] = time.Now().Add(AccessTokenTTL).Unix()
 
 	tokenString, err := token.SignedString(SecretKey)
 	if err != nil {
@@ -141,6 +135,34 @@ func Middleware() gin.HandlerFunc {
 			return
 		}
 
+		// tokens from before sessions existed can't be revoked, make their holders sign in again
+		sessionID, ok := claims["sid"].(string)
+		if !ok {
+			helpers.CreateResponse(c, helpers.Response{
+				Message:    "session expired, please log in again",
+				StatusCode: http.StatusUnauthorized,
+				Data:       nil,
+			})
+			return
+		}
+		revoked, err := IsRevoked(sessionID)
+		if err != nil {
+			helpers.CreateResponse(c, helpers.Response{
+				Message:    err.Error(),
+				StatusCode: http.StatusInternalServerError,
+				Data:       nil,
+			})
+			return
+		}
+		if revoked {
+			helpers.CreateResponse(c, helpers.Response{
+				Message:    "session has been revoked",
+				StatusCode: http.StatusUnauthorized,
+				Data:       nil,
+			})
+			return
+		}
+
 		providerID := claims["provider_id"].(string)
 		user, err := GetUser(providerID)
 		if err != nil {
@@ -152,7 +174,9 @@ func Middleware() gin.HandlerFunc {
 			return
 		}
 		c.Set("claims", claims)
+		c.Set("session_id", sessionID)
 		c.Set("user", user)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), user))
 		c.Next()
 	}
 }
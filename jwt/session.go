@@ -0,0 +1,246 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	cisredis "job_board/redis"
+)
+
+// AccessTokenTTL is short on purpose, clients keep a session alive with their refresh token
+const AccessTokenTTL = 15 * time.Minute
+
+// DefaultRefreshTTL is how long a session survives without being refreshed when
+// REFRESH_TOKEN_DAYS isn't set
+const DefaultRefreshTTL = 30 * 24 * time.Hour
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+var ctx = context.Background()
+
+// Session is one signed in device, everything about it lives in redis
+type Session struct {
+	ID         string    `json:"id"`
+	ProviderID string    `json:"-"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// storedSession is a session as it's kept in redis, the provider id stays out of the api
+// but redis needs it to tell whose session it is
+type storedSession struct {
+	Session
+	ProviderID string `json:"provider_id"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	SessionID    string `json:"session_id"`
+}
+
+// refreshTTL is set from the config by Setup
+var refreshTTL = DefaultRefreshTTL
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+func userSessionsKey(providerID string) string {
+	return "sessions:" + providerID
+}
+
+func revokedKey(id string) string {
+	return "revoked:" + id
+}
+
+// refresh tokens are only kept hashed so a redis dump can't be replayed
+func refreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh:" + hex.EncodeToString(sum[:])
+}
+
+func usedRefreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh-used:" + hex.EncodeToString(sum[:])
+}
+
+func getSession(id string) (*Session, error) {
+	data, err := cisredis.GetClient().Get(ctx, sessionKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored storedSession
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	stored.Session.ProviderID = stored.ProviderID
+	return &stored.Session, nil
+}
+
+// issue saves the session with a fresh refresh token and signs an access token for it
+func issue(session *Session) (*TokenPair, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	refreshToken := hex.EncodeToString(raw)
+
+	ttl := refreshTTL
+	now := time.Now()
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(ttl)
+	data, err := json.Marshal(storedSession{Session: *session, ProviderID: session.ProviderID})
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := cisredis.GetClient().TxPipeline()
+	pipe.Set(ctx, sessionKey(session.ID), data, ttl)
+	pipe.Set(ctx, refreshKey(refreshToken), session.ID, ttl)
+	pipe.SAdd(ctx, userSessionsKey(session.ProviderID), session.ID)
+	pipe.Expire(ctx, userSessionsKey(session.ProviderID), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := GenerateJWT(session.ProviderID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(AccessTokenTTL.Seconds()),
+		SessionID:    session.ID,
+	}, nil
+}
+
+// StartSession signs a user in on a new device
+func StartSession(providerID, userAgent, ip string) (*TokenPair, error) {
+	return issue(&Session{
+		ID:         uuid.NewString(),
+		ProviderID: providerID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// Refresh trades a refresh token for a new pair. Every refresh token works once, presenting
+// one that was already used means it leaked, so the whole session is revoked
+func Refresh(refreshToken, userAgent, ip string) (*TokenPair, error) {
+	client := cisredis.GetClient()
+	id, err := client.GetDel(ctx, refreshKey(refreshToken)).Result()
+	if err == redis.Nil {
+		if stolen, err := client.Get(ctx, usedRefreshKey(refreshToken)).Result(); err == nil {
+			if session, err := getSession(stolen); err == nil {
+				RevokeSession(session.ProviderID, session.ID)
+			}
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := getSession(id)
+	if err == ErrSessionNotFound {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Set(ctx, usedRefreshKey(refreshToken), session.ID, time.Until(session.ExpiresAt)).Err(); err != nil {
+		return nil, err
+	}
+	session.UserAgent = userAgent
+	session.IP = ip
+	return issue(session)
+}
+
+// GetSessions lists the signed in devices of a user, dropping ids whose session expired
+func GetSessions(providerID string) ([]Session, error) {
+	client := cisredis.GetClient()
+	ids, err := client.SMembers(ctx, userSessionsKey(providerID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessions := []Session{}
+	for _, id := range ids {
+		session, err := getSession(id)
+		if err == ErrSessionNotFound {
+			client.SRem(ctx, userSessionsKey(providerID), id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs a device out, its access token stops working straight away
+func RevokeSession(providerID, id string) error {
+	session, err := getSession(id)
+	if err != nil {
+		return err
+	}
+	if session.ProviderID != providerID {
+		return ErrSessionNotFound
+	}
+
+	pipe := cisredis.GetClient().TxPipeline()
+	pipe.Del(ctx, sessionKey(id))
+	pipe.SRem(ctx, userSessionsKey(providerID), id)
+	// access tokens can't be recalled, remember the session until the last of them expires
+	pipe.Set(ctx, revokedKey(id), 1, AccessTokenTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// RevokeAllSessions signs the user out everywhere
+func RevokeAllSessions(providerID string) error {
+	sessions, err := GetSessions(providerID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := RevokeSession(providerID, session.ID); err != nil && err != ErrSessionNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsRevoked checks the revocation list for a session
+func IsRevoked(id string) (bool, error) {
+	n, err := cisredis.GetClient().Exists(ctx, revokedKey(id)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
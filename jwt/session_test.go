@@ -0,0 +1,173 @@
+package jwt
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt"
+
+	"job_board/config"
+	cisredis "job_board/redis"
+)
+
+// setupSessions points the redis client at an in memory server for the length of the test
+func setupSessions(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	SecretKey = []byte("test-secret")
+	store := miniredis.RunT(t)
+	if err := cisredis.Connect(config.Redis{Host: store.Addr()}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cisredis.GetClient().Close() })
+	return store
+}
+
+// members reads a set back, a missing key is an empty set
+func members(store *miniredis.Miniredis, key string) map[string]bool {
+	out := map[string]bool{}
+	list, _ := store.Members(key)
+	for _, member := range list {
+		out[member] = true
+	}
+	return out
+}
+
+func tokenClaims(t *testing.T, token string) jwt.MapClaims {
+	t.Helper()
+	parsed, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return SecretKey, nil })
+	if err != nil {
+		t.Fatalf("parsing access token: %v", err)
+	}
+	return parsed.Claims.(jwt.MapClaims)
+}
+
+func TestRefreshKeepsTheUser(t *testing.T) {
+	store := setupSessions(t)
+
+	first, err := StartSession("local|alice", "firefox", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed, err := Refresh(first.RefreshToken, "chrome", "10.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := tokenClaims(t, refreshed.AccessToken)
+	if claims["provider_id"] != "local|alice" {
+		t.Errorf("refreshed token names provider %q, want local|alice", claims["provider_id"])
+	}
+	if claims["sid"] != first.SessionID {
+		t.Errorf("refreshed token names session %q, want %q", claims["sid"], first.SessionID)
+	}
+
+	if members := members(store, userSessionsKey("local|alice")); !members[first.SessionID] || len(members) != 1 {
+		t.Errorf("user sessions = %v, want only %s", members, first.SessionID)
+	}
+	if members := members(store, userSessionsKey("")); len(members) != 0 {
+		t.Errorf("sessions were filed under an empty provider: %v", members)
+	}
+
+	sessions, err := GetSessions("local|alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ProviderID != "local|alice" || sessions[0].UserAgent != "chrome" {
+		t.Errorf("sessions after refresh = %+v", sessions)
+	}
+}
+
+func TestRefreshTokensWorkOnce(t *testing.T) {
+	setupSessions(t)
+
+	first, err := StartSession("local|alice", "firefox", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Refresh(first.RefreshToken, "firefox", "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// replaying the old token revokes the session it belonged to
+	if _, err := Refresh(first.RefreshToken, "curl", "10.6.6.6"); err != ErrInvalidRefreshToken {
+		t.Fatalf("replayed refresh token gave %v, want ErrInvalidRefreshToken", err)
+	}
+	revoked, err := IsRevoked(first.SessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Error("session wasn't revoked after its refresh token was replayed")
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	setupSessions(t)
+
+	laptop, err := StartSession("local|alice", "firefox", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	phone, err := StartSession("local|alice", "safari", "10.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RevokeSession("local|bob", laptop.SessionID); err != ErrSessionNotFound {
+		t.Errorf("revoking somebody else's session gave %v, want ErrSessionNotFound", err)
+	}
+	if err := RevokeSession("local|alice", laptop.SessionID); err != nil {
+		t.Fatalf("revoking own session: %v", err)
+	}
+
+	if revoked, _ := IsRevoked(laptop.SessionID); !revoked {
+		t.Error("revoked session isn't on the revocation list")
+	}
+	if revoked, _ := IsRevoked(phone.SessionID); revoked {
+		t.Error("the other session was revoked too")
+	}
+	if _, err := Refresh(laptop.RefreshToken, "firefox", "10.0.0.1"); err != ErrInvalidRefreshToken {
+		t.Errorf("refreshing a revoked session gave %v, want ErrInvalidRefreshToken", err)
+	}
+
+	sessions, err := GetSessions("local|alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != phone.SessionID {
+		t.Errorf("sessions after revoking the laptop = %+v", sessions)
+	}
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	setupSessions(t)
+
+	var ids []string
+	for _, agent := range []string{"firefox", "safari", "curl"} {
+		pair, err := StartSession("local|alice", agent, "10.0.0.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, pair.SessionID)
+	}
+	other, err := StartSession("local|bob", "firefox", "10.0.0.9")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RevokeAllSessions("local|alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range ids {
+		if revoked, _ := IsRevoked(id); !revoked {
+			t.Errorf("session %s survived revoking every session", id)
+		}
+	}
+	if sessions, _ := GetSessions("local|alice"); len(sessions) != 0 {
+		t.Errorf("sessions left after revoking all: %+v", sessions)
+	}
+	if revoked, _ := IsRevoked(other.SessionID); revoked {
+		t.Error("another user's session was revoked")
+	}
+}
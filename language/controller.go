@@ -8,6 +8,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 /*language*/
@@ -43,9 +44,16 @@ func CreateLanguage(ctx *gin.Context) {
 
 func GetLanguage(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	language, total, page, perPage, err := getLanguage(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	language, err := getLanguage(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -57,12 +65,8 @@ func GetLanguage(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched language",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     language,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       language,
+		Links:      language.Links(ctx),
 	})
 }
 
@@ -188,9 +192,16 @@ func CreateLanguageProficiency(ctx *gin.Context) {
 
 func GetLanguageProficiency(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	proficiency, total, page, perPage, err := getLanguageProficiency(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	proficiency, err := getLanguageProficiency(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -202,12 +213,8 @@ func GetLanguageProficiency(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched proficiency",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     proficiency,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       proficiency,
+		Links:      proficiency.Links(ctx),
 	})
 }
 
@@ -380,7 +387,16 @@ func GetProfileLanguage(ctx *gin.Context) {
 		LanguageProficiencyID: languageProficiencyID,
 	}
 
-	resp, total, page, perPage, err := getProficiency(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	resp, err := getProficiency(filter, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -392,12 +408,8 @@ func GetProfileLanguage(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched profile languages",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     resp,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       resp,
+		Links:      resp.Links(ctx),
 	})
 }
 
@@ -8,15 +8,13 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-
 func LanguageRoutes(superRoute *gin.RouterGroup) {
 	languageRouter := superRoute.Group("/languages")
-	languageRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), CreateLanguage)
+	languageRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), CreateLanguage)
 	languageRouter.GET("/", GetLanguage)
 	languageRouter.GET("/:id", GetSingleLanguage)
-	languageRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), UpdateLanguage)
-	languageRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), DeleteLanguage)
+	languageRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), UpdateLanguage)
+	languageRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), DeleteLanguage)
 
 	ProficiencyRoutes(languageRouter.Group("/proficiencies"))
 }
@@ -24,9 +22,9 @@ func LanguageRoutes(superRoute *gin.RouterGroup) {
 func ProficiencyRoutes(superRoute *gin.RouterGroup) {
 	proficiencyRouter := superRoute.Group("/languages")
 
-	proficiencyRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), CreateLanguageProficiency)
+	proficiencyRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), CreateLanguageProficiency)
 	proficiencyRouter.GET("/", GetLanguageProficiency)
 	proficiencyRouter.GET("/:id", GetSingleLanguageProficiency)
-	proficiencyRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), UpdateLanguageProficiency)
-	proficiencyRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), DeleteLanguageProficiency)
+	proficiencyRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), UpdateLanguageProficiency)
+	proficiencyRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), DeleteLanguageProficiency)
 }
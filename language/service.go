@@ -4,19 +4,21 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/completeness"
+	"job_board/matching"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func createLanguage(Language models.Language) (*models.Language, error) {
@@ -40,26 +42,7 @@ func createLanguage(Language models.Language) (*models.Language, error) {
 	return &Language, nil
 }
 
-func getLanguage(name string, pageSize string, pageNumber string) ([]models.Language, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getLanguage(name string, params pagination.Params) (*pagination.Page[models.Language], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Language{})
 
@@ -68,25 +51,13 @@ func getLanguage(name string, pageSize string, pageNumber string) ([]models.Lang
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.Language
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.Language](db, params)
+	if err != nil {
 		log.Println("Error finding Language:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleLanguage(ID uuid.UUID) (*models.Language, error) {
@@ -153,26 +124,7 @@ func createLanguageProficiency(LanguageProficiency models.LanguageProficiency) (
 	return &LanguageProficiency, nil
 }
 
-func getLanguageProficiency(name string, pageSize string, pageNumber string) ([]models.LanguageProficiency, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getLanguageProficiency(name string, params pagination.Params) (*pagination.Page[models.LanguageProficiency], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.LanguageProficiency{})
 
@@ -181,25 +133,13 @@ func getLanguageProficiency(name string, pageSize string, pageNumber string) ([]
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.LanguageProficiency
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.LanguageProficiency](db, params)
+	if err != nil {
 		log.Println("Error finding LanguageProficiency:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleLanguageProficiency(ID uuid.UUID) (*models.LanguageProficiency, error) {
@@ -272,29 +212,13 @@ func createProficiency(Proficiency models.ProfileLanguage, user models.User) (*m
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(Proficiency.ProfileID)
+	completeness.Queue(Proficiency.ProfileID)
+
 	return &Proficiency, nil
 }
 
-func getProficiency(filter Search, pageSize string, pageNumber string) ([]models.ProfileLanguage, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getProficiency(filter Search, params pagination.Params) (*pagination.Page[models.ProfileLanguage], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.ProfileLanguage{})
 
@@ -309,25 +233,13 @@ func getProficiency(filter Search, pageSize string, pageNumber string) ([]models
 		db = db.Where("language_proficiency_id = ?", filter.LanguageProficiencyID)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.ProfileLanguage
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.ProfileLanguage](db, params)
+	if err != nil {
 		log.Println("Error finding Proficiency:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleProficiency(ID uuid.UUID, user models.User) (*models.ProfileLanguage, error) {
@@ -343,7 +255,7 @@ func getSingleProficiency(ID uuid.UUID, user models.User) (*models.ProfileLangua
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole && record.ProfileID != user.Profile.ID {
+	if err := policy.Check(user, "profile:read", policy.OwnProfile(record.ProfileID)); err != nil {
 		return nil, fmt.Errorf("you don't have permission to view this record")
 	}
 
@@ -364,13 +276,9 @@ func updateProficiency(ID uuid.UUID, user models.User, updates Request) (*models
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole {
-		if profile := checkProfile(user); profile {
-			return nil, fmt.Errorf("you don't have a profile")
-		}
-		if existingRecord.ProfileID != user.Profile.ID {
-			return nil, fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:update", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
 	}
 
 	// Update the record with the provided updates
@@ -382,6 +290,9 @@ func updateProficiency(ID uuid.UUID, user models.User, updates Request) (*models
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
+
 	return &existingRecord, nil
 }
 
@@ -399,10 +310,9 @@ func deleteSingleProficiency(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName == models.UserRole {
-		if existingRecord.ProfileID != user.Profile.ID {
-			return fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:delete", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.ProfileLanguage{}, "id = ?", ID)
@@ -421,5 +331,8 @@ func deleteSingleProficiency(ID uuid.UUID, user models.User) error {
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
 	return nil
 }
\ No newline at end of file
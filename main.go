@@ -2,20 +2,76 @@ package main
 
 import (
 	// "context"
+	"fmt"
 	"log"
-	// "os"
+	"os"
 
 	// apitoolkit "github.com/apitoolkit/apitoolkit-go"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"job_board/alert"
+	"job_board/completeness"
+	"job_board/config"
+	"job_board/db"
+	"job_board/erasure"
+	"job_board/export"
+	"job_board/job"
+	"job_board/matching"
+	"job_board/migrations"
 	"job_board/models"
+	"job_board/notifications"
+	"job_board/redis"
+	"job_board/user"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Fatalf("Failed to load the env vars: %v", err)
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Failed to load the config: %v", err)
+	}
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// go run . [-env-file file] migrate up|down|status
+	if len(args) > 0 && args[0] == "migrate" {
+		// the legacy upgrade dates old jobs with the configured expiry
+		models.Setup(database, cfg.Jobs)
+		if err := migrations.Command(database, args[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := redis.Connect(cfg.Redis); err != nil {
+		log.Fatal(err)
+	}
+	if err := setup(cfg, database); err != nil {
+		log.Fatalf("Failed to set up: %v", err)
 	}
-	models.MigrateDb()
+
+	// MIGRATE_ON_START=false leaves migrating to the migrate command, e.g in a release step
+	if !cfg.MigrateOnStart {
+		if pending, err := migrations.Pending(database); err != nil {
+			log.Fatalf("Failed to check the migrations: %v", err)
+		} else if pending > 0 {
+			log.Fatalf("%d migrations haven't been applied, run migrate up first", pending)
+		}
+	} else if _, err := migrations.Up(database, 0); err != nil {
+		log.Fatalf("Failed to migrate the database: %v", err)
+	}
+	if err := models.Seed(database); err != nil {
+		log.Printf("Failed to seed the database: %v", err)
+	}
+	user.CreateSuperAdmin()
+
+	notifications.StartWorker()
+	alert.Start()
+	matching.Start()
+	completeness.Start()
+	job.StartScheduler()
+	erasure.StartSweeper()
+	export.StartWorker()
 
 	// ctx := context.Background()
 	// apitoolkitClient, err := apitoolkit.NewClient(ctx, apitoolkit.Config{APIKey: os.Getenv("API_TOOLKIT")})
@@ -30,8 +86,10 @@ func main() {
 
 	AddRoutes(router)
 
-	app.Run(":3000")
-
-	log.Print("Server listening on http://localhost:3000/")
+	address := fmt.Sprintf(":%d", cfg.Port)
+	log.Printf("Server listening on http://localhost%s/", address)
+	if err := app.Run(address); err != nil {
+		log.Fatal(err)
+	}
 
 }
@@ -0,0 +1,305 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"job_board/models"
+)
+
+// weights of every factor, they add up to 100 so the score reads as a percentage
+const (
+	skillsWeight     = 35.0
+	experienceWeight = 20.0
+	locationWeight   = 15.0
+	salaryWeight     = 15.0
+	educationWeight  = 10.0
+	languagesWeight  = 5.0
+)
+
+// levelYears maps words found in level names to the years of experience they usually ask for,
+// the first match wins so the more senior words come first
+var levelYears = []struct {
+	keyword string
+	years   float64
+}{
+	{"principal", 8},
+	{"staff", 8},
+	{"head", 8},
+	{"lead", 7},
+	{"senior", 5},
+	{"mid", 2},
+	{"intermediate", 2},
+	{"junior", 0.5},
+	{"entry", 0},
+	{"graduate", 0},
+	{"intern", 0},
+}
+
+// Reference is the lookup data shared by every score of a refresh
+type Reference struct {
+	// Languages is every known language, it is used to find the ones a job description asks for
+	Languages []models.Language
+	// Rates maps currencies to their exchange rate to the base currency
+	Rates map[uuid.UUID]float64
+}
+
+// Score computes how well the profile fits the job. The profile needs its user, educations,
+// experiences, work samples and languages loaded and the job its level
+func Score(profile models.Profile, job models.Job, ref Reference) (float64, models.MatchBreakdown) {
+	corpus := profileCorpus(profile)
+
+	breakdown := models.MatchBreakdown{
+		scoreSkills(corpus, job),
+		scoreExperience(profile, job),
+		scoreLocation(profile, job),
+		scoreSalary(profile, job, ref.Rates),
+		scoreEducation(profile, job),
+		scoreLanguages(profile, job, ref.Languages),
+	}
+
+	total := 0.0
+	for i := range breakdown {
+		breakdown[i].Score = round(breakdown[i].Score)
+		total += breakdown[i].Weight * breakdown[i].Score
+	}
+	return round(total), breakdown
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// profileCorpus is all the free text of a profile skills can be found in
+func profileCorpus(profile models.Profile) string {
+	parts := append([]string{profile.Bio}, profile.Skills...)
+	for _, education := range profile.Educations {
+		parts = append(parts, education.FieldOFStudy)
+	}
+	for _, internship := range profile.InternShipExperiences {
+		parts = append(parts, internship.Title, internship.Description)
+	}
+	for _, project := range profile.ProjectsExperiences {
+		parts = append(parts, project.Title, project.Description)
+	}
+	for _, sample := range profile.WorkSamples {
+		parts = append(parts, sample.Description)
+	}
+	return strings.ToLower(strings.Join(parts, " "))
+}
+
+// mentions reports whether the phrase shows up in the lower case text as a whole word. It
+// runs for every skill of every profile and job pair, so it scans the text instead of
+// building a regexp each time
+func mentions(text, phrase string) bool {
+	phrase = strings.ToLower(strings.TrimSpace(phrase))
+	if phrase == "" {
+		return false
+	}
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], phrase)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(phrase)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return false
+}
+
+// isWordRune is what the phrase can't touch on either side to count as a whole word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+func scoreSkills(corpus string, job models.Job) models.MatchFactor {
+	factor := models.MatchFactor{Name: "skills", Weight: skillsWeight}
+	if len(job.Skills) == 0 {
+		factor.Score = 1
+		factor.Reason = "the job doesn't list any skills"
+		return factor
+	}
+
+	for _, skill := range job.Skills {
+		if mentions(corpus, skill) {
+			factor.Matched = append(factor.Matched, skill)
+		} else {
+			factor.Missing = append(factor.Missing, skill)
+		}
+	}
+	factor.Score = float64(len(factor.Matched)) / float64(len(job.Skills))
+	factor.Reason = fmt.Sprintf("%d of %d required skills found in the profile", len(factor.Matched), len(job.Skills))
+	return factor
+}
+
+// experienceYears adds up internships and projects, a current or open ended one runs until now
+func experienceYears(profile models.Profile) float64 {
+	now := time.Now()
+	span := func(start time.Time, end *time.Time) time.Duration {
+		if start.IsZero() {
+			return 0
+		}
+		finish := now
+		if end != nil && !end.IsZero() {
+			finish = *end
+		}
+		if finish.Before(start) {
+			return 0
+		}
+		return finish.Sub(start)
+	}
+
+	var total time.Duration
+	for _, internship := range profile.InternShipExperiences {
+		total += span(internship.StartDate, internship.EndDate)
+	}
+	for _, project := range profile.ProjectsExperiences {
+		total += span(project.StartDate, project.EndDate)
+	}
+	return total.Hours() / (24 * 365)
+}
+
+func requiredYears(level models.Level) (float64, bool) {
+	name := strings.ToLower(level.Name)
+	for _, entry := range levelYears {
+		if strings.Contains(name, entry.keyword) {
+			return entry.years, true
+		}
+	}
+	return 0, false
+}
+
+func scoreExperience(profile models.Profile, job models.Job) models.MatchFactor {
+	factor := models.MatchFactor{Name: "experience", Weight: experienceWeight}
+	years := experienceYears(profile)
+
+	required, known := requiredYears(job.Level)
+	if !known {
+		factor.Score = 1
+		factor.Reason = fmt.Sprintf("level %q has no experience requirement", job.Level.Name)
+		return factor
+	}
+	if required == 0 || years >= required {
+		factor.Score = 1
+	} else {
+		factor.Score = years / required
+	}
+	factor.Reason = fmt.Sprintf("%.1f years of experience for a %s role that usually asks for %.1f", years, job.Level.Name, required)
+	return factor
+}
+
+func scoreLocation(profile models.Profile, job models.Job) models.MatchFactor {
+	factor := models.MatchFactor{Name: "location", Weight: locationWeight}
+	switch {
+	case profile.User.CountryID == uuid.Nil:
+		factor.Score = 0.5
+		factor.Reason = "the candidate hasn't set a country"
+	case profile.User.CountryID == job.CountryID:
+		factor.Score = 1
+		factor.Reason = "the candidate is in the job's country"
+	default:
+		factor.Reason = "the candidate is in a different country"
+	}
+	return factor
+}
+
+// scoreSalary compares the top of the job range with the expected salary, profiles keep a
+// yearly salary so both are compared as yearly amounts in the base currency
+func scoreSalary(profile models.Profile, job models.Job, rates map[uuid.UUID]float64) models.MatchFactor {
+	factor := models.MatchFactor{Name: "salary", Weight: salaryWeight}
+
+	expected := profile.ExpectedSalary
+	known := true
+	if id := profile.ExpectedSalaryCurrencyID; id != nil && *id != uuid.Nil {
+		rate, ok := rates[*id]
+		expected, known = expected*rate, ok
+	}
+	if job.SalaryCurrencyID != nil {
+		if _, ok := rates[*job.SalaryCurrencyID]; !ok {
+			known = false
+		}
+	}
+
+	switch {
+	case profile.ExpectedSalary == 0 || job.SalaryMax == 0 || job.SalaryHidden:
+		factor.Score = 1
+		factor.Reason = "no salary to compare"
+	case !known:
+		factor.Score = 1
+		factor.Reason = "no exchange rate to compare the salaries"
+	case job.SalaryMaxBase >= expected:
+		factor.Score = 1
+		factor.Reason = "the job pays at least the expected salary"
+	default:
+		factor.Score = job.SalaryMaxBase / expected
+		factor.Reason = fmt.Sprintf("the job pays %.0f%% of the expected salary", factor.Score*100)
+	}
+	return factor
+}
+
+func scoreEducation(profile models.Profile, job models.Job) models.MatchFactor {
+	factor := models.MatchFactor{Name: "education", Weight: educationWeight}
+	if len(profile.Educations) == 0 {
+		factor.Reason = "no education listed"
+		return factor
+	}
+
+	// a field of study sharing a word with the title or the skills counts as related
+	target := strings.ToLower(job.Title + " " + strings.Join(job.Skills, " "))
+	for _, education := range profile.Educations {
+		for _, word := range strings.Fields(education.FieldOFStudy) {
+			if len(word) > 3 && mentions(target, word) {
+				factor.Score = 1
+				factor.Matched = append(factor.Matched, education.FieldOFStudy)
+				factor.Reason = "related field of study"
+				return factor
+			}
+		}
+	}
+	factor.Score = 0.6
+	factor.Reason = "education in an unrelated field"
+	return factor
+}
+
+func scoreLanguages(profile models.Profile, job models.Job, languages []models.Language) models.MatchFactor {
+	factor := models.MatchFactor{Name: "languages", Weight: languagesWeight}
+
+	text := strings.ToLower(job.Title + " " + job.Description + " " + strings.Join(job.Skills, " "))
+	spoken := map[uuid.UUID]bool{}
+	for _, language := range profile.ProfileLanguages {
+		spoken[language.LanguageID] = true
+	}
+
+	for _, language := range languages {
+		if !mentions(text, language.Name) {
+			continue
+		}
+		if spoken[language.ID] {
+			factor.Matched = append(factor.Matched, language.Name)
+		} else {
+			factor.Missing = append(factor.Missing, language.Name)
+		}
+	}
+
+	required := len(factor.Matched) + len(factor.Missing)
+	if required == 0 {
+		factor.Score = 1
+		factor.Reason = "the job doesn't ask for a language"
+		return factor
+	}
+	factor.Score = float64(len(factor.Matched)) / float64(required)
+	factor.Reason = fmt.Sprintf("speaks %d of the %d languages the job mentions", len(factor.Matched), required)
+	return factor
+}
@@ -0,0 +1,197 @@
+package matching
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"job_board/models"
+)
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text, phrase string
+		want         bool
+	}{
+		{"built apis in go and python", "Go", true},
+		{"built apis in golang", "go", false},
+		{"a django developer", "go", false},
+		{"go", "go", true},
+		{"c++ and c# services", "c++", true},
+		{"ci/cd with github actions", "github actions", true},
+		{"node.js, react", "node.js", true},
+		{"wrote java", "javascript", false},
+		{"javascript only", "java", false},
+		{"java, then javascript", "javascript", true},
+		{"gogo go", "go", true},
+		{"kotlin2", "kotlin", false},
+		{"speaks français", "français", true},
+		{"speaks françaisé", "français", false},
+		{"anything", "  ", false},
+		{"", "go", false},
+	}
+	for _, tt := range tests {
+		if got := mentions(tt.text, tt.phrase); got != tt.want {
+			t.Errorf("mentions(%q, %q) = %v, want %v", tt.text, tt.phrase, got, tt.want)
+		}
+	}
+}
+
+func TestRequiredYears(t *testing.T) {
+	tests := []struct {
+		level string
+		years float64
+		known bool
+	}{
+		{"Senior", 5, true},
+		{"Senior Staff Engineer", 8, true},
+		{"Team Lead", 7, true},
+		{"Mid-level", 2, true},
+		{"Junior", 0.5, true},
+		{"Internship", 0, true},
+		{"Contract", 0, false},
+	}
+	for _, tt := range tests {
+		years, known := requiredYears(models.Level{Name: tt.level})
+		if years != tt.years || known != tt.known {
+			t.Errorf("requiredYears(%q) = %v, %v, want %v, %v", tt.level, years, known, tt.years, tt.known)
+		}
+	}
+}
+
+// yearsAgo is a start date the given number of years back
+func yearsAgo(years float64) time.Time {
+	return time.Now().Add(-time.Duration(years * 365 * 24 * float64(time.Hour)))
+}
+
+func TestScoreExperience(t *testing.T) {
+	end := yearsAgo(1)
+	profile := models.Profile{
+		InternShipExperiences: []models.InternShipExperience{
+			{StartDate: yearsAgo(2)},                                        // current, 2 years
+			{StartDate: yearsAgo(2), EndDate: &end},                         // 1 year
+			{StartDate: yearsAgo(1), EndDate: &[]time.Time{yearsAgo(3)}[0]}, // ends before it starts, ignored
+		},
+		ProjectsExperiences: []models.ProjectsExperience{{}}, // no dates, ignored
+	}
+
+	tests := []struct {
+		level string
+		score float64
+	}{
+		{"Senior", 0.6},
+		{"Mid", 1},
+		{"Graduate", 1},
+		{"Contract", 1},
+	}
+	for _, tt := range tests {
+		factor := scoreExperience(profile, models.Job{Level: models.Level{Name: tt.level}})
+		if math.Abs(factor.Score-tt.score) > 0.001 {
+			t.Errorf("%s: experience score %v, want %v (%s)", tt.level, factor.Score, tt.score, factor.Reason)
+		}
+	}
+}
+
+func TestScoreSalary(t *testing.T) {
+	naira, dollar, unknown := uuid.New(), uuid.New(), uuid.New()
+	rates := map[uuid.UUID]float64{naira: 0.001, dollar: 1}
+
+	tests := []struct {
+		name     string
+		expected float64
+		currency *uuid.UUID
+		job      models.Job
+		score    float64
+	}{
+		{"pays more", 50000, &dollar, models.Job{SalaryMax: 60000, SalaryMaxBase: 60000, SalaryCurrencyID: &dollar}, 1},
+		{"pays half", 50000, &dollar, models.Job{SalaryMax: 25000, SalaryMaxBase: 25000, SalaryCurrencyID: &dollar}, 0.5},
+		{"converted first", 40000000, &naira, models.Job{SalaryMax: 30000, SalaryMaxBase: 30000, SalaryCurrencyID: &dollar}, 0.75},
+		{"no currency is the base currency", 60000, nil, models.Job{SalaryMax: 30000, SalaryMaxBase: 30000}, 0.5},
+		{"hidden salary", 50000, &dollar, models.Job{SalaryMax: 100, SalaryMaxBase: 100, SalaryHidden: true}, 1},
+		{"no expectation", 0, nil, models.Job{SalaryMax: 100, SalaryMaxBase: 100}, 1},
+		{"no job salary", 50000, &dollar, models.Job{}, 1},
+		{"unknown profile currency", 50000, &unknown, models.Job{SalaryMax: 100, SalaryMaxBase: 100}, 1},
+		{"unknown job currency", 50000, &dollar, models.Job{SalaryMax: 100, SalaryMaxBase: 100, SalaryCurrencyID: &unknown}, 1},
+	}
+	for _, tt := range tests {
+		profile := models.Profile{ExpectedSalary: tt.expected, ExpectedSalaryCurrencyID: tt.currency}
+		factor := scoreSalary(profile, tt.job, rates)
+		if math.Abs(factor.Score-tt.score) > 0.001 {
+			t.Errorf("%s: salary score %v, want %v (%s)", tt.name, factor.Score, tt.score, factor.Reason)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	country, english, french := uuid.New(), uuid.New(), uuid.New()
+	ref := Reference{
+		Languages: []models.Language{{ID: english, Name: "English"}, {ID: french, Name: "French"}},
+		Rates:     map[uuid.UUID]float64{},
+	}
+	job := models.Job{
+		Title:       "Backend Engineer",
+		Description: "Fluent English and French, you'll work with our Paris office.",
+		CountryID:   country,
+		Level:       models.Level{Name: "Junior"},
+		Skills:      []string{"Go", "PostgreSQL", "Kubernetes", "gRPC"},
+	}
+
+	t.Run("perfect fit", func(t *testing.T) {
+		profile := models.Profile{
+			Bio:                   "Backend developer working in Go with PostgreSQL",
+			Skills:                []string{"Kubernetes", "gRPC"},
+			User:                  models.User{CountryID: country},
+			Educations:            []models.Education{{FieldOFStudy: "Backend Systems"}},
+			InternShipExperiences: []models.InternShipExperience{{StartDate: yearsAgo(1)}},
+			ProfileLanguages:      []models.ProfileLanguage{{LanguageID: english}, {LanguageID: french}},
+		}
+		total, breakdown := Score(profile, job, ref)
+		if total != 100 {
+			t.Errorf("total %v, want 100: %+v", total, breakdown)
+		}
+	})
+
+	t.Run("partial fit", func(t *testing.T) {
+		profile := models.Profile{
+			Skills:           []string{"go", "kubernetes"},
+			User:             models.User{CountryID: uuid.New()},
+			Educations:       []models.Education{{FieldOFStudy: "History"}},
+			ProfileLanguages: []models.ProfileLanguage{{LanguageID: english}},
+		}
+		total, breakdown := Score(profile, job, ref)
+
+		want := map[string]float64{
+			"skills":     0.5, // Go and Kubernetes of four
+			"experience": 0,   // none for a junior role
+			"location":   0,
+			"salary":     1, // nothing to compare
+			"education":  0.6,
+			"languages":  0.5, // English but not French
+		}
+		weights := 0.0
+		for _, factor := range breakdown {
+			weights += factor.Weight
+			if factor.Score != want[factor.Name] {
+				t.Errorf("%s scored %v, want %v (%s)", factor.Name, factor.Score, want[factor.Name], factor.Reason)
+			}
+		}
+		if weights != 100 {
+			t.Errorf("weights add up to %v", weights)
+		}
+		// 35*0.5 + 15*1 + 10*0.6 + 5*0.5
+		if total != 41 {
+			t.Errorf("total %v, want 41", total)
+		}
+	})
+
+	t.Run("no country set", func(t *testing.T) {
+		_, breakdown := Score(models.Profile{}, job, ref)
+		for _, factor := range breakdown {
+			if factor.Name == "location" && factor.Score != 0.5 {
+				t.Errorf("location scored %v without a country, want 0.5", factor.Score)
+			}
+		}
+	})
+}
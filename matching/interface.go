@@ -0,0 +1,12 @@
+package matching
+
+import "job_board/pagination"
+
+// matchSorts lets lists be ordered by score, best matches first by default
+var matchSorts = pagination.Sorts{
+	Default: "score",
+	Fields: map[string]string{
+		"score":      "score",
+		"updated_at": "updated_at",
+	},
+}
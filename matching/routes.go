@@ -0,0 +1,18 @@
+package matching
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"job_board/jwt"
+	"job_board/middleware"
+	"job_board/models"
+)
+
+func MatchRoutes(superRoute *gin.RouterGroup) {
+	matchRouter := superRoute.Group("/matches")
+
+	matchRouter.Use(jwt.Middleware())
+	matchRouter.GET("/recommendations", middleware.PermissionMiddleware(models.MatchReadOwn), recommendations)
+	matchRouter.GET("/jobs/:id", middleware.PermissionMiddleware(models.MatchReadOwn), jobMatch)
+	matchRouter.GET("/jobs/:id/candidates", middleware.PermissionMiddleware(models.CandidateReadCompany, models.CandidateReadAny), topCandidates)
+}
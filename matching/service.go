@@ -0,0 +1,143 @@
+package matching
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
+)
+
+var database *gorm.DB
+
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
+}
+
+func checkProfile(user models.User) bool {
+	if user.Profile == nil {
+		return true
+	}
+	return false
+}
+
+// liveScores leaves out scores of jobs and profiles that have since been deleted
+func liveScores(minScore float64) *gorm.DB {
+	db := database.Model(&models.MatchScore{}).
+		Where("job_id IN (SELECT id FROM jobs WHERE deleted_at IS NULL)").
+		Where("profile_id IN (SELECT id FROM profiles WHERE deleted_at IS NULL)")
+	if minScore > 0 {
+		db = db.Where("score >= ?", minScore)
+	}
+	return db
+}
+
+func getRecommendations(user models.User, minScore float64, params pagination.Params) (*pagination.Page[models.MatchScore], error) {
+	if profile := checkProfile(user); profile {
+		return nil, fmt.Errorf("you don't have a profile")
+	}
+
+	// only live jobs, and the ones the user already applied to aren't worth recommending again
+	db := liveScores(minScore).
+		Where("profile_id = ?", user.Profile.ID).
+		Where("job_id IN (SELECT id FROM jobs WHERE "+models.LiveJobs+")").
+		Where("job_id NOT IN (SELECT job_id FROM job_applications WHERE applicant_id = ? AND deleted_at IS NULL)", user.ID)
+
+	data, err := pagination.Find[models.MatchScore](db, params)
+	if err != nil {
+		log.Println("Error finding recommendations:", err)
+		return nil, err
+	}
+
+	jobIDs := make([]uuid.UUID, 0, len(data.Data))
+	for _, score := range data.Data {
+		jobIDs = append(jobIDs, score.JobID)
+	}
+	var jobs []models.Job
+	if err := database.Preload("Company").Preload("Level").Where("id IN ?", jobIDs).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.Job, len(jobs))
+	for i := range jobs {
+		byID[jobs[i].ID] = &jobs[i]
+	}
+	for i := range data.Data {
+		data.Data[i].Job = byID[data.Data[i].JobID]
+		if data.Data[i].Job != nil {
+			data.Data[i].Job.MaskSalary(&user)
+		}
+	}
+
+	return data, nil
+}
+
+func getCandidates(jobID uuid.UUID, user models.User, minScore float64, minCompleteness int, params pagination.Params) (*pagination.Page[models.MatchScore], error) {
+	var job models.Job
+	if err := database.First(&job, "id = ?", jobID).Error; err != nil {
+		return nil, err
+	}
+	if err := policy.Check(user, "candidate:read", policy.Company(job.CompanyID)); err != nil {
+		return nil, fmt.Errorf("you don't have permission to view candidates for this job")
+	}
+
+	db := liveScores(minScore).Where("job_id = ?", jobID)
+	if minCompleteness > 0 {
+		db = db.Where("profile_id IN (?)", database.Model(&models.Profile{}).Select("id").Where("completeness >= ?", minCompleteness))
+	}
+
+	data, err := pagination.Find[models.MatchScore](db, params)
+	if err != nil {
+		log.Println("Error finding candidates:", err)
+		return nil, err
+	}
+
+	profileIDs := make([]uuid.UUID, 0, len(data.Data))
+	for _, score := range data.Data {
+		profileIDs = append(profileIDs, score.ProfileID)
+	}
+	var profiles []models.Profile
+	if err := database.Preload("User").Where("id IN ?", profileIDs).Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.Profile, len(profiles))
+	for i := range profiles {
+		byID[profiles[i].ID] = &profiles[i]
+	}
+	for i := range data.Data {
+		data.Data[i].Profile = byID[data.Data[i].ProfileID]
+	}
+
+	return data, nil
+}
+
+// getJobMatch scores the user against a single job right away instead of waiting for the refresher
+func getJobMatch(jobID uuid.UUID, user models.User) (*models.MatchScore, error) {
+	if profile := checkProfile(user); profile {
+		return nil, fmt.Errorf("you don't have a profile")
+	}
+
+	var job models.Job
+	if err := database.Preload("Level").First(&job, "id = ?", jobID).Error; err != nil {
+		return nil, err
+	}
+	var profile models.Profile
+	if err := withProfileDetails(database).First(&profile, "id = ?", user.Profile.ID).Error; err != nil {
+		return nil, err
+	}
+	ref, err := loadReference()
+	if err != nil {
+		return nil, err
+	}
+
+	score, breakdown := Score(profile, job, ref)
+	record := models.MatchScore{ProfileID: profile.ID, JobID: job.ID, Score: score, Breakdown: breakdown}
+	if err := saveScores([]models.MatchScore{record}); err != nil {
+		return nil, fmt.Errorf("error saving match score: %w", err)
+	}
+	record.Job = &job
+	return &record, nil
+}
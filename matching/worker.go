@@ -0,0 +1,135 @@
+package matching
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"job_board/models"
+)
+
+const batchSize = 100
+
+// refresh asks for the scores of a job or of a profile to be recomputed
+type refresh struct {
+	jobID     uuid.UUID
+	profileID uuid.UUID
+}
+
+var refreshQueue = make(chan refresh, 100)
+
+func enqueue(r refresh) {
+	select {
+	case refreshQueue <- r:
+	default:
+		go func() { refreshQueue <- r }()
+	}
+}
+
+// QueueJob recomputes the scores of every profile against the job once it has been saved
+func QueueJob(jobID uuid.UUID) {
+	enqueue(refresh{jobID: jobID})
+}
+
+// QueueProfile recomputes the scores of the profile against every job once it or one of
+// its educations, experiences or languages has been saved
+func QueueProfile(profileID uuid.UUID) {
+	enqueue(refresh{profileID: profileID})
+}
+
+// Start runs the score refresher in the background
+func Start() {
+	go func() {
+		for r := range refreshQueue {
+			var err error
+			if r.jobID != uuid.Nil {
+				err = refreshJob(r.jobID)
+			} else {
+				err = refreshProfile(r.profileID)
+			}
+			if err != nil {
+				log.Printf("Failed to refresh match scores: %v", err)
+			}
+		}
+	}()
+}
+
+// withProfileDetails preloads everything Score reads from a profile
+func withProfileDetails(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("User").
+		Preload("Educations").
+		Preload("InternShipExperiences").
+		Preload("ProjectsExperiences").
+		Preload("WorkSamples").
+		Preload("ProfileLanguages")
+}
+
+func loadReference() (Reference, error) {
+	ref := Reference{Rates: map[uuid.UUID]float64{}}
+	if err := database.Find(&ref.Languages).Error; err != nil {
+		return ref, err
+	}
+	var rates []models.ExchangeRate
+	if err := database.Find(&rates).Error; err != nil {
+		return ref, err
+	}
+	for _, rate := range rates {
+		ref.Rates[rate.CurrencyID] = rate.Rate
+	}
+	return ref, nil
+}
+
+func saveScores(scores []models.MatchScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+	return database.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "profile_id"}, {Name: "job_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "breakdown", "updated_at"}),
+	}).Create(&scores).Error
+}
+
+func refreshJob(jobID uuid.UUID) error {
+	var job models.Job
+	if err := database.Preload("Level").First(&job, "id = ?", jobID).Error; err != nil {
+		return err
+	}
+	ref, err := loadReference()
+	if err != nil {
+		return err
+	}
+
+	var profiles []models.Profile
+	return withProfileDetails(database).FindInBatches(&profiles, batchSize, func(tx *gorm.DB, batch int) error {
+		scores := make([]models.MatchScore, 0, len(profiles))
+		for _, profile := range profiles {
+			score, breakdown := Score(profile, job, ref)
+			scores = append(scores, models.MatchScore{ProfileID: profile.ID, JobID: job.ID, Score: score, Breakdown: breakdown})
+		}
+		return saveScores(scores)
+	}).Error
+}
+
+func refreshProfile(profileID uuid.UUID) error {
+	var profile models.Profile
+	if err := withProfileDetails(database).First(&profile, "id = ?", profileID).Error; err != nil {
+		return err
+	}
+	ref, err := loadReference()
+	if err != nil {
+		return err
+	}
+
+	var jobs []models.Job
+	return database.Preload("Level").FindInBatches(&jobs, batchSize, func(tx *gorm.DB, batch int) error {
+		scores := make([]models.MatchScore, 0, len(jobs))
+		for _, job := range jobs {
+			score, breakdown := Score(profile, job, ref)
+			scores = append(scores, models.MatchScore{ProfileID: profile.ID, JobID: job.ID, Score: score, Breakdown: breakdown})
+		}
+		return saveScores(scores)
+	}).Error
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"job_board/models"
+	"job_board/policy"
+)
+
+// PermissionMiddleware lets the request through when the user holds any of the permissions,
+// record level scopes are checked again by the service
+func PermissionMiddleware(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user")
+		if !exists {
+			c.String(http.StatusUnauthorized, "User not found in session")
+			c.Abort()
+			return
+		}
+
+		user, ok := value.(models.User)
+		if !ok {
+			c.String(http.StatusInternalServerError, "Mismatching types")
+			c.Abort()
+			return
+		}
+
+		if !policy.CanAny(user, permissions...) {
+			c.String(http.StatusForbidden, "You don't have the required permission")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+
+	"job_board/models"
+)
+
+const usage = `usage: migrate <command>
+
+  up [version]   apply pending migrations, up to version when given
+  down [steps]   roll back the last applied migrations, 1 by default
+  status         list migrations and whether they have been applied`
+
+// Command runs the migrate subcommand, args are what follows "migrate" on the command line
+func Command(db *gorm.DB, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "up":
+		var target int64
+		if len(args) > 1 {
+			version, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || version < 1 {
+				return fmt.Errorf("version must be a positive number")
+			}
+			target = version
+		}
+		done, err := Up(db, target)
+		if err != nil {
+			return err
+		}
+		if err := models.Seed(db); err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Fprintln(out, "nothing to apply, the database is up to date")
+		}
+		for _, migration := range done {
+			fmt.Fprintf(out, "applied %d_%s\n", migration.Version, migration.Name)
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("steps must be a positive number")
+			}
+			steps = n
+		}
+		done, err := Down(db, steps)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Fprintln(out, "nothing to roll back")
+		}
+		for _, migration := range done {
+			fmt.Fprintf(out, "rolled back %d_%s\n", migration.Version, migration.Name)
+		}
+	case "status":
+		statuses, err := Statuses(db)
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Modified {
+				appliedAt += " (modified since)"
+			}
+			fmt.Fprintf(table, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return table.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %s\n%s", args[0], usage)
+	}
+	return nil
+}
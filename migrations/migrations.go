@@ -0,0 +1,299 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"job_board/models"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockID keeps two instances from migrating at the same time
+const lockID = 7425310
+
+// Migration is a pair of sql files named <version>_<name>.up.sql and <version>_<name>.down.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// SchemaMigration is a row of schema_migrations, one per applied migration
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	Checksum  string    `gorm:"type:varchar(64);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// Status is a migration along with whether and when it ran
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+	// the file changed after it was applied
+	Modified bool
+}
+
+// Load reads the embedded migrations in version order
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		direction := ""
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s has to end in .up.sql or .down.sql", name)
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s has to be named <version>_<name>", name)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", name, err)
+		}
+
+		content, err := files.ReadFile(path.Join("sql", name))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: label}
+			byVersion[version] = migration
+		}
+		if migration.Name != label {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, label)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func applied(db *gorm.DB) (map[int64]SchemaMigration, error) {
+	var rows []SchemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[int64]SchemaMigration, len(rows))
+	for _, row := range rows {
+		out[row.Version] = row
+	}
+	return out, nil
+}
+
+// prepare creates schema_migrations, a database made by AutoMigrate before migrations were
+// versioned is brought up to date first so the IF NOT EXISTS migrations can take over
+func prepare(db *gorm.DB) error {
+	if db.Migrator().HasTable(&SchemaMigration{}) {
+		return nil
+	}
+	legacy := db.Migrator().HasTable("users")
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+			return err
+		}
+		if tx.Migrator().HasTable(&SchemaMigration{}) {
+			return nil
+		}
+		if legacy {
+			log.Print("upgrading a database created before versioned migrations")
+			if err := models.UpgradeLegacySchema(tx); err != nil {
+				return fmt.Errorf("error upgrading legacy schema: %w", err)
+			}
+		}
+		return tx.Migrator().CreateTable(&SchemaMigration{})
+	})
+}
+
+// Up applies the pending migrations up to and including target, 0 applies all of them
+func Up(db *gorm.DB, target int64) ([]Migration, error) {
+	if err := prepare(db); err != nil {
+		return nil, err
+	}
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		ran, err := apply(db, migration)
+		if err != nil {
+			return done, fmt.Errorf("error applying migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			log.Printf("applied migration %d_%s", migration.Version, migration.Name)
+			done = append(done, migration)
+		}
+	}
+	return done, nil
+}
+
+// apply runs one migration in its own transaction, it is skipped when another instance
+// got to it first
+func apply(db *gorm.DB, migration Migration) (bool, error) {
+	ran := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&SchemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		if err := tx.Exec(migration.Up).Error; err != nil {
+			return err
+		}
+		ran = true
+		return tx.Create(&SchemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum(),
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	return ran, err
+}
+
+// Down rolls back the last steps applied migrations, newest first
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, errors.New("steps has to be at least 1")
+	}
+	if err := prepare(db); err != nil {
+		return nil, err
+	}
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int64]Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	var done []Migration
+	for i := 0; i < steps; i++ {
+		var last SchemaMigration
+		err := db.Order("version DESC").First(&last).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return done, err
+		}
+		migration, ok := known[last.Version]
+		if !ok {
+			return done, fmt.Errorf("migration %d_%s was applied but its files are gone", last.Version, last.Name)
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+		}); err != nil {
+			return done, fmt.Errorf("error rolling back migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		log.Printf("rolled back migration %d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Statuses lists every known migration and whether it has been applied
+func Statuses(db *gorm.DB) ([]Status, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	rows := map[int64]SchemaMigration{}
+	if db.Migrator().HasTable(&SchemaMigration{}) {
+		if rows, err = applied(db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, migration := range migrations {
+		status := Status{Migration: migration}
+		if row, ok := rows[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = row.Checksum != migration.Checksum()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending reports how many migrations haven't been applied yet
+func Pending(db *gorm.DB) (int, error) {
+	statuses, err := Statuses(db)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+	return pending, nil
+}
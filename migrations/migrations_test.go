@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+
+	"job_board/models"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s is in place %d, versions have gaps", migration.Version, migration.Name, i+1)
+		}
+	}
+}
+
+var addColumn = regexp.MustCompile(`ALTER TABLE "(\w+)" ADD COLUMN IF NOT EXISTS "(\w+)"`)
+
+// TestLegacyTablesGetEveryColumn covers the tables AutoMigrate made before the schema was
+// versioned that UpgradeLegacySchema leaves alone, 0002 skips them as they already exist so
+// any column added since needs an ALTER of its own
+func TestLegacyTablesGetEveryColumn(t *testing.T) {
+	tests := []struct {
+		model interface{}
+		// the columns the table had when it was made by AutoMigrate, and those
+		// UpgradeLegacySchema adds on its own
+		legacy []string
+	}{
+		{&models.User{}, []string{
+			"id", "created_at", "updated_at", "deleted_at", "name", "email", "picture", "role_name", "provider_id",
+			"mobile_number", "subscriber_id", "verification_token", "expires_at", "password", "country_id",
+		}},
+		{&models.Company{}, []string{
+			"id", "created_at", "updated_at", "deleted_at", "name", "description", "employees_size_id", "established",
+			"industry_id", "location", "logo", "user_id", "website",
+			// migrateCompanyVerification
+			"verified", "verified_at",
+		}},
+	}
+
+	migrations, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := map[string]map[string]bool{}
+	for _, migration := range migrations {
+		for _, m := range addColumn.FindAllStringSubmatch(migration.Up, -1) {
+			if added[m[1]] == nil {
+				added[m[1]] = map[string]bool{}
+			}
+			added[m[1]][m[2]] = true
+		}
+	}
+
+	for _, tt := range tests {
+		s, err := schema.Parse(tt.model, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		legacy := map[string]bool{}
+		for _, column := range tt.legacy {
+			legacy[column] = true
+		}
+		for _, field := range s.Fields {
+			if field.DBName == "" || legacy[field.DBName] || added[s.Table][field.DBName] {
+				continue
+			}
+			t.Errorf("%s.%s is missing from legacy databases, no migration adds it", s.Table, field.DBName)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+-- ids default to uuid_generate_v4()
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
@@ -0,0 +1,37 @@
+-- drops every table, children before the tables they point at
+DROP TABLE IF EXISTS "social_media_accounts";
+DROP TABLE IF EXISTS "social_media";
+DROP TABLE IF EXISTS "profile_languages";
+DROP TABLE IF EXISTS "language_proficiencies";
+DROP TABLE IF EXISTS "languages";
+DROP TABLE IF EXISTS "awards";
+DROP TABLE IF EXISTS "work_samples";
+DROP TABLE IF EXISTS "projects_experiences";
+DROP TABLE IF EXISTS "intern_ship_experiences";
+DROP TABLE IF EXISTS "educations";
+DROP TABLE IF EXISTS "academic_rankings";
+DROP TABLE IF EXISTS "degrees";
+DROP TABLE IF EXISTS "audit_logs";
+DROP TABLE IF EXISTS "company_verifications";
+DROP TABLE IF EXISTS "company_invitations";
+DROP TABLE IF EXISTS "company_members";
+DROP TABLE IF EXISTS "role_permissions";
+DROP TABLE IF EXISTS "match_scores";
+DROP TABLE IF EXISTS "profiles";
+DROP TABLE IF EXISTS "genders";
+DROP TABLE IF EXISTS "files";
+DROP TABLE IF EXISTS "saved_search_matches";
+DROP TABLE IF EXISTS "saved_searches";
+DROP TABLE IF EXISTS "application_status_histories";
+DROP TABLE IF EXISTS "job_applications";
+DROP TABLE IF EXISTS "pipeline_stages";
+DROP TABLE IF EXISTS "jobs";
+DROP TABLE IF EXISTS "exchange_rates";
+DROP TABLE IF EXISTS "salary_currencies";
+DROP TABLE IF EXISTS "levels";
+DROP TABLE IF EXISTS "job_types";
+DROP TABLE IF EXISTS "companies";
+DROP TABLE IF EXISTS "employees_sizes";
+DROP TABLE IF EXISTS "industries";
+DROP TABLE IF EXISTS "users";
+DROP TABLE IF EXISTS "countries";
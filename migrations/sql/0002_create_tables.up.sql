@@ -0,0 +1,583 @@
+-- every table the models map to, as gorm would create them. Indexes and foreign keys
+-- keep gorm's names so databases created by AutoMigrate line up
+
+CREATE TABLE IF NOT EXISTS "countries" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_countries_name" ON "countries" ("name");
+CREATE INDEX IF NOT EXISTS "idx_countries_deleted_at" ON "countries" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "email" text,
+    "picture" text,
+    "role_name" text,
+    "provider_id" text,
+    "mobile_number" varchar(25) DEFAULT null,
+    "subscriber_id" text DEFAULT null,
+    "verification_token" text,
+    "expires_at" timestamptz,
+    "email_verified_at" timestamptz,
+    "totp_secret" text DEFAULT null,
+    "totp_enabled_at" timestamptz,
+    "recovery_codes" text[],
+    "password" text DEFAULT null,
+    "country_id" uuid,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_country" FOREIGN KEY ("country_id") REFERENCES "countries"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_provider_id" ON "users" ("provider_id");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "industries" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_industries_name" UNIQUE ("name")
+);
+CREATE INDEX IF NOT EXISTS "idx_industries_deleted_at" ON "industries" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "employees_sizes" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_employees_sizes_deleted_at" ON "employees_sizes" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_employees_sizes_name" ON "employees_sizes" ("name");
+
+CREATE TABLE IF NOT EXISTS "companies" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(100) NOT NULL,
+    "description" text NOT NULL,
+    "website" varchar(512),
+    "industry_id" uuid NOT NULL,
+    "established" timestamptz,
+    "location" varchar(100),
+    "employees_size_id" uuid NOT NULL,
+    "logo" varchar(512) DEFAULT 'https://via.placeholder.com/200x200',
+    "user_id" uuid NOT NULL,
+    "verified" boolean NOT NULL DEFAULT false,
+    "verified_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_companies_industry" FOREIGN KEY ("industry_id") REFERENCES "industries"("id"),
+    CONSTRAINT "fk_companies_employees_size" FOREIGN KEY ("employees_size_id") REFERENCES "employees_sizes"("id"),
+    CONSTRAINT "fk_users_companies" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_companies_verified" ON "companies" ("verified");
+CREATE INDEX IF NOT EXISTS "idx_companies_deleted_at" ON "companies" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "job_types" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_job_types_name" ON "job_types" ("name");
+CREATE INDEX IF NOT EXISTS "idx_job_types_deleted_at" ON "job_types" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "levels" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_levels_name" ON "levels" ("name");
+CREATE INDEX IF NOT EXISTS "idx_levels_deleted_at" ON "levels" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "salary_currencies" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_salary_currencies_name" ON "salary_currencies" ("name");
+CREATE INDEX IF NOT EXISTS "idx_salary_currencies_deleted_at" ON "salary_currencies" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "exchange_rates" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "currency_id" uuid NOT NULL,
+    "rate" decimal(18,8) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_exchange_rates_currency" FOREIGN KEY ("currency_id") REFERENCES "salary_currencies"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_exchange_rates_currency_id" ON "exchange_rates" ("currency_id");
+
+CREATE TABLE IF NOT EXISTS "jobs" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "title" varchar(100) NOT NULL,
+    "description" text NOT NULL,
+    "country_id" uuid NOT NULL,
+    "salary_min" decimal(12,2) DEFAULT 0,
+    "salary_max" decimal(12,2) DEFAULT 0,
+    "salary_currency_id" uuid,
+    "pay_period" varchar(20) NOT NULL DEFAULT 'yearly',
+    "salary_hidden" boolean NOT NULL DEFAULT false,
+    "salary_min_base" decimal(14,2) NOT NULL DEFAULT 0,
+    "salary_max_base" decimal(14,2) NOT NULL DEFAULT 0,
+    "job_type_id" uuid NOT NULL,
+    "level_id" uuid NOT NULL,
+    "skills" text[] NOT NULL,
+    "search_vector" tsvector,
+    "company_id" uuid NOT NULL,
+    "company_verified" boolean NOT NULL DEFAULT false,
+    "state" varchar(20) NOT NULL DEFAULT 'published',
+    "publish_at" timestamptz,
+    "published_at" timestamptz,
+    "expires_at" timestamptz,
+    "expiry_notified_at" timestamptz,
+    "closed_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_jobs_job_type" FOREIGN KEY ("job_type_id") REFERENCES "job_types"("id"),
+    CONSTRAINT "fk_jobs_level" FOREIGN KEY ("level_id") REFERENCES "levels"("id"),
+    CONSTRAINT "fk_jobs_company" FOREIGN KEY ("company_id") REFERENCES "companies"("id"),
+    CONSTRAINT "fk_users_jobs" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_jobs_country" FOREIGN KEY ("country_id") REFERENCES "countries"("id"),
+    CONSTRAINT "fk_jobs_salary_currency" FOREIGN KEY ("salary_currency_id") REFERENCES "salary_currencies"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_jobs_expires_at" ON "jobs" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_jobs_state" ON "jobs" ("state");
+CREATE INDEX IF NOT EXISTS "idx_jobs_salary_max_base" ON "jobs" ("salary_max_base");
+CREATE INDEX IF NOT EXISTS "idx_jobs_salary_min_base" ON "jobs" ("salary_min_base");
+CREATE INDEX IF NOT EXISTS "idx_jobs_deleted_at" ON "jobs" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "pipeline_stages" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "company_id" uuid NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "status" varchar(50) NOT NULL,
+    "position" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_pipeline_stages_company" FOREIGN KEY ("company_id") REFERENCES "companies"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_company_stage_name" ON "pipeline_stages" ("company_id","name");
+CREATE INDEX IF NOT EXISTS "idx_pipeline_stages_deleted_at" ON "pipeline_stages" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "job_applications" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "job_id" uuid NOT NULL,
+    "applicant_id" uuid NOT NULL,
+    "status" varchar(50) DEFAULT 'applied',
+    "stage_id" uuid,
+    "applied_at" timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_job_applications_stage" FOREIGN KEY ("stage_id") REFERENCES "pipeline_stages"("id"),
+    CONSTRAINT "fk_users_job_applications" FOREIGN KEY ("applicant_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_jobs_job_applications" FOREIGN KEY ("job_id") REFERENCES "jobs"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_job_applications" ON "job_applications" ("job_id","applicant_id");
+CREATE INDEX IF NOT EXISTS "idx_job_applications_deleted_at" ON "job_applications" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "application_status_histories" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "job_application_id" uuid NOT NULL,
+    "from_status" varchar(50) NOT NULL,
+    "to_status" varchar(50) NOT NULL,
+    "from_stage_id" uuid,
+    "to_stage_id" uuid,
+    "changed_by_id" uuid NOT NULL,
+    "note" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_application_status_histories_changed_by" FOREIGN KEY ("changed_by_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_application_status_histories_job_application_id" ON "application_status_histories" ("job_application_id");
+
+CREATE TABLE IF NOT EXISTS "saved_searches" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" uuid NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "title" varchar(100),
+    "description" text,
+    "country_id" uuid,
+    "job_type_id" uuid,
+    "level_id" uuid,
+    "company_id" uuid,
+    "skills" text[],
+    "salary" decimal(12,2) DEFAULT 0,
+    "salary_currency_id" uuid,
+    "pay_period" varchar(20) NOT NULL DEFAULT 'yearly',
+    "frequency" varchar(20) NOT NULL DEFAULT 'daily',
+    "active" boolean NOT NULL DEFAULT true,
+    "unsubscribe_token" varchar(64),
+    "last_sent_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_saved_searches_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_saved_searches_unsubscribe_token" ON "saved_searches" ("unsubscribe_token");
+CREATE INDEX IF NOT EXISTS "idx_saved_searches_user_id" ON "saved_searches" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_saved_searches_deleted_at" ON "saved_searches" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "saved_search_matches" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "saved_search_id" uuid NOT NULL,
+    "job_id" uuid NOT NULL,
+    "sent_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_saved_search_matches_job" FOREIGN KEY ("job_id") REFERENCES "jobs"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_saved_search_job" ON "saved_search_matches" ("saved_search_id","job_id");
+
+CREATE TABLE IF NOT EXISTS "files" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "owner_id" uuid NOT NULL,
+    "name" varchar(255) NOT NULL,
+    "key" varchar(512) NOT NULL,
+    "driver" varchar(20) NOT NULL,
+    "content_type" varchar(100) NOT NULL,
+    "size" bigint NOT NULL,
+    "checksum" varchar(64) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_files_owner" FOREIGN KEY ("owner_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_files_key" ON "files" ("key");
+CREATE INDEX IF NOT EXISTS "idx_files_owner_id" ON "files" ("owner_id");
+CREATE INDEX IF NOT EXISTS "idx_files_deleted_at" ON "files" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "genders" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_genders_name" ON "genders" ("name");
+CREATE INDEX IF NOT EXISTS "idx_genders_deleted_at" ON "genders" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "profiles" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" uuid,
+    "bio" text NOT NULL,
+    "skills" text[],
+    "resume_id" uuid,
+    "gender_id" uuid NOT NULL,
+    "current_salary" decimal(10,2) DEFAULT 0,
+    "current_salary_currency_id" uuid,
+    "expected_salary" decimal(10,2) DEFAULT 0,
+    "expected_salary_currency_id" uuid,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_profiles_gender" FOREIGN KEY ("gender_id") REFERENCES "genders"("id"),
+    CONSTRAINT "fk_profiles_expected_salary_currency" FOREIGN KEY ("expected_salary_currency_id") REFERENCES "salary_currencies"("id"),
+    CONSTRAINT "fk_profiles_resume" FOREIGN KEY ("resume_id") REFERENCES "files"("id"),
+    CONSTRAINT "fk_profiles_current_salary_currency" FOREIGN KEY ("current_salary_currency_id") REFERENCES "salary_currencies"("id"),
+    CONSTRAINT "fk_users_profile" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_profiles_user_id" ON "profiles" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_profiles_deleted_at" ON "profiles" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "match_scores" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "profile_id" uuid NOT NULL,
+    "job_id" uuid NOT NULL,
+    "score" decimal(5,2) NOT NULL DEFAULT 0,
+    "breakdown" jsonb,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_match_scores_job" FOREIGN KEY ("job_id") REFERENCES "jobs"("id"),
+    CONSTRAINT "fk_match_scores_profile" FOREIGN KEY ("profile_id") REFERENCES "profiles"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_match_scores_score" ON "match_scores" ("score");
+CREATE INDEX IF NOT EXISTS "idx_match_scores_job_id" ON "match_scores" ("job_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_match_profile_job" ON "match_scores" ("profile_id","job_id");
+
+CREATE TABLE IF NOT EXISTS "role_permissions" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "role_name" text NOT NULL,
+    "permission" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_role_permission" ON "role_permissions" ("role_name","permission");
+
+CREATE TABLE IF NOT EXISTS "company_members" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "company_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "role" varchar(20) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_company_members_company" FOREIGN KEY ("company_id") REFERENCES "companies"("id"),
+    CONSTRAINT "fk_company_members_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_company_members_user_id" ON "company_members" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_company_member" ON "company_members" ("company_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "company_invitations" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "company_id" uuid NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "role" varchar(20) NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "invited_by_id" uuid NOT NULL,
+    "expires_at" timestamptz,
+    "accepted_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_company_invitations_company" FOREIGN KEY ("company_id") REFERENCES "companies"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_company_invitations_token_hash" ON "company_invitations" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_company_invitations_company_id" ON "company_invitations" ("company_id");
+
+CREATE TABLE IF NOT EXISTS "company_verifications" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "company_id" uuid NOT NULL,
+    "method" varchar(20) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "domain" varchar(255),
+    "email" varchar(255),
+    "record" varchar(255),
+    "code_hash" varchar(64),
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "note" text,
+    "review_note" text,
+    "requested_by_id" uuid NOT NULL,
+    "reviewed_by_id" uuid,
+    "expires_at" timestamptz,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_company_verifications_company" FOREIGN KEY ("company_id") REFERENCES "companies"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_company_verifications_status" ON "company_verifications" ("status");
+CREATE INDEX IF NOT EXISTS "idx_company_verifications_company_id" ON "company_verifications" ("company_id");
+
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "actor_id" uuid,
+    "actor_role" varchar(20),
+    "action" varchar(20) NOT NULL,
+    "resource_type" varchar(50) NOT NULL,
+    "resource_id" varchar(64) NOT NULL,
+    "before" jsonb,
+    "after" jsonb,
+    "ip" varchar(64),
+    "user_agent" text,
+    "method" varchar(10),
+    "path" varchar(255),
+    "request_id" varchar(64),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_resource" ON "audit_logs" ("resource_type","resource_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_id" ON "audit_logs" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_request_id" ON "audit_logs" ("request_id");
+
+CREATE TABLE IF NOT EXISTS "degrees" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_degrees_name" ON "degrees" ("name");
+CREATE INDEX IF NOT EXISTS "idx_degrees_deleted_at" ON "degrees" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "academic_rankings" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_academic_rankings_name" ON "academic_rankings" ("name");
+CREATE INDEX IF NOT EXISTS "idx_academic_rankings_deleted_at" ON "academic_rankings" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "educations" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "profile_id" uuid NOT NULL,
+    "institution_name" varchar(100) NOT NULL,
+    "field_of_study" varchar(250) NOT NULL,
+    "degree_id" uuid NOT NULL,
+    "academic_ranking_id" uuid NOT NULL,
+    "graduation_year" bigint NOT NULL,
+    "start_date" timestamptz,
+    "end_date" timestamptz,
+    "is_current" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_educations_degree" FOREIGN KEY ("degree_id") REFERENCES "degrees"("id"),
+    CONSTRAINT "fk_educations_academic_ranking" FOREIGN KEY ("academic_ranking_id") REFERENCES "academic_rankings"("id"),
+    CONSTRAINT "fk_profiles_educations" FOREIGN KEY ("profile_id") REFERENCES "profiles"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_educations_deleted_at" ON "educations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "intern_ship_experiences" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "profile_id" uuid NOT NULL,
+    "company_name" varchar(250) NOT NULL,
+    "title" varchar(100) NOT NULL,
+    "description" text NOT NULL,
+    "start_date" timestamptz,
+    "end_date" timestamptz,
+    "is_current" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_profiles_intern_ship_experiences" FOREIGN KEY ("profile_id") REFERENCES "profiles"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_intern_ship_experiences_deleted_at" ON "intern_ship_experiences" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "projects_experiences" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "profile_id" uuid NOT NULL,
+    "project_name" varchar(250) NOT NULL,
+    "title" varchar(100) NOT NULL,
+    "description" text NOT NULL,
+    "link" varchar(512) NOT NULL,
+    "start_date" timestamptz,
+    "end_date" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_profiles_projects_experiences" FOREIGN KEY ("profile_id") REFERENCES "profiles"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_projects_experiences_deleted_at" ON "projects_experiences" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "work_samples" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "profile_id" uuid NOT NULL,
+    "attachment_id" uuid,
+    "link" varchar(512) NOT NULL,
+    "description" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_work_samples_attachment" FOREIGN KEY ("attachment_id") REFERENCES "files"("id"),
+    CONSTRAINT "fk_profiles_work_samples" FOREIGN KEY ("profile_id") REFERENCES "profiles"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_work_samples_deleted_at" ON "work_samples" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "awards" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "profile_id" uuid NOT NULL,
+    "title" varchar(100) NOT NULL,
+    "year" bigint NOT NULL,
+    "description" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_profiles_awards" FOREIGN KEY ("profile_id") REFERENCES "profiles"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_awards_deleted_at" ON "awards" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "languages" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_languages_name" ON "languages" ("name");
+CREATE INDEX IF NOT EXISTS "idx_languages_deleted_at" ON "languages" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "language_proficiencies" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_language_proficiencies_name" ON "language_proficiencies" ("name");
+CREATE INDEX IF NOT EXISTS "idx_language_proficiencies_deleted_at" ON "language_proficiencies" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "profile_languages" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "profile_id" uuid NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "language_id" uuid NOT NULL,
+    "language_proficiency_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_profile_languages_language_proficiency" FOREIGN KEY ("language_proficiency_id") REFERENCES "language_proficiencies"("id"),
+    CONSTRAINT "fk_profiles_profile_languages" FOREIGN KEY ("profile_id") REFERENCES "profiles"("id"),
+    CONSTRAINT "fk_profile_languages_language" FOREIGN KEY ("language_id") REFERENCES "languages"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_profile_languages_deleted_at" ON "profile_languages" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "social_media" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" varchar(250) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_social_media_name" ON "social_media" ("name");
+CREATE INDEX IF NOT EXISTS "idx_social_media_deleted_at" ON "social_media" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "social_media_accounts" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "profile_id" uuid NOT NULL,
+    "link" varchar(512) NOT NULL,
+    "social_media_id" uuid NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_social_media_accounts_social_media" FOREIGN KEY ("social_media_id") REFERENCES "social_media"("id"),
+    CONSTRAINT "fk_profiles_social_media_accounts" FOREIGN KEY ("profile_id") REFERENCES "profiles"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_social_media_accounts_deleted_at" ON "social_media_accounts" ("deleted_at");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_jobs_skills;
+DROP INDEX IF EXISTS idx_jobs_search_vector;
@@ -0,0 +1,3 @@
+-- full text search and skill filters, search_vector is kept up to date by RefreshJobSearch
+CREATE INDEX IF NOT EXISTS idx_jobs_search_vector ON jobs USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_jobs_skills ON jobs USING GIN (skills);
@@ -0,0 +1 @@
+ALTER TABLE job_applications DROP CONSTRAINT IF EXISTS unique_applicant_job;
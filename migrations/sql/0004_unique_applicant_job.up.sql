@@ -0,0 +1,8 @@
+-- a user can only apply once to a job
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_applicant_job') THEN
+        ALTER TABLE job_applications ADD CONSTRAINT unique_applicant_job UNIQUE (applicant_id, job_id);
+    END IF;
+END;
+$$;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "outbox_messages" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "kind" varchar(20) NOT NULL,
+    "event_id" varchar(100) NOT NULL,
+    "payload" jsonb NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "provider" varchar(20),
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz NOT NULL,
+    "last_error" text,
+    "sent_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_outbox_due" ON "outbox_messages" ("status", "next_attempt_at");
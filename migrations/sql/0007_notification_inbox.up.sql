@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "user_id" uuid NOT NULL,
+    "category" varchar(50) NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "body" text,
+    "link" varchar(255),
+    "read_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_feed" ON "notifications" ("user_id", "created_at");
+CREATE INDEX IF NOT EXISTS "idx_notifications_unread" ON "notifications" ("user_id") WHERE "read_at" IS NULL;
+
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "user_id" uuid NOT NULL,
+    "category" varchar(50) NOT NULL,
+    "enabled" boolean NOT NULL DEFAULT true,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_notification_preferences_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_notification_preference" ON "notification_preferences" ("user_id", "category");
@@ -0,0 +1,8 @@
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS "idx_users_erasure";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "erased_at";
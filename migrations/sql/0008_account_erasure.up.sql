@@ -0,0 +1,23 @@
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "erased_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_users_erasure" ON "users" ("deleted_at") WHERE "deleted_at" IS NOT NULL AND "erased_at" IS NULL;
+
+-- erasing an account may scrub the personal data out of entries, only when the transaction
+-- asks for it with job_board.erasure and only the values, never which change was made
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND current_setting('job_board.erasure', true) = 'on'
+        AND NEW.id = OLD.id
+        AND NEW.actor_id IS NOT DISTINCT FROM OLD.actor_id
+        AND NEW.actor_role IS NOT DISTINCT FROM OLD.actor_role
+        AND NEW.action = OLD.action
+        AND NEW.resource_type = OLD.resource_type
+        AND NEW.resource_id = OLD.resource_id
+        AND NEW.method IS NOT DISTINCT FROM OLD.method
+        AND NEW.path IS NOT DISTINCT FROM OLD.path
+        AND NEW.request_id IS NOT DISTINCT FROM OLD.request_id
+        AND NEW.created_at IS NOT DISTINCT FROM OLD.created_at THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_logs is append only';
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS "data_exports" (
+    "id" uuid DEFAULT uuid_generate_v4(),
+    "user_id" uuid NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "key" varchar(512),
+    "driver" varchar(20),
+    "size" bigint NOT NULL DEFAULT 0,
+    "error" text,
+    "started_at" timestamptz,
+    "completed_at" timestamptz,
+    "expires_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_data_exports_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_data_exports_user_id" ON "data_exports" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_data_exports_status" ON "data_exports" ("status");
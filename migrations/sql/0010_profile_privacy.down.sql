@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "idx_profiles_slug";
+ALTER TABLE "profiles" DROP COLUMN IF EXISTS "hide_contact";
+ALTER TABLE "profiles" DROP COLUMN IF EXISTS "hide_salary";
+ALTER TABLE "profiles" DROP COLUMN IF EXISTS "visibility";
+ALTER TABLE "profiles" DROP COLUMN IF EXISTS "slug";
@@ -0,0 +1,8 @@
+ALTER TABLE "profiles" ADD COLUMN IF NOT EXISTS "slug" varchar(32);
+ALTER TABLE "profiles" ADD COLUMN IF NOT EXISTS "visibility" varchar(20) NOT NULL DEFAULT 'applied_employers';
+ALTER TABLE "profiles" ADD COLUMN IF NOT EXISTS "hide_salary" boolean NOT NULL DEFAULT false;
+ALTER TABLE "profiles" ADD COLUMN IF NOT EXISTS "hide_contact" boolean NOT NULL DEFAULT false;
+
+-- existing profiles get a random slug like the ones made on create
+UPDATE "profiles" SET "slug" = substr(md5(random()::text || "id"::text), 1, 16) WHERE "slug" IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_profiles_slug" ON "profiles" ("slug");
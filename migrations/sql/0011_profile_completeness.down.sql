@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "profile_reminders";
+DROP INDEX IF EXISTS "idx_profiles_completeness";
+ALTER TABLE "profiles" DROP COLUMN IF EXISTS "completeness";
@@ -0,0 +1,12 @@
+-- null until the completeness worker has scored the profile, existing profiles are
+-- scored in the background after the upgrade
+ALTER TABLE "profiles" ADD COLUMN IF NOT EXISTS "completeness" smallint;
+CREATE INDEX IF NOT EXISTS "idx_profiles_completeness" ON "profiles" ("completeness");
+
+CREATE TABLE IF NOT EXISTS "profile_reminders" (
+    "user_id" uuid,
+    "sent" bigint NOT NULL DEFAULT 0,
+    "last_sent_at" timestamptz,
+    PRIMARY KEY ("user_id"),
+    CONSTRAINT "fk_profile_reminders_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
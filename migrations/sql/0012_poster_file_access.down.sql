@@ -0,0 +1,3 @@
+DELETE FROM "role_permissions" WHERE "role_name" = 'poster' AND "permission" = 'file:read:own';
+UPDATE "role_permissions" SET "permission" = 'file:read:any'
+WHERE "role_name" = 'poster' AND "permission" = 'file:read:company';
@@ -0,0 +1,9 @@
+-- posters could read every upload, they now read their own and those of people who applied
+-- to their companies
+UPDATE "role_permissions" SET "permission" = 'file:read:company'
+WHERE "role_name" = 'poster' AND "permission" = 'file:read:any';
+
+INSERT INTO "role_permissions" ("role_name", "permission", "created_at")
+SELECT 'poster', 'file:read:own', now()
+WHERE EXISTS (SELECT 1 FROM "role_permissions" WHERE "role_name" = 'poster')
+ON CONFLICT ("role_name", "permission") DO NOTHING;
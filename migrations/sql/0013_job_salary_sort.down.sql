@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_jobs_salary_sort";
+ALTER TABLE "jobs" DROP COLUMN IF EXISTS "salary_sort";
@@ -0,0 +1,5 @@
+-- the salary sort reads this instead of salary_max_base, a hidden salary would otherwise
+-- show through the order of the results and the sort value in the page cursors
+ALTER TABLE "jobs" ADD COLUMN IF NOT EXISTS "salary_sort" decimal(14,2)
+    GENERATED ALWAYS AS (CASE WHEN "salary_hidden" THEN 0 ELSE "salary_max_base" END) STORED;
+CREATE INDEX IF NOT EXISTS "idx_jobs_salary_sort" ON "jobs" ("salary_sort");
@@ -0,0 +1 @@
+DELETE FROM "role_permissions" WHERE "role_name" = 'admin' AND "permission" = 'audit:read';
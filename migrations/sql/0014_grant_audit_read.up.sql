@@ -0,0 +1,6 @@
+-- databases seeded before the audit log have admin permissions already, so the seed leaves
+-- them alone. This adds the new default once, an admin grant removed afterwards stays removed
+INSERT INTO "role_permissions" ("role_name", "permission", "created_at")
+SELECT 'admin', 'audit:read', now()
+WHERE EXISTS (SELECT 1 FROM "role_permissions" WHERE "role_name" = 'admin')
+ON CONFLICT ("role_name", "permission") DO NOTHING;
@@ -0,0 +1,3 @@
+-- the column belongs to the users table 0002 creates, new databases keep it after a
+-- rollback so nothing is dropped here
+SELECT 1;
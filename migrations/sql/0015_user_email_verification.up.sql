@@ -0,0 +1,3 @@
+-- 0002 only creates users on new databases, a users table made by AutoMigrate before the
+-- schema was versioned is missing the column local accounts are verified with
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_verified_at" timestamptz;
@@ -0,0 +1,3 @@
+-- the columns belong to the users table 0002 creates, new databases keep them after a
+-- rollback so nothing is dropped here
+SELECT 1;
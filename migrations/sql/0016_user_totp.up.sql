@@ -0,0 +1,5 @@
+-- the TOTP columns admins sign in with, users tables made by AutoMigrate before the schema
+-- was versioned don't have them
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_secret" text DEFAULT null;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_enabled_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "recovery_codes" text[];
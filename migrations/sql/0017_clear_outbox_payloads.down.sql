@@ -0,0 +1,2 @@
+-- cleared payloads can't be brought back
+SELECT 1;
@@ -0,0 +1,2 @@
+-- dead messages used to keep their payload, clear it like the worker now does
+UPDATE "outbox_messages" SET "payload" = '{}' WHERE "status" IN ('sent', 'dead') AND "payload" <> '{}';
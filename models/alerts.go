@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type AlertFrequency string
+
+const (
+	DailyAlert  AlertFrequency = "daily"
+	WeeklyAlert AlertFrequency = "weekly"
+)
+
+func ParseAlertFrequency(str string) (AlertFrequency, error) {
+	switch str {
+	case "", "daily":
+		return DailyAlert, nil
+	case "weekly":
+		return WeeklyAlert, nil
+	default:
+		return "", fmt.Errorf("unsupported frequency: %s, use daily or weekly", str)
+	}
+}
+
+// Interval is how long to wait between two digests
+func (f AlertFrequency) Interval() time.Duration {
+	if f == WeeklyAlert {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// SavedSearch is a stored job filter a user gets digests for
+type SavedSearch struct {
+	gorm.Model
+	ID               uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID           uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	User             User           `gorm:"foreignKey:UserID" json:"-"`
+	Name             string         `gorm:"type:varchar(100);not null" json:"name"`
+	Title            string         `gorm:"type:varchar(100)" json:"title"`
+	Description      string         `gorm:"type:text" json:"description"`
+	CountryID        *uuid.UUID     `gorm:"type:uuid" json:"country_id"`
+	JobTypeID        *uuid.UUID     `gorm:"type:uuid" json:"job_type_id"`
+	LevelID          *uuid.UUID     `gorm:"type:uuid" json:"level_id"`
+	CompanyID        *uuid.UUID     `gorm:"type:uuid" json:"company_id"`
+	Skills           pq.StringArray `gorm:"type:text[]" json:"skills"`
+	Salary           float64        `gorm:"type:decimal(12,2);default:0.0" json:"salary"`
+	SalaryCurrencyID *uuid.UUID     `gorm:"type:uuid" json:"salary_currency_id"`
+	PayPeriod        PayPeriod      `gorm:"type:varchar(20);not null;default:'yearly'" json:"pay_period"`
+	Frequency        AlertFrequency `gorm:"type:varchar(20);not null;default:'daily'" json:"frequency"`
+	Active           bool           `gorm:"not null;default:true" json:"active"`
+	UnsubscribeToken string         `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	LastSentAt       *time.Time     `json:"last_sent_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"deleted_at,omitempty"`
+}
+
+// SavedSearchMatch is a job waiting to go out in the next digest of a saved search
+type SavedSearchMatch struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	SavedSearchID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_job" json:"saved_search_id"`
+	JobID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_job" json:"job_id"`
+	Job           Job        `gorm:"foreignKey:JobID" json:"job"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
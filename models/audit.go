@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate    AuditAction = "create"
+	AuditUpdate    AuditAction = "update"
+	AuditDelete    AuditAction = "delete"
+	AuditReinstate AuditAction = "reinstate"
+	// a hard delete, the row is gone for good
+	AuditPurge AuditAction = "purge"
+	// the tombstone of an erased account, its earlier entries had their personal data removed
+	AuditErase AuditAction = "erase"
+)
+
+// AuditValues holds the columns of a row an audit entry is about
+type AuditValues map[string]interface{}
+
+func (v AuditValues) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (v *AuditValues) Scan(value interface{}) error {
+	switch raw := value.(type) {
+	case []byte:
+		return json.Unmarshal(raw, v)
+	case string:
+		return json.Unmarshal([]byte(raw), v)
+	case nil:
+		*v = nil
+		return nil
+	default:
+		return errors.New("unsupported type for audit values")
+	}
+}
+
+// AuditLog is one change to one row, entries are only ever inserted. Before and After
+// only carry the columns that changed, creates have no Before and deletes no After
+type AuditLog struct {
+	ID           uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ActorID      *uuid.UUID  `gorm:"type:uuid;index" json:"actor_id"` // nil for the system, e.g the scheduler
+	ActorRole    RoleAllowed `gorm:"type:varchar(20)" json:"actor_role,omitempty"`
+	Action       AuditAction `gorm:"type:varchar(20);not null;index" json:"action"`
+	ResourceType string      `gorm:"type:varchar(50);not null;index:idx_audit_resource" json:"resource_type"`
+	ResourceID   string      `gorm:"type:varchar(64);not null;index:idx_audit_resource" json:"resource_id"`
+	Before       AuditValues `gorm:"type:jsonb" json:"before,omitempty"`
+	After        AuditValues `gorm:"type:jsonb" json:"after,omitempty"`
+	IP           string      `gorm:"type:varchar(64)" json:"ip,omitempty"`
+	UserAgent    string      `gorm:"type:text" json:"user_agent,omitempty"`
+	Method       string      `gorm:"type:varchar(10)" json:"method,omitempty"`
+	Path         string      `gorm:"type:varchar(255)" json:"path,omitempty"`
+	RequestID    string      `gorm:"type:varchar(64);index" json:"request_id,omitempty"`
+	CreatedAt    time.Time   `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportStatus string
+
+const (
+	ExportPending ExportStatus = "pending"
+	ExportRunning ExportStatus = "running"
+	ExportReady   ExportStatus = "ready"
+	ExportFailed  ExportStatus = "failed"
+	// the archive was removed once it was kept long enough
+	ExportExpired ExportStatus = "expired"
+)
+
+// DataExport is a zip of everything kept about a user, it is built in the background and
+// kept in the file storage until it expires
+type DataExport struct {
+	ID          uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID      uuid.UUID    `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status      ExportStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Key         string       `gorm:"type:varchar(512)" json:"-"`
+	Driver      string       `gorm:"type:varchar(20)" json:"-"`
+	Size        int64        `gorm:"not null;default:0" json:"size"`
+	Error       string       `gorm:"type:text" json:"error,omitempty"`
+	StartedAt   *time.Time   `json:"started_at"`
+	CompletedAt *time.Time   `json:"completed_at"`
+	ExpiresAt   *time.Time   `json:"expires_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// File is an upload kept by one of the storage backends, other records point at it by id
+type File struct {
+	gorm.Model
+	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OwnerID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"owner_id"`
+	Owner       User           `gorm:"foreignKey:OwnerID" json:"-"`
+	Name        string         `gorm:"type:varchar(255);not null" json:"name"`
+	Key         string         `gorm:"type:varchar(512);not null;uniqueIndex" json:"-"`
+	Driver      string         `gorm:"type:varchar(20);not null" json:"-"`
+	ContentType string         `gorm:"type:varchar(100);not null" json:"content_type"`
+	Size        int64          `gorm:"not null" json:"size"`
+	Checksum    string         `gorm:"type:varchar(64);not null" json:"checksum"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty"`
+}
+
+// CheckFileOwner makes sure a record only references a file its user uploaded
+func CheckFileOwner(tx *gorm.DB, fileID uuid.UUID, userID uuid.UUID) error {
+	var file File
+	if err := tx.First(&file, "id = ?", fileID).Error; err != nil {
+		return fmt.Errorf("file %s not found", fileID)
+	}
+	if file.OwnerID != userID {
+		return fmt.Errorf("file %s doesn't belong to you", fileID)
+	}
+	return nil
+}
+
+// migrateFileReferences relaxes the old free text resume and attachment columns now that
+// profiles and work samples point at files instead
+func migrateFileReferences(tx *gorm.DB) error {
+	columns := map[string]string{"profiles": "resume", "work_samples": "attachment"}
+	for table, column := range columns {
+		if !tx.Migrator().HasColumn(table, column) {
+			continue
+		}
+		if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", table, column)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
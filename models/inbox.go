@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationCategory string
+
+const (
+	// an application the user made moved to another status or stage
+	ApplicationStatusCategory NotificationCategory = "application_status"
+	// somebody applied to a job the user posted
+	NewApplicantCategory NotificationCategory = "new_applicant"
+	// a job the user posted is about to expire
+	JobExpiryCategory NotificationCategory = "job_expiry"
+	// the user's profile is still missing sections a while after signing up
+	ProfileReminderCategory NotificationCategory = "profile_reminder"
+)
+
+// NotificationCategories lists every category a user can turn off
+var NotificationCategories = []NotificationCategory{ApplicationStatusCategory, NewApplicantCategory, JobExpiryCategory, ProfileReminderCategory}
+
+func ParseNotificationCategory(str string) (NotificationCategory, error) {
+	for _, category := range NotificationCategories {
+		if string(category) == str {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported notification category: %s", str)
+}
+
+// Notification is an entry in a user's in-app feed
+type Notification struct {
+	ID       uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID   uuid.UUID            `gorm:"type:uuid;not null;index:idx_notifications_feed" json:"user_id"`
+	Category NotificationCategory `gorm:"type:varchar(50);not null" json:"category"`
+	Title    string               `gorm:"type:varchar(255);not null" json:"title"`
+	Body     string               `gorm:"type:text" json:"body"`
+	// Link is the api path of the record it is about
+	Link      string     `gorm:"type:varchar(255)" json:"link,omitempty"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"index:idx_notifications_feed" json:"created_at"`
+}
+
+// NotificationPreference turns a category of the feed on or off for a user, categories
+// without a row are on
+type NotificationPreference struct {
+	ID        uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"-"`
+	UserID    uuid.UUID            `gorm:"type:uuid;not null;uniqueIndex:idx_notification_preference" json:"-"`
+	Category  NotificationCategory `gorm:"type:varchar(50);not null;uniqueIndex:idx_notification_preference" json:"category"`
+	Enabled   bool                 `gorm:"not null;default:true" json:"enabled"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
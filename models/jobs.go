@@ -41,6 +41,8 @@ type Company struct {
 	Logo            string         `gorm:"type:varchar(512);default:'https://via.placeholder.com/200x200'"`
 	UserID          uuid.UUID      `gorm:"type:uuid;not null"` // Removed uniqueIndex
 	User            User           `gorm:"foreignKey:UserID"`
+	Verified        bool           `gorm:"not null;default:false;index" json:"verified"`
+	VerifiedAt      *time.Time     `json:"verified_at"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty"`
@@ -86,65 +88,121 @@ type Country struct {
 
 type Job struct {
 	gorm.Model
-	ID              uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Title           string           `gorm:"type:varchar(100);not null"`
-	Description     string           `gorm:"type:text;not null"`
-	CountryID       uuid.UUID        `gorm:"type:uuid;not null"`
-	Country         Country          `gorm:"foreignKey:CountryID"`
-	Salary          float64          `gorm:"type:decimal(10,2);default:0.0"`
-	JobTypeID       uuid.UUID        `gorm:"type:uuid;not null"`
-	JobType         JobType          `gorm:"foreignKey:JobTypeID"`
-	LevelID         uuid.UUID        `gorm:"type:uuid;not null"`
-	Level           Level            `gorm:"foreignKey:LevelID"`
-	Skills          pq.StringArray   `json:"skills" gorm:"type:text[]; not null"`
-	CompanyID       uuid.UUID        `gorm:"type:uuid;not null"`
-	Company         Company          `gorm:"foreignKey: CompanyID"`
-	JobApplications []JobApplication `gorm:"foreignKey:JobID"`
-	UserID          uuid.UUID        `gorm:"type:uuid;not null"` // Removed uniqueIndex
-	User            User             `gorm:"foreignKey:UserID"`
-	CreatedAt       time.Time        `json:"created_at"`
-	UpdatedAt       time.Time        `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt   `json:"deleted_at,omitempty"`
+	ID               uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Title            string           `gorm:"type:varchar(100);not null"`
+	Description      string           `gorm:"type:text;not null"`
+	CountryID        uuid.UUID        `gorm:"type:uuid;not null"`
+	Country          Country          `gorm:"foreignKey:CountryID"`
+	SalaryMin        float64          `gorm:"type:decimal(12,2);default:0.0" json:"salary_min"`
+	SalaryMax        float64          `gorm:"type:decimal(12,2);default:0.0" json:"salary_max"`
+	SalaryCurrencyID *uuid.UUID       `gorm:"type:uuid" json:"salary_currency_id"`
+	SalaryCurrency   *SalaryCurrency  `gorm:"foreignKey:SalaryCurrencyID" json:"salary_currency,omitempty"`
+	PayPeriod        PayPeriod        `gorm:"type:varchar(20);not null;default:'yearly'" json:"pay_period"`
+	SalaryHidden     bool             `gorm:"not null;default:false" json:"salary_hidden"`
+	SalaryMinBase    float64          `gorm:"type:decimal(14,2);not null;default:0;index" json:"-"` // maintained by RefreshJobSalaries
+	SalaryMaxBase    float64          `gorm:"type:decimal(14,2);not null;default:0;index" json:"-"`
+	SalarySort       float64          `gorm:"type:decimal(14,2);->;index" json:"-"` // generated, hidden salaries sort as 0
+	JobTypeID        uuid.UUID        `gorm:"type:uuid;not null"`
+	JobType          JobType          `gorm:"foreignKey:JobTypeID"`
+	LevelID          uuid.UUID        `gorm:"type:uuid;not null"`
+	Level            Level            `gorm:"foreignKey:LevelID"`
+	Skills           pq.StringArray   `json:"skills" gorm:"type:text[]; not null"`
+	SearchVector     string           `gorm:"type:tsvector;->:false;<-:false" json:"-"` // maintained by RefreshJobSearch
+	CompanyID        uuid.UUID        `gorm:"type:uuid;not null"`
+	Company          Company          `gorm:"foreignKey: CompanyID"`
+	CompanyVerified  bool             `gorm:"not null;default:false" json:"company_verified"` // copied by SetCompanyVerified
+	State            JobState         `gorm:"type:varchar(20);not null;default:'published';index" json:"state"`
+	PublishAt        *time.Time       `json:"publish_at"`
+	PublishedAt      *time.Time       `json:"published_at"`
+	ExpiresAt        *time.Time       `gorm:"index" json:"expires_at"`
+	ExpiryNotifiedAt *time.Time       `json:"-"`
+	ClosedAt         *time.Time       `json:"closed_at"`
+	JobApplications  []JobApplication `gorm:"foreignKey:JobID"`
+	UserID           uuid.UUID        `gorm:"type:uuid;not null"` // Removed uniqueIndex
+	User             User             `gorm:"foreignKey:UserID"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt   `json:"deleted_at,omitempty"`
 }
 
 type Status string
 
 const (
+	Applied   Status = "applied"
+	Screening Status = "screening"
+	Interview Status = "interview"
+	Offer     Status = "offer"
+	Hired     Status = "hired"
+	Rejected  Status = "rejected"
+	Withdrawn Status = "withdrawn"
+
+	// legacy statuses kept so rows created before the pipeline still parse
 	Pending Status = "pending"
 	Success Status = "success"
 	Failed  Status = "failed"
 )
 
+// transitions lists the statuses an application can move to from a given status,
+// anything not listed here is a final state. Legacy statuses are looked up by the status
+// they stand for
+var transitions = map[Status][]Status{
+	Applied:   {Screening, Interview, Rejected, Withdrawn},
+	Screening: {Interview, Rejected, Withdrawn},
+	Interview: {Offer, Rejected, Withdrawn},
+	Offer:     {Hired, Rejected, Withdrawn},
+}
+
 func ParseStatus(str string) (Status, error) {
 	switch str {
-	case "pending":
-		return Pending, nil
-	case "success":
-		return Success, nil
-	case "failed":
-		return Failed, nil
+	case "applied", "pending":
+		return Applied, nil
+	case "screening":
+		return Screening, nil
+	case "interview":
+		return Interview, nil
+	case "offer":
+		return Offer, nil
+	case "hired", "success":
+		return Hired, nil
+	case "rejected", "failed":
+		return Rejected, nil
+	case "withdrawn":
+		return Withdrawn, nil
 	default:
 		return "", fmt.Errorf("unsupported status: %s", str)
 	}
 }
 
-func CheckStatus(status Status) error {
-	switch status {
-	case Success:
-		return nil
-	case Failed:
-		return nil
-	default:
-		return fmt.Errorf("unsupported status for update, only %s or %s is allowed", Success, Failed)
+// Current is the pipeline status a stored status stands for, rows written before the
+// pipeline keep their legacy status until they are moved
+func (s Status) Current() Status {
+	if current, err := ParseStatus(string(s)); err == nil {
+		return current
 	}
+	return s
 }
 
-/*
-you need to run this cript to ensure a user can only apply once to a job
+// IsFinal reports whether no further transitions are allowed from the status
+func (s Status) IsFinal() bool {
+	_, ok := transitions[s.Current()]
+	return !ok
+}
+
+// CheckTransition makes sure an application can move from one status to the next
+func CheckTransition(from Status, to Status) error {
+	from = from.Current()
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	if from.IsFinal() {
+		return fmt.Errorf("application is already %s and can no longer be moved", from)
+	}
+	return fmt.Errorf("unsupported status for update, cannot move from %s to %s", from, to)
+}
 
-ALTER TABLE job_applications
-ADD CONSTRAINT unique_applicant_job UNIQUE (applicant_id, job_id);
-*/
+// a user can only apply once to a job, migration 0004 adds the unique_applicant_job constraint
 type JobApplication struct {
 	gorm.Model
 	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
@@ -152,7 +210,9 @@ type JobApplication struct {
 	Job         Job            `gorm:"foreignKey:JobID"`
 	ApplicantID uuid.UUID      `gorm:"type:uuid;not null;index:idx_job_applications"`
 	Applicant   User           `gorm:"foreignKey:ApplicantID"`
-	Status      Status         `gorm:"type:varchar(50);default:'pending'"`
+	Status      Status         `gorm:"type:varchar(50);default:'applied'"`
+	StageID     *uuid.UUID     `gorm:"type:uuid"`
+	Stage       *PipelineStage `gorm:"foreignKey:StageID"`
 	AppliedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
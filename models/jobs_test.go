@@ -0,0 +1,102 @@
+package models
+
+import "testing"
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		ok       bool
+	}{
+		{Applied, Screening, true},
+		{Applied, Interview, true},
+		{Applied, Rejected, true},
+		{Applied, Withdrawn, true},
+		{Applied, Offer, false},
+		{Applied, Hired, false},
+		{Pending, Screening, true},
+		{Screening, Interview, true},
+		{Screening, Applied, false},
+		{Interview, Offer, true},
+		{Interview, Screening, false},
+		{Offer, Hired, true},
+		{Offer, Interview, false},
+
+		// moving between custom stages of one status is decided by the caller, the
+		// status itself never transitions to itself
+		{Applied, Applied, false},
+		{Interview, Interview, false},
+		{Offer, Offer, false},
+
+		// final statuses can't move at all
+		{Hired, Rejected, false},
+		{Rejected, Applied, false},
+		{Withdrawn, Screening, false},
+		{Withdrawn, Withdrawn, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"_to_"+string(tt.to), func(t *testing.T) {
+			err := CheckTransition(tt.from, tt.to)
+			if tt.ok && err != nil {
+				t.Errorf("CheckTransition(%s, %s) = %v, want nil", tt.from, tt.to, err)
+			}
+			if !tt.ok && err == nil {
+				t.Errorf("CheckTransition(%s, %s) allowed the move", tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestStatusIsFinal(t *testing.T) {
+	for _, status := range []Status{Hired, Rejected, Withdrawn, Success, Failed} {
+		if !status.IsFinal() {
+			t.Errorf("%s isn't final", status)
+		}
+	}
+	for _, status := range []Status{Applied, Pending, Screening, Interview, Offer} {
+		if status.IsFinal() {
+			t.Errorf("%s is final", status)
+		}
+	}
+}
+
+func TestParseStatusMapsLegacyNames(t *testing.T) {
+	tests := map[string]Status{
+		"pending": Applied,
+		"success": Hired,
+		"failed":  Rejected,
+		"offer":   Offer,
+	}
+	for in, want := range tests {
+		got, err := ParseStatus(in)
+		if err != nil || got != want {
+			t.Errorf("ParseStatus(%q) = %s, %v, want %s", in, got, err, want)
+		}
+	}
+	if _, err := ParseStatus("archived"); err == nil {
+		t.Error("ParseStatus accepted an unknown status")
+	}
+}
+
+func TestLegacyStatusesMoveLikeTheirCurrentOnes(t *testing.T) {
+	tests := []struct {
+		legacy, current Status
+	}{
+		{Pending, Applied},
+		{Success, Hired},
+		{Failed, Rejected},
+		{Interview, Interview},
+	}
+	for _, tt := range tests {
+		if got := tt.legacy.Current(); got != tt.current {
+			t.Errorf("%s.Current() = %s, want %s", tt.legacy, got, tt.current)
+		}
+		if tt.legacy.IsFinal() != tt.current.IsFinal() {
+			t.Errorf("%s and %s disagree on being final", tt.legacy, tt.current)
+		}
+		for _, to := range []Status{Screening, Interview, Offer, Hired, Rejected, Withdrawn} {
+			if (CheckTransition(tt.legacy, to) == nil) != (CheckTransition(tt.current, to) == nil) {
+				t.Errorf("%s to %s is decided differently from %s to %s", tt.legacy, to, tt.current, to)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JobState string
+
+const (
+	Draft     JobState = "draft"
+	Scheduled JobState = "scheduled"
+	Published JobState = "published"
+	Paused    JobState = "paused"
+	Closed    JobState = "closed"
+	Expired   JobState = "expired"
+)
+
+// DefaultJobExpiryDays is how long a job stays published when JOB_EXPIRY_DAYS isn't set
+const DefaultJobExpiryDays = 30
+
+// LiveJobs is the condition for jobs candidates can see and apply to
+const LiveJobs = "jobs.state = 'published' AND (jobs.expires_at IS NULL OR jobs.expires_at > NOW())"
+
+// jobStateTransitions lists the states a poster can move a job to from each state,
+// expiry is only ever set by the scheduler
+var jobStateTransitions = map[JobState][]JobState{
+	Draft:     {Scheduled, Published, Closed},
+	Scheduled: {Draft, Published, Closed},
+	Published: {Paused, Closed},
+	Paused:    {Published, Closed},
+	Expired:   {Published, Closed},
+	Closed:    {},
+}
+
+func ParseJobState(str string) (JobState, error) {
+	state := JobState(str)
+	if _, ok := jobStateTransitions[state]; !ok {
+		return "", fmt.Errorf("unsupported job state: %s", str)
+	}
+	return state, nil
+}
+
+func CheckJobTransition(from, to JobState) error {
+	for _, allowed := range jobStateTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("a %s job can't be moved to %s", from, to)
+}
+
+// IsLive reports whether candidates can see and apply to the job
+func (j Job) IsLive() bool {
+	return j.State == Published && (j.ExpiresAt == nil || j.ExpiresAt.After(time.Now()))
+}
+
+// jobExpiry is how long a job stays published before it expires, Setup sets it from JOB_EXPIRY_DAYS
+var jobExpiry = DefaultJobExpiryDays * 24 * time.Hour
+
+// JobExpiry is how long a job stays published before it expires
+func JobExpiry() time.Duration {
+	return jobExpiry
+}
+
+// Publish marks the job as published now and starts its expiry clock unless it already has one ahead
+func (j *Job) Publish(now time.Time) {
+	j.State = Published
+	j.PublishedAt = &now
+	if j.ExpiresAt == nil || !j.ExpiresAt.After(now) {
+		expiresAt := now.Add(JobExpiry())
+		j.ExpiresAt = &expiresAt
+	}
+	j.ExpiryNotifiedAt = nil
+}
+
+// migrateJobLifecycle dates the jobs posted before the lifecycle, they came in published
+// without an expiry so the scheduler would never expire them. Their expiry clock starts
+// from when they were posted
+func migrateJobLifecycle(tx *gorm.DB) error {
+	return tx.Model(&Job{}).
+		Where("state = ? AND expires_at IS NULL", Published).
+		UpdateColumns(map[string]interface{}{
+			"published_at": gorm.Expr("COALESCE(published_at, created_at)"),
+			"expires_at":   gorm.Expr("COALESCE(published_at, created_at) + make_interval(secs => ?)", JobExpiry().Seconds()),
+		}).Error
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MatchFactor is one line of the explanation behind a match score
+type MatchFactor struct {
+	Name    string   `json:"name"`
+	Weight  float64  `json:"weight"`
+	Score   float64  `json:"score"`
+	Reason  string   `json:"reason"`
+	Matched []string `json:"matched,omitempty"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+type MatchBreakdown []MatchFactor
+
+func (b MatchBreakdown) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+func (b *MatchBreakdown) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	case nil:
+		*b = nil
+		return nil
+	default:
+		return errors.New("unsupported type for match breakdown")
+	}
+}
+
+// MatchScore caches how well a profile fits a job, it is recomputed whenever either side changes
+type MatchScore struct {
+	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ProfileID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_match_profile_job" json:"profile_id"`
+	Profile   *Profile       `gorm:"foreignKey:ProfileID" json:"profile,omitempty"`
+	JobID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_match_profile_job;index" json:"job_id"`
+	Job       *Job           `gorm:"foreignKey:JobID" json:"job,omitempty"`
+	Score     float64        `gorm:"type:decimal(5,2);not null;default:0;index" json:"score"`
+	Breakdown MatchBreakdown `gorm:"type:jsonb" json:"breakdown"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
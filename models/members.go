@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CompanyRole is what a member may do inside one company, on top of their account permissions
+type CompanyRole string
+
+const (
+	// owners manage the company and its team, every company has exactly one
+	CompanyOwner CompanyRole = "owner"
+	// recruiters post jobs and move applications through the pipeline
+	CompanyRecruiter CompanyRole = "recruiter"
+	// viewers read jobs, applications and candidates
+	CompanyViewer CompanyRole = "viewer"
+)
+
+// Hiring are the company roles allowed to change jobs and applications
+var Hiring = []CompanyRole{CompanyOwner, CompanyRecruiter}
+
+func ParseCompanyRole(role string) (CompanyRole, error) {
+	switch CompanyRole(role) {
+	case CompanyOwner, CompanyRecruiter, CompanyViewer:
+		return CompanyRole(role), nil
+	}
+	return "", fmt.Errorf("invalid company role %s, expected one of owner, recruiter or viewer", role)
+}
+
+type CompanyMember struct {
+	ID        uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	CompanyID uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_company_member" json:"company_id"`
+	Company   *Company    `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	UserID    uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_company_member;index" json:"user_id"`
+	User      *User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Role      CompanyRole `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// CompanyInvitation is an emailed offer to join a company, the token is only kept hashed
+type CompanyInvitation struct {
+	ID          uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	CompanyID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"company_id"`
+	Company     *Company    `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	Email       string      `gorm:"type:varchar(255);not null" json:"email"`
+	Role        CompanyRole `gorm:"type:varchar(20);not null" json:"role"`
+	TokenHash   string      `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	InvitedByID uuid.UUID   `gorm:"type:uuid;not null" json:"invited_by_id"`
+	ExpiresAt   time.Time   `json:"expires_at"`
+	AcceptedAt  *time.Time  `json:"accepted_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// IsCompanyMember reports whether the user belongs to the company with one of the roles,
+// no roles accepts any member
+func IsCompanyMember(db *gorm.DB, companyID uuid.UUID, userID uuid.UUID, roles ...CompanyRole) bool {
+	query := db.Model(&CompanyMember{}).Where("company_id = ? AND user_id = ?", companyID, userID)
+	if len(roles) > 0 {
+		query = query.Where("role IN ?", roles)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false
+	}
+	return count > 0
+}
+
+// HasAppliedToCompanyOf reports whether the applicant applied to a job of a company the
+// member belongs to
+func HasAppliedToCompanyOf(db *gorm.DB, applicantID uuid.UUID, memberID uuid.UUID) bool {
+	var count int64
+	if err := db.Model(&JobApplication{}).
+		Joins("JOIN jobs ON jobs.id = job_applications.job_id").
+		Where("job_applications.applicant_id = ? AND jobs.company_id IN (?)", applicantID, MemberCompanies(db, memberID)).
+		Count(&count).Error; err != nil {
+		return false
+	}
+	return count > 0
+}
+
+// MemberCompanies selects the ids of the companies the user belongs to, for use as a subquery
+func MemberCompanies(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Model(&CompanyMember{}).Select("company_id").Where("user_id = ?", userID)
+}
+
+// migrateCompanyMembers makes every company's creator its owner
+func migrateCompanyMembers(db *gorm.DB) error {
+	return db.Exec(`INSERT INTO company_members (company_id, user_id, role, created_at, updated_at)
+		SELECT id, user_id, ?, NOW(), NOW() FROM companies WHERE deleted_at IS NULL
+		ON CONFLICT (company_id, user_id) DO NOTHING`, CompanyOwner).Error
+}
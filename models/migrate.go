@@ -1,66 +1,74 @@
 package models
 
 import (
-	// "log"
-	"job_board/db"
+	"fmt"
+	"time"
+
+	"job_board/config"
 
 	"gorm.io/gorm"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle and how long jobs stay published
+func Setup(db *gorm.DB, cfg config.Jobs) {
+	database = db
+	if cfg.ExpiryDays > 0 {
+		jobExpiry = time.Duration(cfg.ExpiryDays) * 24 * time.Hour
+	}
 }
 
-func MigrateDb() {
-
-	//remeber to run this for uuid support CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-
-	//this is for index
-	// if err := database.Exec("CREATE INDEX idx_user_email ON users (email) WHERE email IS NOT NULL").Error; err != nil {
-	// 	log.Printf("Error creating index on users table: %v", err)
-	// 	return
-	// }
-
-	// drop the index
-	// if err := database.Exec("DROP INDEX idx_user_email;").Error; err != nil {
-	// 	log.Printf("Error dropping index on users table: %v", err)
-	// 	return
-	// }
+// UpgradeLegacySchema brings a database created by AutoMigrate, before the schema was
+// versioned, up to the models once. The versioned migrations in job_board/migrations
+// take it from there, new databases never run this
+func UpgradeLegacySchema(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(
+		&Job{},
+		&JobApplication{},
+		&PipelineStage{},
+		&ApplicationStatusHistory{},
+		&SavedSearch{},
+		&SavedSearchMatch{},
+		&MatchScore{},
+		&File{},
+		&RolePermission{},
+		&CompanyMember{},
+		&CompanyInvitation{},
+		&CompanyVerification{},
+		&AuditLog{},
+		&Profile{},
+		&SalaryCurrency{},
+		&ExchangeRate{},
+		&WorkSample{},
+	); err != nil {
+		return err
+	}
 
-	// result := database.Unscoped().Where("role_name = ?", SuperAdminRole).Delete(&User{})
-	// // Check for errors during delete operation
-	// if err := result.Error; err != nil {
-	// 	log.Printf("Error deleting admin: %v", err)
-	// 	return
-	// }
-	database.AutoMigrate(
-	// &User{},
-
-	// &Company{},
-	// &Industry{},
-	// &EmployeesSize{},
-	// &JobType{},
-	// &Level{},
-	// &Job{},
-	// &JobApplication{},
-
-	// &Profile{},
-	// &SalaryCurrency{},
-	// &Gender{},
-	// &Degree{},
-	// &AcademicRanking{},
-	// &Education{},
-	// &InternShipExperience{},
-	// &ProjectsExperience{},
-	// &WorkSample{},
-	// &Award{},
-	// &Language{},
-	// &LanguageProficiency{},
-	// &ProfileLanguage{},
-	// &SocialMedia{},
-	// &SocialMediaAccount{},
-	)
+	steps := []struct {
+		name string
+		run  func(*gorm.DB) error
+	}{
+		{"company verification", migrateCompanyVerification},
+		{"job salaries", migrateSalaries},
+		{"job search", migrateJobSearch},
+		{"file references", migrateFileReferences},
+		{"company members", migrateCompanyMembers},
+		{"permission names", renamePermissions},
+		{"job lifecycle", migrateJobLifecycle},
+	}
+	for _, step := range steps {
+		if err := step.run(tx); err != nil {
+			return fmt.Errorf("error migrating %s: %w", step.name, err)
+		}
+	}
+	return nil
+}
 
+// Seed fills in the rows the api can't run without, it is safe to call on every start
+func Seed(db *gorm.DB) error {
+	if err := seedRolePermissions(db); err != nil {
+		return fmt.Errorf("error seeding role permissions: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxKind string
+
+const (
+	// registers the recipient with the provider
+	OutboxSubscriber OutboxKind = "subscriber"
+	// sends a notification
+	OutboxTrigger OutboxKind = "trigger"
+	// removes the recipient from the provider
+	OutboxForget OutboxKind = "forget"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	// gave up after too many attempts, kept with the last error for a look by hand, the
+	// payload is cleared like that of sent messages
+	OutboxDead OutboxStatus = "dead"
+)
+
+// OutboxMessage is a notification waiting to be delivered. It is written in the same
+// transaction as the change it is about, so nothing is sent for a change that rolled back
+// and nothing is lost when the provider is down
+type OutboxMessage struct {
+	ID      uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Kind    OutboxKind   `gorm:"type:varchar(20);not null" json:"kind"`
+	EventID string       `gorm:"type:varchar(100);not null" json:"event_id"`
+	Payload string       `gorm:"type:jsonb;not null" json:"-"`
+	Status  OutboxStatus `gorm:"type:varchar(20);not null;default:'pending';index:idx_outbox_due" json:"status"`
+	// Provider is the notifier that delivered it
+	Provider      string     `gorm:"type:varchar(20)" json:"provider,omitempty"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_due" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Permission names an action as resource:action[:scope], the scope says whose records
+// it covers, own for the user's records, company for records of jobs and companies
+// they run and any for everybody's
+type Permission string
+
+const (
+	UserReadAny   Permission = "user:read:any"
+	UserCreate    Permission = "user:create"
+	UserReinstate Permission = "user:reinstate"
+
+	ProfileCreate    Permission = "profile:create"
+	ProfileReadOwn   Permission = "profile:read:own"
+	ProfileReadAny   Permission = "profile:read:any"
+	ProfileUpdateOwn Permission = "profile:update:own"
+	ProfileUpdateAny Permission = "profile:update:any"
+	ProfileDeleteOwn Permission = "profile:delete:own"
+	ProfileDeleteAny Permission = "profile:delete:any"
+	ResumeImport     Permission = "resume:import"
+
+	JobCreate        Permission = "job:create"
+	JobReadCompany   Permission = "job:read:company"
+	JobReadAny       Permission = "job:read:any"
+	JobUpdateCompany Permission = "job:update:company"
+	JobUpdateAny     Permission = "job:update:any"
+	JobDeleteCompany Permission = "job:delete:company"
+	JobDeleteAny     Permission = "job:delete:any"
+
+	ApplicationCreate        Permission = "application:create"
+	ApplicationReadOwn       Permission = "application:read:own"
+	ApplicationReadCompany   Permission = "application:read:company"
+	ApplicationReadAny       Permission = "application:read:any"
+	ApplicationWithdrawOwn   Permission = "application:withdraw:own"
+	ApplicationUpdateCompany Permission = "application:update:company"
+	ApplicationUpdateAny     Permission = "application:update:any"
+	ApplicationDeleteAny     Permission = "application:delete:any"
+
+	PipelineManageCompany Permission = "pipeline:manage:company"
+	PipelineManageAny     Permission = "pipeline:manage:any"
+
+	CompanyRead          Permission = "company:read"
+	CompanyCreate        Permission = "company:create"
+	CompanyUpdateCompany Permission = "company:update:company"
+	CompanyUpdateAny     Permission = "company:update:any"
+	CompanyDeleteCompany Permission = "company:delete:company"
+	CompanyDeleteAny     Permission = "company:delete:any"
+
+	MatchReadOwn         Permission = "match:read:own"
+	CandidateReadCompany Permission = "candidate:read:company"
+	CandidateReadAny     Permission = "candidate:read:any"
+
+	AlertManageOwn Permission = "alert:manage:own"
+
+	FileCreate      Permission = "file:create"
+	FileReadOwn     Permission = "file:read:own"
+	FileReadCompany Permission = "file:read:company"
+	FileReadAny     Permission = "file:read:any"
+	FileDeleteOwn   Permission = "file:delete:own"
+	FileDeleteAny   Permission = "file:delete:any"
+
+	// genders, degrees, countries, currencies and the other admin managed lists
+	LookupManage     Permission = "lookup:manage"
+	TwoFactorManage  Permission = "auth:two-factor"
+	PermissionManage Permission = "permission:manage"
+	AuditRead        Permission = "audit:read"
+)
+
+// Permissions lists every permission the api checks
+var Permissions = []Permission{
+	UserReadAny, UserCreate, UserReinstate,
+	ProfileCreate, ProfileReadOwn, ProfileReadAny, ProfileUpdateOwn, ProfileUpdateAny, ProfileDeleteOwn, ProfileDeleteAny, ResumeImport,
+	JobCreate, JobReadCompany, JobReadAny, JobUpdateCompany, JobUpdateAny, JobDeleteCompany, JobDeleteAny,
+	ApplicationCreate, ApplicationReadOwn, ApplicationReadCompany, ApplicationReadAny,
+	ApplicationWithdrawOwn, ApplicationUpdateCompany, ApplicationUpdateAny, ApplicationDeleteAny,
+	PipelineManageCompany, PipelineManageAny,
+	CompanyRead, CompanyCreate, CompanyUpdateCompany, CompanyUpdateAny, CompanyDeleteCompany, CompanyDeleteAny,
+	MatchReadOwn, CandidateReadCompany, CandidateReadAny,
+	AlertManageOwn,
+	FileCreate, FileReadOwn, FileReadCompany, FileReadAny, FileDeleteOwn, FileDeleteAny,
+	LookupManage, TwoFactorManage, PermissionManage, AuditRead,
+}
+
+// Roles lists the roles whose permissions are stored, super admins hold every permission
+var Roles = []RoleAllowed{AdminRole, PosterRole, UserRole}
+
+func IsPermission(permission Permission) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func IsRole(role RoleAllowed) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RolePermission grants a permission to every user with the role
+type RolePermission struct {
+	ID         uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RoleName   RoleAllowed `gorm:"not null;uniqueIndex:idx_role_permission" json:"role"`
+	Permission Permission  `gorm:"not null;uniqueIndex:idx_role_permission" json:"permission"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// DefaultRolePermissions is what each role was allowed before permissions were editable
+var DefaultRolePermissions = map[RoleAllowed][]Permission{
+	AdminRole: {
+		UserReadAny, UserCreate, UserReinstate,
+		ProfileCreate, ProfileReadOwn, ProfileReadAny, ProfileUpdateOwn, ProfileUpdateAny, ProfileDeleteOwn, ProfileDeleteAny,
+		JobCreate, JobReadAny, JobUpdateAny, JobDeleteAny,
+		ApplicationReadAny, ApplicationUpdateAny, ApplicationDeleteAny,
+		PipelineManageAny,
+		CompanyRead, CompanyCreate, CompanyUpdateAny, CompanyDeleteAny,
+		CandidateReadAny,
+		FileCreate, FileReadAny, FileDeleteAny,
+		LookupManage, TwoFactorManage, AuditRead,
+	},
+	PosterRole: {
+		JobCreate, JobReadCompany, JobUpdateCompany, JobDeleteCompany,
+		ApplicationReadCompany, ApplicationUpdateCompany,
+		PipelineManageCompany,
+		CompanyRead, CompanyCreate, CompanyUpdateCompany, CompanyDeleteCompany,
+		CandidateReadCompany,
+		FileCreate, FileReadOwn, FileReadCompany, FileDeleteOwn,
+	},
+	UserRole: {
+		ProfileCreate, ProfileReadOwn, ProfileUpdateOwn, ProfileDeleteOwn, ResumeImport,
+		ApplicationCreate, ApplicationReadOwn, ApplicationWithdrawOwn,
+		MatchReadOwn, AlertManageOwn,
+		FileCreate, FileReadOwn, FileDeleteOwn,
+	},
+}
+
+// renamedPermissions maps permissions that changed name to their new one, jobs and
+// companies moved from the creator to the whole company team
+var renamedPermissions = map[Permission]Permission{
+	"job:read:own":       JobReadCompany,
+	"job:update:own":     JobUpdateCompany,
+	"job:delete:own":     JobDeleteCompany,
+	"company:update:own": CompanyUpdateCompany,
+	"company:delete:own": CompanyDeleteCompany,
+}
+
+func renamePermissions(db *gorm.DB) error {
+	for from, to := range renamedPermissions {
+		if err := db.Model(&RolePermission{}).Where("permission = ?", from).Update("permission", to).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedRolePermissions gives a role its defaults the first time it's seen, once a role has
+// rows the super admin owns them and removed permissions stay removed. A default added later
+// reaches roles seeded before it through a migration that grants it once
+func seedRolePermissions(db *gorm.DB) error {
+	for role, permissions := range DefaultRolePermissions {
+		var count int64
+		if err := db.Model(&RolePermission{}).Where("role_name = ?", role).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		rows := make([]RolePermission, 0, len(permissions))
+		for _, permission := range permissions {
+			rows = append(rows, RolePermission{RoleName: role, Permission: permission})
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PipelineStage is a company defined step inside one of the base statuses,
+// e.g "Technical interview" and "Culture fit" both belong to the interview status
+type PipelineStage struct {
+	gorm.Model
+	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	CompanyID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_company_stage_name" json:"company_id"`
+	Company   Company        `gorm:"foreignKey:CompanyID" json:"-"`
+	Name      string         `gorm:"type:varchar(100);not null;uniqueIndex:idx_company_stage_name" json:"name"`
+	Status    Status         `gorm:"type:varchar(50);not null" json:"status"`
+	Position  int            `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty"`
+}
+
+// ApplicationStatusHistory records every move of an application through the pipeline
+type ApplicationStatusHistory struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	JobApplicationID uuid.UUID  `gorm:"type:uuid;not null;index" json:"job_application_id"`
+	FromStatus       Status     `gorm:"type:varchar(50);not null" json:"from_status"`
+	ToStatus         Status     `gorm:"type:varchar(50);not null" json:"to_status"`
+	FromStageID      *uuid.UUID `gorm:"type:uuid" json:"from_stage_id"`
+	ToStageID        *uuid.UUID `gorm:"type:uuid" json:"to_stage_id"`
+	ChangedByID      uuid.UUID  `gorm:"type:uuid;not null" json:"changed_by_id"`
+	ChangedBy        User       `gorm:"foreignKey:ChangedByID" json:"changed_by"`
+	Note             string     `gorm:"type:text" json:"note"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
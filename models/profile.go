@@ -1,9 +1,26 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
-	"time"
+)
+
+// ProfileVisibility says who besides the owner can see a profile
+type ProfileVisibility string
+
+const (
+	// ProfilePrivate profiles are only seen by their owner and admins
+	ProfilePrivate ProfileVisibility = "private"
+	// ProfileAppliedEmployers profiles are also seen by the teams of companies the owner applied to
+	ProfileAppliedEmployers ProfileVisibility = "applied_employers"
+	// ProfilePublic profiles are seen by anybody with the link
+	ProfilePublic ProfileVisibility = "public"
 )
 
 type Profile struct {
@@ -12,7 +29,9 @@ type Profile struct {
 	UserID                   uuid.UUID              `gorm:"type:uuid;uniqueIndex"` // Unique index enforces one wallet per user
 	User                     User                   `gorm:"foreignKey:UserID"`
 	Bio                      string                 `gorm:"type:text;not null"`
-	Resume                   string                 `gorm:"type:varchar(512);not null"`
+	Skills                   pq.StringArray         `gorm:"type:text[]" json:"skills"`
+	ResumeID                 *uuid.UUID             `gorm:"type:uuid" json:"resume_id"`
+	Resume                   *File                  `gorm:"foreignKey:ResumeID" json:"resume,omitempty"`
 	Educations               []Education            `gorm:"foreignKey:ProfileID"`
 	InternShipExperiences    []InternShipExperience `gorm:"foreignKey:ProfileID"`
 	ProjectsExperiences      []ProjectsExperience   `gorm:"foreignKey:ProfileID"`
@@ -28,15 +47,49 @@ type Profile struct {
 	ExpectedSalary           float64                `gorm:"type:decimal(10,2);default:0.0"`
 	ExpectedSalaryCurrencyID *uuid.UUID             `gorm:"type:uuid"`
 	ExpectedSalaryCurrency   SalaryCurrency         `gorm:"foreignKey:ExpectedSalaryCurrencyID"`
+	Completeness             *int                   `gorm:"type:smallint;index" json:"-"`             // stored for filtering, null until first scored
+	Slug                     string                 `gorm:"type:varchar(32);uniqueIndex" json:"slug"` // set once, shared links keep working
+	Visibility               ProfileVisibility      `gorm:"type:varchar(20);not null;default:'applied_employers'" json:"visibility"`
+	HideSalary               bool                   `gorm:"not null;default:false" json:"hide_salary"`
+	HideContact              bool                   `gorm:"not null;default:false" json:"hide_contact"`
 	CreatedAt                time.Time              `json:"created_at"`
 	UpdatedAt                time.Time              `json:"updated_at"`
 	DeletedAt                gorm.DeletedAt         `json:"deleted_at,omitempty"`
 }
 
+var slugEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newProfileSlug makes a random slug, it says nothing about the owner so a shared link
+// doesn't give away who it belongs to until it is opened
+func newProfileSlug() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(slugEncoding.EncodeToString(b)), nil
+}
+
+func (p *Profile) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.Slug == "" {
+		p.Slug, err = newProfileSlug()
+	}
+	if p.Visibility == "" {
+		p.Visibility = ProfileAppliedEmployers
+	}
+	return err
+}
+
+// ProfileReminder counts the reminders a new user got to fill in their profile
+type ProfileReminder struct {
+	UserID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Sent       int       `gorm:"not null;default:0" json:"sent"`
+	LastSentAt time.Time `json:"last_sent_at"`
+}
+
 type SalaryCurrency struct {
 	gorm.Model
 	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name      string         `gorm:"type:varchar(250);not null; uniqueIndex"json:"name"`
+	Name      string         `gorm:"type:varchar(250);not null; uniqueIndex" json:"name"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty"`
@@ -120,14 +173,15 @@ type ProjectsExperience struct {
 
 type WorkSample struct {
 	gorm.Model
-	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	ProfileID   uuid.UUID      `gorm:"type:uuid;not null"`
-	Attachment  string         `gorm:"type:varchar(512);not null" `
-	Link        string         `gorm:"type:varchar(512);not null" `
-	Description string         `gorm:"type:text;not null"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty"`
+	ID           uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ProfileID    uuid.UUID      `gorm:"type:uuid;not null"`
+	AttachmentID *uuid.UUID     `gorm:"type:uuid" json:"attachment_id"`
+	Attachment   *File          `gorm:"foreignKey:AttachmentID" json:"attachment,omitempty"`
+	Link         string         `gorm:"type:varchar(512);not null" `
+	Description  string         `gorm:"type:text;not null"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"deleted_at,omitempty"`
 }
 
 type Award struct {
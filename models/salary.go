@@ -0,0 +1,121 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PayPeriod string
+
+const (
+	Hourly  PayPeriod = "hourly"
+	Monthly PayPeriod = "monthly"
+	Yearly  PayPeriod = "yearly"
+)
+
+// hoursPerYear is a 40 hour week over 52 weeks
+const hoursPerYear = 2080
+
+func ParsePayPeriod(str string) (PayPeriod, error) {
+	switch str {
+	case "", "yearly":
+		return Yearly, nil
+	case "monthly":
+		return Monthly, nil
+	case "hourly":
+		return Hourly, nil
+	default:
+		return "", fmt.Errorf("unsupported pay period: %s, use hourly, monthly or yearly", str)
+	}
+}
+
+// PerYear is how many pay periods fit in a year
+func (p PayPeriod) PerYear() float64 {
+	switch p {
+	case Hourly:
+		return hoursPerYear
+	case Monthly:
+		return 12
+	default:
+		return 1
+	}
+}
+
+// ExchangeRate is what one unit of a currency is worth in the base currency every salary is
+// normalised to, the base currency itself has a rate of 1
+type ExchangeRate struct {
+	ID         uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	CurrencyID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"currency_id"`
+	Currency   SalaryCurrency `gorm:"foreignKey:CurrencyID" json:"currency"`
+	Rate       float64        `gorm:"type:decimal(18,8);not null" json:"rate"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// AnnualBaseSQL is the sql expression turning an amount paid per period in a currency into a
+// yearly amount in the base currency. A missing currency is taken as the base currency, a
+// currency without a rate gives NULL
+func AnnualBaseSQL(amount, period, currency string) string {
+	return fmt.Sprintf("%s * (CASE %s WHEN 'hourly' THEN %d WHEN 'monthly' THEN 12 ELSE 1 END) * "+
+		"(CASE WHEN %s IS NULL THEN 1 ELSE (SELECT exchange_rates.rate FROM exchange_rates WHERE exchange_rates.currency_id = %s) END)",
+		amount, period, hoursPerYear, currency, currency)
+}
+
+// NormaliseSalary converts an amount paid per period in a currency to a yearly amount in the base currency
+func NormaliseSalary(tx *gorm.DB, amount float64, currencyID *uuid.UUID, period PayPeriod) (float64, error) {
+	rate := 1.0
+	if currencyID != nil && *currencyID != uuid.Nil {
+		var exchangeRate ExchangeRate
+		if err := tx.First(&exchangeRate, "currency_id = ?", *currencyID).Error; err != nil {
+			return 0, fmt.Errorf("no exchange rate for currency %s", *currencyID)
+		}
+		rate = exchangeRate.Rate
+	}
+	return amount * period.PerYear() * rate, nil
+}
+
+// RefreshJobSalaries recomputes the normalised salary range of the jobs matching the condition,
+// it has to run whenever a job salary or the exchange rate of its currency changes. Jobs paid
+// in a currency without a rate end up at 0, NormalisedSalaryKnown keeps them out of filters
+func RefreshJobSalaries(tx *gorm.DB, query string, args ...interface{}) error {
+	return tx.Model(&Job{}).
+		Where(query, args...).
+		UpdateColumns(map[string]interface{}{
+			"salary_min_base": gorm.Expr("COALESCE(" + AnnualBaseSQL("jobs.salary_min", "jobs.pay_period", "jobs.salary_currency_id") + ", 0)"),
+			"salary_max_base": gorm.Expr("COALESCE(" + AnnualBaseSQL("jobs.salary_max", "jobs.pay_period", "jobs.salary_currency_id") + ", 0)"),
+		}).Error
+}
+
+// NormalisedSalaryKnown is the condition for jobs whose normalised salary can be trusted
+const NormalisedSalaryKnown = "(jobs.salary_currency_id IS NULL OR jobs.salary_currency_id IN (SELECT exchange_rates.currency_id FROM exchange_rates))"
+
+// migrateSalaries moves the old single salary into the range and fills in the normalised columns
+func migrateSalaries(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn("jobs", "salary") {
+		if err := tx.Exec("UPDATE jobs SET salary_min = salary, salary_max = salary WHERE salary_min = 0 AND salary_max = 0 AND salary > 0").Error; err != nil {
+			return err
+		}
+	}
+	return RefreshJobSalaries(tx, "salary_max > 0 AND salary_max_base = 0")
+}
+
+// MaskSalary clears the salary of a job posted with a hidden salary, only the company's
+// team and admins get to see it
+func (j *Job) MaskSalary(viewer *User) {
+	if !j.SalaryHidden {
+		return
+	}
+	if viewer != nil && (viewer.RoleName == AdminRole || viewer.RoleName == SuperAdminRole) {
+		return
+	}
+	if viewer != nil && (viewer.ID == j.UserID || IsCompanyMember(database, j.CompanyID, viewer.ID)) {
+		return
+	}
+	j.SalaryMin = 0
+	j.SalaryMax = 0
+	j.SalaryCurrencyID = nil
+	j.SalaryCurrency = nil
+}
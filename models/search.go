@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type SearchMode string
+
+const (
+	PlainSearch  SearchMode = "plain"
+	PhraseSearch SearchMode = "phrase"
+	PrefixSearch SearchMode = "prefix"
+)
+
+// searchConfig is the text search configuration used for both indexing and querying jobs
+const searchConfig = "english"
+
+// jobSearchDocument builds the weighted tsvector for a job, title ranks highest,
+// then skills and the company name, then the description
+const jobSearchDocument = `setweight(to_tsvector('english', coalesce(jobs.title, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(array_to_string(jobs.skills, ' '), '')), 'B') ||
+	setweight(to_tsvector('english', coalesce((SELECT companies.name FROM companies WHERE companies.id = jobs.company_id), '')), 'B') ||
+	setweight(to_tsvector('english', coalesce(jobs.description, '')), 'C')`
+
+var searchTermRegex = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+func ParseSearchMode(str string) (SearchMode, error) {
+	switch str {
+	case "", "plain":
+		return PlainSearch, nil
+	case "phrase":
+		return PhraseSearch, nil
+	case "prefix":
+		return PrefixSearch, nil
+	default:
+		return "", fmt.Errorf("unsupported search mode: %s", str)
+	}
+}
+
+// TsQuery returns the sql expression and its argument for the search term in the given mode
+func TsQuery(term string, mode SearchMode) (string, string) {
+	switch mode {
+	case PhraseSearch:
+		return "phraseto_tsquery('" + searchConfig + "', ?)", term
+	case PrefixSearch:
+		// strip anything to_tsquery would treat as an operator and match every word as a prefix
+		words := strings.Fields(searchTermRegex.ReplaceAllString(term, " "))
+		for i, word := range words {
+			words[i] = word + ":*"
+		}
+		return "to_tsquery('" + searchConfig + "', ?)", strings.Join(words, " & ")
+	default:
+		return "websearch_to_tsquery('" + searchConfig + "', ?)", term
+	}
+}
+
+// RefreshJobSearch recomputes the search vector of the jobs matching the condition,
+// it has to run whenever a job or the name of its company changes
+func RefreshJobSearch(tx *gorm.DB, query string, args ...interface{}) error {
+	return tx.Model(&Job{}).
+		Where(query, args...).
+		UpdateColumn("search_vector", gorm.Expr(jobSearchDocument)).Error
+}
+
+// migrateJobSearch fills in the search vector of jobs posted before it existed
+func migrateJobSearch(tx *gorm.DB) error {
+	return RefreshJobSearch(tx, "search_vector IS NULL")
+}
@@ -3,10 +3,11 @@ package models
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	// "github.com/lib/pq"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 
 	"errors"
+	"strings"
 	"time"
 
 	"job_board/helpers"
@@ -21,6 +22,9 @@ const (
 	UserRole       RoleAllowed = "user"
 )
 
+// LocalProvider prefixes the provider id of users who registered with an email and password
+const LocalProvider = "local"
+
 // user struct
 type User struct {
 	gorm.Model
@@ -36,41 +40,39 @@ type User struct {
 	JobApplications   []JobApplication `gorm:"foreignKey:ApplicantID" json:"job_applications"`
 	Jobs              []Job            `gorm:"foreignKey:UserID" json:"jobs"`
 	Companies         []Company        `gorm:"foreignKey:UserID" json:"companies"`
-	VerificationToken string           `json:"verification_token"`
+	VerificationToken string           `json:"-"`
 	ExpiresAt         time.Time        `json:"expires_at"`
+	EmailVerifiedAt   *time.Time       `json:"email_verified_at"`
+	TOTPSecret        string           `gorm:"default:null" json:"-"`
+	TOTPEnabledAt     *time.Time       `json:"totp_enabled_at"`
+	RecoveryCodes     pq.StringArray   `gorm:"type:text[]" json:"-"`
 	Password          string           `gorm:"default:null" json:"-"`
 	CountryID         uuid.UUID        `gorm:"type:uuid;"`
 	Country           Country          `gorm:"foreignKey:CountryID"`
 	CreatedAt         time.Time        `json:"created_at"`
 	UpdatedAt         time.Time        `json:"updated_at"`
 	DeletedAt         gorm.DeletedAt   `json:"deleted_at,omitempty"`
+	// ErasedAt is when the personal data was removed, the row stays for the records that
+	// point at it
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
-	if u.RoleName == SuperAdminRole {
-		// Check if the password is not empty
-		if u.Password != "" {
-			// Hash Password
-			u.Password, err = helpers.HashPassword(u.Password, 14)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
-	if u.RoleName == AdminRole {
-		// Check if the password is not empty
-		if u.Password != "" {
-			// Hash Password
-			u.Password, err = helpers.HashPassword(u.Password, 10)
-			if err != nil {
-				return err
-			}
+	// every role hashes with the same cost, older hashes keep working since bcrypt stores theirs
+	if u.Password != "" {
+		u.Password, err = helpers.HashPassword(u.Password, helpers.PasswordCost)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// IsLocal reports whether the user signs in with a password kept by us instead of Auth0
+func (u *User) IsLocal() bool {
+	return strings.HasPrefix(u.ProviderID, LocalProvider+"|")
+}
+
 func GetUserFromContext(ctx *gin.Context) (*User, error) {
 	value, exists := ctx.Get("user")
 	if !exists {
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VerificationMethod string
+
+const (
+	// a code is emailed to an address on the company's website domain
+	VerifyByEmail VerificationMethod = "email"
+	// a TXT record is published on the company's website domain
+	VerifyByDNS VerificationMethod = "dns"
+	// an admin checks the company by hand
+	VerifyByReview VerificationMethod = "review"
+)
+
+type VerificationStatus string
+
+const (
+	VerificationPending  VerificationStatus = "pending"
+	VerificationApproved VerificationStatus = "approved"
+	VerificationRejected VerificationStatus = "rejected"
+)
+
+// CompanyVerification is one attempt at proving a company is who it says it is
+type CompanyVerification struct {
+	ID            uuid.UUID          `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	CompanyID     uuid.UUID          `gorm:"type:uuid;not null;index" json:"company_id"`
+	Company       *Company           `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	Method        VerificationMethod `gorm:"type:varchar(20);not null" json:"method"`
+	Status        VerificationStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Domain        string             `gorm:"type:varchar(255)" json:"domain,omitempty"`
+	Email         string             `gorm:"type:varchar(255)" json:"email,omitempty"`
+	Record        string             `gorm:"type:varchar(255)" json:"record,omitempty"` // TXT value to publish for dns
+	CodeHash      string             `gorm:"type:varchar(64)" json:"-"`
+	Attempts      int                `gorm:"not null;default:0" json:"-"`
+	Note          string             `gorm:"type:text" json:"note,omitempty"`
+	ReviewNote    string             `gorm:"type:text" json:"review_note,omitempty"`
+	RequestedByID uuid.UUID          `gorm:"type:uuid;not null" json:"requested_by_id"`
+	ReviewedByID  *uuid.UUID         `gorm:"type:uuid" json:"reviewed_by_id,omitempty"`
+	ExpiresAt     *time.Time         `json:"expires_at,omitempty"`
+	CompletedAt   *time.Time         `json:"completed_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// migrateCompanyVerification adds the badge columns to companies made before them
+func migrateCompanyVerification(tx *gorm.DB) error {
+	for _, column := range []string{"Verified", "VerifiedAt"} {
+		if tx.Migrator().HasColumn(&Company{}, column) {
+			continue
+		}
+		if err := tx.Migrator().AddColumn(&Company{}, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetCompanyVerified sets the badge on the company and copies it to its jobs, UpdateColumns
+// keeps the company's update hook out of it
+func SetCompanyVerified(tx *gorm.DB, companyID uuid.UUID, verified bool) error {
+	var verifiedAt *time.Time
+	if verified {
+		now := time.Now()
+		verifiedAt = &now
+	}
+	if err := tx.Model(&Company{}).Where("id = ?", companyID).UpdateColumns(map[string]interface{}{
+		"verified":    verified,
+		"verified_at": verifiedAt,
+	}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&Job{}).Where("company_id = ?", companyID).UpdateColumn("company_verified", verified).Error
+}
@@ -1,5 +1,19 @@
 package notifications
 
+import "context"
+
+// Notifier delivers notifications through one provider
+type Notifier interface {
+	// Name is kept on every delivered message
+	Name() string
+	// Identify registers or updates the recipient, providers without subscribers do nothing
+	Identify(ctx context.Context, subscriber Subscriber) error
+	Send(ctx context.Context, trigger Trigger) error
+	// Forget removes the recipient and what the provider keeps about them, it is part of
+	// erasing an account
+	Forget(ctx context.Context, subscriberID string) error
+}
+
 type Subscriber struct {
 	SubscriberID string                 `json:"subscriberId"`
 	Name         string                 `json:"name"`
@@ -0,0 +1,56 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// LogNotifier writes notifications out as json lines instead of sending them, it is meant
+// for development and for running without a provider
+type LogNotifier struct {
+	mu     sync.Mutex
+	logger *log.Logger
+}
+
+// NewLogNotifier writes to out, a nil out uses the standard logger
+func NewLogNotifier(out io.Writer) *LogNotifier {
+	if out == nil {
+		return &LogNotifier{logger: log.Default()}
+	}
+	return &LogNotifier{logger: log.New(out, "", 0)}
+}
+
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+func (n *LogNotifier) Identify(ctx context.Context, subscriber Subscriber) error {
+	return n.write("subscriber", subscriber)
+}
+
+func (n *LogNotifier) Send(ctx context.Context, trigger Trigger) error {
+	return n.write("trigger", trigger)
+}
+
+func (n *LogNotifier) Forget(ctx context.Context, subscriberID string) error {
+	return n.write("forget", map[string]string{"subscriberId": subscriberID})
+}
+
+func (n *LogNotifier) write(kind string, value interface{}) error {
+	line, err := json.Marshal(map[string]interface{}{
+		"time":    time.Now().UTC(),
+		"kind":    kind,
+		"message": value,
+	})
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.logger.Print(string(line))
+	return nil
+}
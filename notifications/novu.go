@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"context"
+
+	novu "github.com/novuhq/go-novu/lib"
+)
+
+// NovuNotifier hands notifications to novu, which renders and routes them per event
+type NovuNotifier struct {
+	client *novu.APIClient
+}
+
+func NewNovuNotifier(apiKey string) *NovuNotifier {
+	return &NovuNotifier{client: novu.NewAPIClient(apiKey, &novu.Config{})}
+}
+
+func (n *NovuNotifier) Name() string {
+	return "novu"
+}
+
+func (n *NovuNotifier) Identify(ctx context.Context, subscriber Subscriber) error {
+	_, err := n.client.SubscriberApi.Identify(ctx, subscriber.SubscriberID, novu.SubscriberPayload{
+		FirstName: subscriber.Name,
+		Email:     subscriber.Email,
+		Avatar:    subscriber.Avatar,
+		Data:      subscriber.Data,
+	})
+	return err
+}
+
+func (n *NovuNotifier) Send(ctx context.Context, trigger Trigger) error {
+	_, err := n.client.EventApi.Trigger(ctx, trigger.EventID, novu.ITriggerPayloadOptions{
+		To:      trigger.To,
+		Payload: trigger.Data, // dynamic data
+	})
+	return err
+}
+
+func (n *NovuNotifier) Forget(ctx context.Context, subscriberID string) error {
+	_, err := n.client.SubscriberApi.Delete(ctx, subscriberID)
+	return err
+}
+
+func (n *NovuNotifier) CreateTopic(ctx context.Context, topicKey string, topicName string) error {
+	return n.client.TopicsApi.Create(ctx, topicKey, topicName)
+}
+
+func (n *NovuNotifier) AddSubscribers(ctx context.Context, topicKey string, subscribers []string) error {
+	return n.client.TopicsApi.AddSubscribers(ctx, topicKey, subscribers)
+}
+
+func (n *NovuNotifier) RemoveSubscribers(ctx context.Context, topicKey string, subscribers []string) error {
+	return n.client.TopicsApi.RemoveSubscribers(ctx, topicKey, subscribers)
+}
+
+func (n *NovuNotifier) SendTopic(ctx context.Context, arg TriggerTopic) error {
+	to := map[string]interface{}{
+		"type":     "Topic",
+		"topicKey": arg.TopicKey,
+	}
+	payload := map[string]interface{}{
+		"name": arg.Title,
+		"organization": map[string]interface{}{
+			"logo": arg.Logo,
+		},
+	}
+	_, err := n.client.EventApi.Trigger(ctx, arg.EventID, novu.ITriggerPayloadOptions{
+		To:      to,
+		Payload: payload,
+	})
+	return err
+}
@@ -0,0 +1,193 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"job_board/models"
+)
+
+const (
+	// outboxInterval is how often the worker looks for due messages
+	outboxInterval = 3 * time.Second
+	outboxBatch    = 50
+	// claimTimeout is how long a claimed message is left alone, a worker that dies mid
+	// delivery has it picked up again after this
+	claimTimeout = 2 * time.Minute
+	sendTimeout  = 30 * time.Second
+	// a message is dead lettered after this many failed attempts
+	maxAttempts = 8
+	minBackoff  = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// clearedPayload replaces the payload of sent and dead messages, payloads carry addresses,
+// codes and links that aren't kept once nothing will be sent
+const clearedPayload = "{}"
+
+// ErrPermanent marks failures retrying won't fix, e.g a notification without a recipient,
+// the message is dead lettered straight away
+var ErrPermanent = errors.New("permanent failure")
+
+func permanent(err error) error {
+	return fmt.Errorf("%w: %v", ErrPermanent, err)
+}
+
+// Enqueue queues a notification in tx, it goes out once tx commits and is dropped if it
+// rolls back
+func Enqueue(tx *gorm.DB, trigger Trigger) error {
+	return enqueue(tx, models.OutboxTrigger, trigger.EventID, trigger)
+}
+
+// EnqueueSubscriber queues registering the recipient with the provider in tx
+func EnqueueSubscriber(tx *gorm.DB, subscriber Subscriber) error {
+	return enqueue(tx, models.OutboxSubscriber, "subscriber", subscriber)
+}
+
+// EnqueueForget queues removing the recipient from the provider in tx
+func EnqueueForget(tx *gorm.DB, subscriberID string) error {
+	return enqueue(tx, models.OutboxForget, "forget", Subscriber{SubscriberID: subscriberID})
+}
+
+func enqueue(tx *gorm.DB, kind models.OutboxKind, eventID string, payload interface{}) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding %s notification: %w", eventID, err)
+	}
+	message := models.OutboxMessage{
+		Kind:          kind,
+		EventID:       eventID,
+		Payload:       string(raw),
+		Status:        models.OutboxPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := tx.Create(&message).Error; err != nil {
+		return fmt.Errorf("error queueing %s notification: %w", eventID, err)
+	}
+	return nil
+}
+
+// StartWorker delivers queued notifications in the background, failed ones are retried
+// with a growing delay until they are dead lettered
+func StartWorker() {
+	go func() {
+		ticker := time.NewTicker(outboxInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			if err := deliverDue(time.Now()); err != nil {
+				log.Printf("Failed to deliver notifications: %v", err)
+			}
+		}
+	}()
+}
+
+func deliverDue(now time.Time) error {
+	messages, err := claim(now)
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		err := deliver(message)
+		if err := record(message, err, time.Now()); err != nil {
+			log.Printf("Failed to update notification %s: %v", message.ID, err)
+		}
+	}
+	return nil
+}
+
+// claim takes the due messages and pushes their next attempt past the claim timeout so
+// other instances skip them while they are being delivered
+func claim(now time.Time) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, now).
+			Order("next_attempt_at").
+			Limit(outboxBatch).
+			Find(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, 0, len(messages))
+		for _, message := range messages {
+			ids = append(ids, message.ID)
+		}
+		return tx.Model(&models.OutboxMessage{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(claimTimeout)).Error
+	})
+	return messages, err
+}
+
+func deliver(message models.OutboxMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	switch message.Kind {
+	case models.OutboxSubscriber:
+		var subscriber Subscriber
+		if err := json.Unmarshal([]byte(message.Payload), &subscriber); err != nil {
+			return permanent(err)
+		}
+		return notifier.Identify(ctx, subscriber)
+	case models.OutboxTrigger:
+		var trigger Trigger
+		if err := json.Unmarshal([]byte(message.Payload), &trigger); err != nil {
+			return permanent(err)
+		}
+		return notifier.Send(ctx, trigger)
+	case models.OutboxForget:
+		var subscriber Subscriber
+		if err := json.Unmarshal([]byte(message.Payload), &subscriber); err != nil {
+			return permanent(err)
+		}
+		return notifier.Forget(ctx, subscriber.SubscriberID)
+	default:
+		return permanent(fmt.Errorf("unknown message kind %s", message.Kind))
+	}
+}
+
+func record(message models.OutboxMessage, err error, now time.Time) error {
+	attempts := message.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts": attempts,
+		"provider": notifier.Name(),
+	}
+	switch {
+	case err == nil:
+		updates["status"] = models.OutboxSent
+		updates["sent_at"] = now
+		updates["last_error"] = ""
+		updates["payload"] = clearedPayload
+	case errors.Is(err, ErrPermanent) || attempts >= maxAttempts:
+		log.Printf("Giving up on %s notification %s after %d attempts: %v", message.EventID, message.ID, attempts, err)
+		updates["status"] = models.OutboxDead
+		updates["last_error"] = err.Error()
+		updates["payload"] = clearedPayload
+	default:
+		updates["next_attempt_at"] = now.Add(backoff(attempts))
+		updates["last_error"] = err.Error()
+	}
+	return database.Model(&models.OutboxMessage{}).Where("id = ?", message.ID).Updates(updates).Error
+}
+
+// backoff doubles the delay with every failed attempt
+func backoff(attempts int) time.Duration {
+	delay := minBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
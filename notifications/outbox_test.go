@@ -0,0 +1,170 @@
+package notifications
+
+import (
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"job_board/models"
+)
+
+// mockDatabase points the package at a sqlmock connection for the length of the test
+func mockDatabase(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database
+	database = db
+	t.Cleanup(func() {
+		database = previous
+		conn.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return mock
+}
+
+// afterNow matches the updated_at gorm stamps on every update
+type afterNow struct{ start time.Time }
+
+func (a afterNow) Match(v driver.Value) bool {
+	at, ok := v.(time.Time)
+	return ok && !at.Before(a.start)
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{7, 32 * time.Minute},
+		// doubling again would be 64 minutes
+		{8, time.Hour},
+		{30, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestClaimPushesMessagesPastTheTimeout(t *testing.T) {
+	mock := mockDatabase(t)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox_messages" WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(models.OutboxPending, now, outboxBatch).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "event_id", "payload", "status", "attempts"}).
+			AddRow(first, models.OutboxTrigger, "welcome", `{}`, models.OutboxPending, 0).
+			AddRow(second, models.OutboxTrigger, "welcome", `{}`, models.OutboxPending, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_messages" SET "next_attempt_at"=$1,"updated_at"=$2 WHERE id IN ($3,$4)`)).
+		WithArgs(now.Add(claimTimeout), afterNow{now}, first, second).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	messages, err := claim(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 || messages[0].ID != first || messages[1].Attempts != 3 {
+		t.Errorf("claimed %+v", messages)
+	}
+}
+
+func TestClaimWithNothingDue(t *testing.T) {
+	mock := mockDatabase(t)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox_messages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	// nothing to push back, so no update
+	mock.ExpectCommit()
+
+	messages, err := claim(now)
+	if err != nil || len(messages) != 0 {
+		t.Errorf("claim = %v, %v", messages, err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	provider := notifier.Name()
+	tests := []struct {
+		name     string
+		attempts int
+		err      error
+		// the columns set between attempts and updated_at, in gorm's order
+		set  string
+		args []driver.Value
+	}{
+		{
+			name: "sent",
+			set:  `"last_error"=$2,"payload"=$3,"provider"=$4,"sent_at"=$5,"status"=$6`,
+			args: []driver.Value{"", clearedPayload, provider, now, models.OutboxSent},
+		},
+		{
+			name:     "retried later",
+			attempts: 2,
+			err:      errors.New("provider is down"),
+			set:      `"last_error"=$2,"next_attempt_at"=$3,"provider"=$4`,
+			args:     []driver.Value{"provider is down", now.Add(2 * time.Minute), provider},
+		},
+		{
+			name: "permanent failure",
+			err:  permanent(errors.New("no recipient")),
+			set:  `"last_error"=$2,"payload"=$3,"provider"=$4,"status"=$5`,
+			args: []driver.Value{"permanent failure: no recipient", clearedPayload, provider, models.OutboxDead},
+		},
+		{
+			name:     "out of attempts",
+			attempts: maxAttempts - 1,
+			err:      errors.New("provider is down"),
+			set:      `"last_error"=$2,"payload"=$3,"provider"=$4,"status"=$5`,
+			args:     []driver.Value{"provider is down", clearedPayload, provider, models.OutboxDead},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mockDatabase(t)
+			message := models.OutboxMessage{ID: uuid.New(), EventID: "welcome", Attempts: tt.attempts}
+
+			args := append([]driver.Value{tt.attempts + 1}, tt.args...)
+			args = append(args, afterNow{time.Now()}, message.ID)
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_messages" SET "attempts"=$1,` + tt.set + `,"updated_at"=`)).
+				WithArgs(args...).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			if err := record(message, tt.err, now); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -1,117 +1,51 @@
 package notifications
 
 import (
-
-	novu "github.com/novuhq/go-novu/lib"
-
-	"github.com/joho/godotenv"
-
-	"context"
+	"fmt"
 	"log"
 	"os"
-)
-
-var novuClient *novu.APIClient
-
-var ctx = context.Background()
-
-func init() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Error loading .env file")
-	}
-	novuApiKey := os.Getenv("NOVU_API_KEY")
-	if novuApiKey == "" {
-		log.Fatal("Error loading novu api key")
-	}
-	novuClient = novu.NewAPIClient(novuApiKey, &novu.Config{})
-}
-
-func CreateSubscriber(userDetails Subscriber) (*novu.SubscriberResponse, error) {
-	subscriber := novu.SubscriberPayload{
-		FirstName: userDetails.Name,
-		Email:     userDetails.Email,
-		Avatar:    userDetails.Avatar,
-		Data:      userDetails.Data,
-		// Phone:     userDetails.Phone,
-	}
-	resp, err := novuClient.SubscriberApi.Identify(ctx, userDetails.SubscriberID, subscriber)
-	if err != nil {
-		return nil, err
-	}
-	return &resp, nil
-}
-
-func UpdateSubscriber(subscriberID string, name string) (*novu.SubscriberResponse, error) {
-	updateSubscriber := novu.SubscriberPayload{FirstName: name}
-	resp, err := novuClient.SubscriberApi.Update(ctx, subscriberID, updateSubscriber)
-	if err != nil {
-		return nil, err
-	}
-	return &resp, nil
-}
-
-func SendNotification(payload Trigger) (*novu.EventResponse, error) {
-	// to := map[string]interface{}{
-	// 	"lastName":     "",
-	// 	"firstName":    payload.Name,
-	// 	"subscriberId": payload.SubscriberID,
-	// 	"email":        payload.Email,
-	// }
 
-	resp, err := novuClient.EventApi.Trigger(ctx, payload.EventID, novu.ITriggerPayloadOptions{
-		To:      payload.To,
-		Payload: payload.Data, // dynamic data
-	})
-	if err != nil {
-		return nil, err
-	}
-	return &resp, nil
-}
+	"gorm.io/gorm"
 
-func CreateTopic(topicKey string, topicName string) error {
-	err := novuClient.TopicsApi.Create(ctx, topicKey, topicName)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func AddSubscriber(topicKey string, subscribers []string) error {
-	err := novuClient.TopicsApi.AddSubscribers(ctx, topicKey, subscribers)
-	if err != nil {
-		return err
-	}
-	return nil
-}
+	"job_board/config"
+)
 
-func RemoveSubscriber(topicKey string, subscribers []string) error {
-	err := novuClient.TopicsApi.RemoveSubscribers(ctx, topicKey, subscribers)
-	if err != nil {
-		return err
-	}
+var database *gorm.DB
+
+// notifier delivers what the outbox worker picks up
+var notifier Notifier = NewLogNotifier(nil)
+
+// Setup gives the package its database handle and picks the provider notifications go through
+func Setup(db *gorm.DB, cfg config.Notifications) error {
+	database = db
+	switch cfg.Provider {
+	case "novu":
+		notifier = NewNovuNotifier(cfg.NovuAPIKey)
+	case "smtp":
+		smtpNotifier, err := NewSMTPNotifier(SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})
+		if err != nil {
+			return err
+		}
+		notifier = smtpNotifier
+	case "log":
+		if cfg.LogFile == "" {
+			notifier = NewLogNotifier(nil)
+			break
+		}
+		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("error opening %s: %w", cfg.LogFile, err)
+		}
+		notifier = NewLogNotifier(file)
+	default:
+		return fmt.Errorf("unsupported notification provider %s", cfg.Provider)
+	}
+	log.Printf("notifications go through %s", notifier.Name())
 	return nil
 }
-
-func SendTopicNotification(arg TriggerTopic) (*novu.EventResponse, error) {
-	to := map[string]interface{}{
-		"type":     "Topic",
-		"topicKey": arg.TopicKey,
-	}
-	payload := map[string]interface{}{
-		"name": arg.Title,
-		"organization": map[string]interface{}{
-			"logo": arg.Logo,
-		},
-	}
-
-	resp, err := novuClient.EventApi.Trigger(ctx, arg.EventID, novu.ITriggerPayloadOptions{
-		To:      to,
-		Payload: payload,
-	})
-
-	if err != nil {
-		return nil, err
-	}
-	return &resp, nil
-}
@@ -0,0 +1,157 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// SMTPConfig is the mail server the smtp notifier sends through
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotifier mails notifications itself, using the templates below
+type SMTPNotifier struct {
+	config SMTPConfig
+}
+
+func NewSMTPNotifier(config SMTPConfig) (*SMTPNotifier, error) {
+	if config.Host == "" || config.From == "" {
+		return nil, errors.New("SMTP_HOST and SMTP_FROM are required")
+	}
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &SMTPNotifier{config: config}, nil
+}
+
+type mailTemplate struct {
+	subject string
+	body    *template.Template
+}
+
+func mail(subject, body string) mailTemplate {
+	return mailTemplate{subject: subject, body: template.Must(template.New(subject).Parse(body))}
+}
+
+// templates are keyed by event id, events without one get their data listed
+var templates = map[string]mailTemplate{
+	"welcome": mail("Welcome to Jobby", `Hi {{.name}},
+
+Welcome to Jobby, your account is ready.
+`),
+	"account-activation": mail("Your Jobby admin account", `An admin account was created for you.
+
+Your password is {{.password}}, change it after you sign in.
+`),
+	"otp": mail("Your sign in code", `Your {{.companyName}} sign in code is {{.otp}}.
+
+It expires in a few minutes, ignore this email if you didn't try to sign in.
+`),
+	"email-verification": mail("Verify your email", `Hi {{.name}},
+
+Open the link below to verify your email:
+
+{{.link}}
+`),
+	"password-reset": mail("Reset your password", `Hi {{.name}},
+
+Open the link below to choose a new password:
+
+{{.link}}
+
+Ignore this email if you didn't ask for it.
+`),
+	"company-invitation": mail("You've been invited to a team on Jobby", `{{.inviter}} invited you to join {{.companyName}} as {{.role}}.
+
+Accept the invitation here:
+
+{{.link}}
+`),
+	"company-verification-code": mail("Verify your company", `Your code to verify {{.companyName}} is {{.otp}}.
+`),
+	"job-expiring": mail("Your job is about to expire", `Your job {{.jobTitle}} expires at {{.expiresAt}}.
+`),
+	"job-alert-digest": mail("New jobs for your saved search", `New jobs matching {{.searchName}}:
+{{range .jobs}}
+- {{.title}}{{end}}
+
+Unsubscribe: {{.unsubscribeUrl}}
+`),
+}
+
+func (n *SMTPNotifier) Name() string {
+	return "smtp"
+}
+
+// Identify does nothing, mail only needs the address on each notification
+func (n *SMTPNotifier) Identify(ctx context.Context, subscriber Subscriber) error {
+	return nil
+}
+
+// Forget does nothing, no recipients are kept
+func (n *SMTPNotifier) Forget(ctx context.Context, subscriberID string) error {
+	return nil
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, trigger Trigger) error {
+	to := recipientEmail(trigger)
+	if to == "" {
+		return permanent(fmt.Errorf("%s has no email to send to", trigger.EventID))
+	}
+	subject, body, err := render(trigger)
+	if err != nil {
+		return permanent(err)
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+	address := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	return smtp.SendMail(address, auth, n.config.From, []string{to}, message.Bytes())
+}
+
+func recipientEmail(trigger Trigger) string {
+	if email, ok := trigger.To["email"].(string); ok && email != "" {
+		return email
+	}
+	return trigger.Email
+}
+
+func render(trigger Trigger) (string, string, error) {
+	tmpl, ok := templates[trigger.EventID]
+	if !ok {
+		var body strings.Builder
+		for key, value := range trigger.Data {
+			fmt.Fprintf(&body, "%s: %v\n", key, value)
+		}
+		return trigger.EventID, body.String(), nil
+	}
+	var body bytes.Buffer
+	if err := tmpl.body.Execute(&body, trigger.Data); err != nil {
+		return "", "", fmt.Errorf("error rendering %s: %w", trigger.EventID, err)
+	}
+	return tmpl.subject, body.String(), nil
+}
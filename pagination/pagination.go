@@ -0,0 +1,296 @@
+package pagination
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"job_board/helpers"
+)
+
+const (
+	DefaultPageSize = 15
+	MaxPageSize     = 100
+)
+
+// Sorts maps the sort names a client may request to the columns behind them
+type Sorts struct {
+	Default string
+	Fields  map[string]string
+}
+
+// LookupSorts is shared by the name only lookup tables (genders, degrees, levels...)
+var LookupSorts = Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"name":       "name",
+	},
+}
+
+// CreatedSorts is used by resources that can only be ordered by their timestamps
+var CreatedSorts = Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	},
+}
+
+// Cursor marks the row a keyset page starts after, it is handed to clients as an opaque string
+type Cursor struct {
+	Sort  string      `json:"s"`
+	Desc  bool        `json:"d"`
+	Value interface{} `json:"v"`
+	ID    uuid.UUID   `json:"i"`
+	Prev  bool        `json:"p"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(str string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+type Params struct {
+	PageSize int
+	Page     int
+	Sort     string
+	Desc     bool
+	Cursor   *Cursor
+}
+
+// FromContext reads page_size, page_number, cursor, sort and order from the query string
+func FromContext(ctx *gin.Context, sorts Sorts) (Params, error) {
+	params := Params{
+		PageSize: DefaultPageSize,
+		Page:     1,
+		Sort:     sorts.Fields[sorts.Default],
+		Desc:     true,
+	}
+
+	if pageSize := ctx.Query("page_size"); pageSize != "" {
+		perPage, err := strconv.Atoi(pageSize)
+		if err != nil || perPage < 1 {
+			return params, fmt.Errorf("page_size must be a positive number")
+		}
+		if perPage > MaxPageSize {
+			perPage = MaxPageSize
+		}
+		params.PageSize = perPage
+	}
+
+	if pageNumber := ctx.Query("page_number"); pageNumber != "" {
+		page, err := strconv.Atoi(pageNumber)
+		if err != nil || page < 1 {
+			return params, fmt.Errorf("page_number must be a positive number")
+		}
+		params.Page = page
+	}
+
+	if sort := ctx.Query("sort"); sort != "" {
+		column, ok := sorts.Fields[sort]
+		if !ok {
+			allowed := make([]string, 0, len(sorts.Fields))
+			for name := range sorts.Fields {
+				allowed = append(allowed, name)
+			}
+			return params, fmt.Errorf("unsupported sort %s, use one of %s", sort, strings.Join(allowed, ","))
+		}
+		params.Sort = column
+	}
+
+	switch strings.ToLower(ctx.Query("order")) {
+	case "", "desc":
+		params.Desc = true
+	case "asc":
+		params.Desc = false
+	default:
+		return params, fmt.Errorf("order can be either asc or desc")
+	}
+
+	if str := ctx.Query("cursor"); str != "" {
+		cursor, err := DecodeCursor(str)
+		if err != nil {
+			return params, err
+		}
+		// the cursor carries its own ordering so following links keeps the same sort
+		if !hasColumn(sorts, cursor.Sort) {
+			return params, errors.New("invalid cursor")
+		}
+		params.Sort = cursor.Sort
+		params.Desc = cursor.Desc
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+func hasColumn(sorts Sorts, column string) bool {
+	for _, value := range sorts.Fields {
+		if value == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Page is what every list endpoint returns in its data field
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Links builds the next and prev urls from the current request
+func (p *Page[T]) Links(ctx *gin.Context) *helpers.Links {
+	if p == nil || (p.NextCursor == "" && p.PrevCursor == "") {
+		return nil
+	}
+	build := func(cursor string) string {
+		if cursor == "" {
+			return ""
+		}
+		query := ctx.Request.URL.Query()
+		query.Del("page_number")
+		query.Set("cursor", cursor)
+		u := *ctx.Request.URL
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+	return &helpers.Links{
+		Next: build(p.NextCursor),
+		Prev: build(p.PrevCursor),
+	}
+}
+
+// Find counts and fetches a page of T from the filtered query. Without a cursor it falls back to
+// page_number offsets so old clients keep working, with one it seeks past the cursor row instead
+func Find[T any](db *gorm.DB, params Params) (*Page[T], error) {
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	desc := params.Desc
+	query := db.Session(&gorm.Session{})
+	if params.Cursor != nil {
+		// rows after the cursor in the page direction
+		op := ">"
+		if desc != params.Cursor.Prev {
+			op = "<"
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", params.Sort, op), params.Cursor.Value, params.Cursor.ID)
+		if params.Cursor.Prev {
+			desc = !desc
+		}
+	} else if params.Page > 1 {
+		query = query.Offset((params.Page - 1) * params.PageSize)
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	var data []T
+	if err := query.
+		Order(params.Sort + " " + direction).
+		Order("id " + direction).
+		Limit(params.PageSize + 1).
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(data) > params.PageSize
+	if hasMore {
+		data = data[:params.PageSize]
+	}
+	if params.Cursor != nil && params.Cursor.Prev {
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	page := &Page[T]{
+		Data:    data,
+		Total:   total,
+		PerPage: params.PageSize,
+	}
+	if params.Cursor == nil {
+		page.Page = params.Page
+	}
+	if len(data) == 0 {
+		return page, nil
+	}
+
+	movingBack := params.Cursor != nil && params.Cursor.Prev
+	if hasMore || movingBack {
+		cursor, err := cursorFor(db, params, data[len(data)-1], false)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
+	}
+	if (movingBack && hasMore) || (params.Cursor != nil && !movingBack) || (params.Cursor == nil && params.Page > 1) {
+		cursor, err := cursorFor(db, params, data[0], true)
+		if err != nil {
+			return nil, err
+		}
+		page.PrevCursor = cursor
+	}
+	return page, nil
+}
+
+// cursorFor reads the sort column and id of a row through the gorm schema of the model
+func cursorFor[T any](db *gorm.DB, params Params, row T, prev bool) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&row); err != nil {
+		return "", err
+	}
+	value := reflect.ValueOf(&row).Elem()
+
+	sortField := stmt.Schema.LookUpField(params.Sort)
+	idField := stmt.Schema.LookUpField("id")
+	if sortField == nil || idField == nil {
+		return "", fmt.Errorf("cannot paginate on %s", params.Sort)
+	}
+	sortValue, _ := sortField.ValueOf(context.Background(), value)
+	idValue, _ := idField.ValueOf(context.Background(), value)
+	id, ok := idValue.(uuid.UUID)
+	if !ok {
+		return "", errors.New("cannot paginate on a non uuid id")
+	}
+
+	return Cursor{
+		Sort:  params.Sort,
+		Desc:  params.Desc,
+		Value: sortValue,
+		ID:    id,
+		Prev:  prev,
+	}.Encode(), nil
+}
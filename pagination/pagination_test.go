@@ -0,0 +1,274 @@
+package pagination
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"next page by name", Cursor{Sort: "name", Desc: false, Value: "ada", ID: id}},
+		{"prev page by name", Cursor{Sort: "name", Desc: true, Value: "ada", ID: id, Prev: true}},
+		{"numeric sort value", Cursor{Sort: "salary_sort", Desc: true, Value: 52000.5, ID: id}},
+		{"empty sort value", Cursor{Sort: "title", Value: "", ID: id}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *decoded != tt.cursor {
+				t.Errorf("decoded %+v, want %+v", *decoded, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	for _, str := range []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("not json")),
+		base64.RawURLEncoding.EncodeToString([]byte(`{"i":"not a uuid"}`)),
+	} {
+		if _, err := DecodeCursor(str); err == nil {
+			t.Errorf("DecodeCursor(%q) succeeded", str)
+		}
+	}
+}
+
+// item is the model the tests page through
+type item struct {
+	ID   uuid.UUID
+	Name string
+}
+
+// openItems opens a gorm handle on items over a sqlmock connection, every test scripts
+// the count and the page query Find makes
+func openItems(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return db.Model(&item{}), mock
+}
+
+func itemRows(rows ...item) *sqlmock.Rows {
+	out := sqlmock.NewRows([]string{"id", "name"})
+	for _, row := range rows {
+		out.AddRow(row.ID.String(), row.Name)
+	}
+	return out
+}
+
+func names(rows []item) string {
+	var out string
+	for _, row := range rows {
+		out += row.Name
+	}
+	return out
+}
+
+// decode reads a page's cursor back, nil when the page has none
+func decode(t *testing.T, str string) *Cursor {
+	t.Helper()
+	if str == "" {
+		return nil
+	}
+	cursor, err := DecodeCursor(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cursor
+}
+
+func TestCursorFor(t *testing.T) {
+	db, _ := openItems(t)
+	row := item{ID: uuid.New(), Name: "grace"}
+
+	tests := []struct {
+		name   string
+		params Params
+		prev   bool
+	}{
+		{"ascending next", Params{Sort: "name"}, false},
+		{"descending prev", Params{Sort: "name", Desc: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			str, err := cursorFor(db, tt.params, row, tt.prev)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Cursor{Sort: "name", Desc: tt.params.Desc, Value: "grace", ID: row.ID, Prev: tt.prev}
+			if cursor := decode(t, str); *cursor != want {
+				t.Errorf("cursor %+v, want %+v", *cursor, want)
+			}
+		})
+	}
+
+	if _, err := cursorFor(db, Params{Sort: "missing"}, row, false); err == nil {
+		t.Error("cursorFor paginated on a column the model doesn't have")
+	}
+}
+
+func TestFind(t *testing.T) {
+	// two rows share a name so the id has to break the tie
+	a, b1, b2, c, d := item{uuid.New(), "a"}, item{uuid.New(), "b"}, item{uuid.New(), "b"}, item{uuid.New(), "c"}, item{uuid.New(), "d"}
+	tests := []struct {
+		name   string
+		params Params
+		// the page query after SELECT * FROM "items", its arguments and the rows it returns
+		query string
+		args  []driver.Value
+		rows  []item
+		// the names on the page and the rows the cursors point at
+		want string
+		next *item
+		prev *item
+	}{
+		{
+			name:   "first page",
+			params: Params{PageSize: 2, Page: 1, Sort: "name"},
+			query:  `ORDER BY name ASC,id ASC LIMIT $1`,
+			args:   []driver.Value{3},
+			rows:   []item{a, b1, b2},
+			want:   "ab",
+			next:   &b1,
+		},
+		{
+			name:   "last page",
+			params: Params{PageSize: 2, Page: 1, Sort: "name"},
+			query:  `ORDER BY name ASC,id ASC LIMIT $1`,
+			args:   []driver.Value{3},
+			rows:   []item{a, b1},
+			want:   "ab",
+		},
+		{
+			name:   "offset page",
+			params: Params{PageSize: 2, Page: 2, Sort: "name"},
+			query:  `ORDER BY name ASC,id ASC LIMIT $1 OFFSET $2`,
+			args:   []driver.Value{3, 2},
+			rows:   []item{b2, c, d},
+			want:   "bc",
+			next:   &c,
+			prev:   &b2,
+		},
+		{
+			name:   "next page in the middle of a tie",
+			params: Params{PageSize: 2, Sort: "name", Cursor: &Cursor{Sort: "name", Value: "b", ID: b1.ID}},
+			query:  `WHERE (name, id) > ($1, $2) ORDER BY name ASC,id ASC LIMIT $3`,
+			args:   []driver.Value{"b", b1.ID, 3},
+			rows:   []item{b2, c},
+			want:   "bc",
+			prev:   &b2,
+		},
+		{
+			name:   "next page descending",
+			params: Params{PageSize: 2, Sort: "name", Desc: true, Cursor: &Cursor{Sort: "name", Desc: true, Value: "c", ID: c.ID}},
+			query:  `WHERE (name, id) < ($1, $2) ORDER BY name DESC,id DESC LIMIT $3`,
+			args:   []driver.Value{"c", c.ID, 3},
+			rows:   []item{b2, b1, a},
+			want:   "bb",
+			next:   &b1,
+			prev:   &b2,
+		},
+		{
+			// the page before is read backwards and turned around
+			name:   "prev page",
+			params: Params{PageSize: 2, Sort: "name", Cursor: &Cursor{Sort: "name", Value: "c", ID: c.ID, Prev: true}},
+			query:  `WHERE (name, id) < ($1, $2) ORDER BY name DESC,id DESC LIMIT $3`,
+			args:   []driver.Value{"c", c.ID, 3},
+			rows:   []item{b2, b1, a},
+			want:   "bb",
+			next:   &b2,
+			prev:   &b1,
+		},
+		{
+			name:   "prev page reaching the start",
+			params: Params{PageSize: 2, Sort: "name", Cursor: &Cursor{Sort: "name", Value: "b", ID: b2.ID, Prev: true}},
+			query:  `WHERE (name, id) < ($1, $2) ORDER BY name DESC,id DESC LIMIT $3`,
+			args:   []driver.Value{"b", b2.ID, 3},
+			rows:   []item{b1, a},
+			want:   "ab",
+			next:   &b1,
+		},
+		{
+			name:   "prev page descending",
+			params: Params{PageSize: 2, Sort: "name", Desc: true, Cursor: &Cursor{Sort: "name", Desc: true, Value: "b", ID: b1.ID, Prev: true}},
+			query:  `WHERE (name, id) > ($1, $2) ORDER BY name ASC,id ASC LIMIT $3`,
+			args:   []driver.Value{"b", b1.ID, 3},
+			rows:   []item{b2, c},
+			want:   "cb",
+			next:   &b2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := openItems(t)
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "items"`)).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "items" ` + tt.query)).
+				WithArgs(tt.args...).
+				WillReturnRows(itemRows(tt.rows...))
+
+			page, err := Find[item](db, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(page.Data); got != tt.want {
+				t.Errorf("page %s, want %s", got, tt.want)
+			}
+			if page.Total != 5 || page.PerPage != 2 {
+				t.Errorf("total %d per page %d", page.Total, page.PerPage)
+			}
+
+			for _, link := range []struct {
+				name   string
+				cursor *Cursor
+				want   *item
+				prev   bool
+			}{
+				{"next", decode(t, page.NextCursor), tt.next, false},
+				{"prev", decode(t, page.PrevCursor), tt.prev, true},
+			} {
+				if link.want == nil {
+					if link.cursor != nil {
+						t.Errorf("unexpected %s cursor %+v", link.name, *link.cursor)
+					}
+					continue
+				}
+				want := Cursor{Sort: "name", Desc: tt.params.Desc, Value: link.want.Name, ID: link.want.ID, Prev: link.prev}
+				if link.cursor == nil || *link.cursor != want {
+					t.Errorf("%s cursor %+v, want %+v", link.name, link.cursor, want)
+				}
+			}
+		})
+	}
+}
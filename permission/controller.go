@@ -0,0 +1,74 @@
+package permission
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"job_board/helpers"
+	"job_board/models"
+)
+
+func get(ctx *gin.Context) {
+	resp, err := getCatalogue()
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched permissions",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func getSingle(ctx *gin.Context) {
+	resp, err := getRole(models.RoleAllowed(ctx.Param("role")))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched role permissions",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
+
+func update(ctx *gin.Context) {
+	var req Request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	resp, err := setRole(models.RoleAllowed(ctx.Param("role")), req.Permissions)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully updated role permissions",
+		StatusCode: http.StatusOK,
+		Data:       resp,
+	})
+}
@@ -0,0 +1,19 @@
+package permission
+
+import "job_board/models"
+
+type Request struct {
+	Permissions []models.Permission `json:"permissions" binding:"required"`
+}
+
+type RolePermissions struct {
+	Role        models.RoleAllowed  `json:"role"`
+	Permissions []models.Permission `json:"permissions"`
+	// super admins hold every permission and can't be edited
+	Editable bool `json:"editable"`
+}
+
+type Catalogue struct {
+	Permissions []models.Permission `json:"permissions"`
+	Roles       []RolePermissions   `json:"roles"`
+}
@@ -0,0 +1,18 @@
+package permission
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"job_board/jwt"
+	"job_board/middleware"
+	"job_board/models"
+)
+
+func PermissionRoutes(superRoute *gin.RouterGroup) {
+	permissionRouter := superRoute.Group("/permissions")
+
+	permissionRouter.Use(jwt.Middleware(), middleware.PermissionMiddleware(models.PermissionManage))
+	permissionRouter.GET("/", get)
+	permissionRouter.GET("/roles/:role", getSingle)
+	permissionRouter.PUT("/roles/:role", update)
+}
@@ -0,0 +1,96 @@
+package permission
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"job_board/models"
+	"job_board/policy"
+)
+
+var database *gorm.DB
+
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
+}
+
+func getRole(role models.RoleAllowed) (*RolePermissions, error) {
+	if role == models.SuperAdminRole {
+		return &RolePermissions{Role: role, Permissions: models.Permissions}, nil
+	}
+	if !models.IsRole(role) {
+		return nil, fmt.Errorf("unknown role %s", role)
+	}
+
+	var rows []models.RolePermission
+	if err := database.Where("role_name = ?", role).Order("permission ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error fetching role permissions: %w", err)
+	}
+	data := RolePermissions{Role: role, Permissions: []models.Permission{}, Editable: true}
+	for _, row := range rows {
+		data.Permissions = append(data.Permissions, row.Permission)
+	}
+	return &data, nil
+}
+
+func getCatalogue() (*Catalogue, error) {
+	data := Catalogue{Permissions: models.Permissions}
+	for _, role := range append([]models.RoleAllowed{models.SuperAdminRole}, models.Roles...) {
+		permissions, err := getRole(role)
+		if err != nil {
+			return nil, err
+		}
+		data.Roles = append(data.Roles, *permissions)
+	}
+	return &data, nil
+}
+
+// setRole replaces everything the role may do with permissions
+func setRole(role models.RoleAllowed, permissions []models.Permission) (*RolePermissions, error) {
+	if role == models.SuperAdminRole {
+		return nil, fmt.Errorf("super admins hold every permission and can't be edited")
+	}
+	if !models.IsRole(role) {
+		return nil, fmt.Errorf("unknown role %s", role)
+	}
+
+	rows := []models.RolePermission{}
+	seen := map[models.Permission]bool{}
+	for _, permission := range permissions {
+		if !models.IsPermission(permission) {
+			return nil, fmt.Errorf("unknown permission %s", permission)
+		}
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		rows = append(rows, models.RolePermission{RoleName: role, Permission: permission})
+	}
+
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("role_name = ?", role).Delete(&models.RolePermission{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error clearing role permissions: %w", err)
+	}
+	if len(rows) > 0 {
+		if err := tx.Create(&rows).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error saving role permissions: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	policy.Invalidate()
+
+	return getRole(role)
+}
@@ -0,0 +1,152 @@
+package policy
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"job_board/models"
+)
+
+// ErrForbidden is returned by Check when none of the user's permissions cover the record
+var ErrForbidden = errors.New("you don't have permission to access this record")
+
+// grants are reread after this long so edits made on another instance show up
+const cacheTTL = time.Minute
+
+var database *gorm.DB
+
+var (
+	mu       sync.RWMutex
+	grants   map[models.RoleAllowed]map[models.Permission]bool
+	loadedAt time.Time
+)
+
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
+}
+
+func load() (map[models.RoleAllowed]map[models.Permission]bool, error) {
+	var rows []models.RolePermission
+	if err := database.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	loaded := map[models.RoleAllowed]map[models.Permission]bool{}
+	for _, row := range rows {
+		if loaded[row.RoleName] == nil {
+			loaded[row.RoleName] = map[models.Permission]bool{}
+		}
+		loaded[row.RoleName][row.Permission] = true
+	}
+	return loaded, nil
+}
+
+func roleGrants(role models.RoleAllowed) map[models.Permission]bool {
+	mu.RLock()
+	current, fresh := grants, time.Since(loadedAt) < cacheTTL
+	mu.RUnlock()
+	if current != nil && fresh {
+		return current[role]
+	}
+
+	loaded, err := load()
+	if err != nil {
+		// keep answering from the stale copy rather than locking everybody out
+		log.Println("Error loading role permissions:", err)
+		return current[role]
+	}
+
+	mu.Lock()
+	grants, loadedAt = loaded, time.Now()
+	mu.Unlock()
+	return loaded[role]
+}
+
+// Invalidate drops the cached grants, the next check reads them from the database
+func Invalidate() {
+	mu.Lock()
+	grants = nil
+	mu.Unlock()
+}
+
+// Can reports whether the user's role holds the permission, super admins hold all of them
+func Can(user models.User, permission models.Permission) bool {
+	if user.RoleName == models.SuperAdminRole {
+		return true
+	}
+	return roleGrants(user.RoleName)[permission]
+}
+
+// CanAny reports whether the user holds at least one of the permissions
+func CanAny(user models.User, permissions ...models.Permission) bool {
+	for _, permission := range permissions {
+		if Can(user, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope ties a permission scope to whether the user falls into it for one record
+type Scope struct {
+	Name    string
+	matches func(user models.User) bool
+}
+
+func owns(user models.User, owners []uuid.UUID) bool {
+	for _, owner := range owners {
+		if owner != uuid.Nil && owner == user.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// Own matches when the user is one of the record's owners
+func Own(owners ...uuid.UUID) Scope {
+	return Scope{Name: "own", matches: func(user models.User) bool {
+		return owns(user, owners)
+	}}
+}
+
+// OwnProfile matches records hanging off the user's own profile
+func OwnProfile(profileID uuid.UUID) Scope {
+	return Scope{Name: "own", matches: func(user models.User) bool {
+		return user.Profile != nil && user.Profile.ID == profileID
+	}}
+}
+
+// Company matches when the user is on the team of the company the record belongs to with
+// one of the roles, no roles accepts every member
+func Company(companyID uuid.UUID, roles ...models.CompanyRole) Scope {
+	return Scope{Name: "company", matches: func(user models.User) bool {
+		return models.IsCompanyMember(database, companyID, user.ID, roles...)
+	}}
+}
+
+// Applicant matches when the user is on the team of a company the applicant applied to, for
+// records that belong to the applicant rather than to a job
+func Applicant(applicantID uuid.UUID) Scope {
+	return Scope{Name: "company", matches: func(user models.User) bool {
+		return models.HasAppliedToCompanyOf(database, applicantID, user.ID)
+	}}
+}
+
+// Check is the resource level check services make. action is resource:action, the
+// :any permission always passes and each scope passes when the user is in it and holds
+// the permission for that scope
+func Check(user models.User, action string, scopes ...Scope) error {
+	if Can(user, models.Permission(action+":any")) {
+		return nil
+	}
+	for _, scope := range scopes {
+		if scope.matches(user) && Can(user, models.Permission(action+":"+scope.Name)) {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"job_board/models"
+)
+
+// mockDatabase points the package at a sqlmock connection that grants every role its
+// default permissions, the first check reads them
+func mockDatabase(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database
+	database = db
+	Invalidate()
+	t.Cleanup(func() {
+		database = previous
+		Invalidate()
+		conn.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	rows := sqlmock.NewRows([]string{"role_name", "permission"})
+	for role, permissions := range models.DefaultRolePermissions {
+		for _, permission := range permissions {
+			rows.AddRow(role, permission)
+		}
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "role_permissions"`)).WillReturnRows(rows)
+	return mock
+}
+
+// expectMember answers one membership lookup of Company
+func expectMember(mock sqlmock.Sqlmock, companyID uuid.UUID, userID uuid.UUID, member bool) {
+	count := 0
+	if member {
+		count = 1
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "company_members" WHERE company_id = $1 AND user_id = $2`)).
+		WithArgs(companyID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestCan(t *testing.T) {
+	mockDatabase(t)
+	tests := []struct {
+		role       models.RoleAllowed
+		permission models.Permission
+		want       bool
+	}{
+		{models.UserRole, models.ApplicationCreate, true},
+		{models.UserRole, models.JobCreate, false},
+		{models.PosterRole, models.JobUpdateCompany, true},
+		{models.PosterRole, models.JobUpdateAny, false},
+		{models.AdminRole, models.JobUpdateAny, true},
+		// super admins hold permissions no role was granted
+		{models.SuperAdminRole, models.Permission("anything:at:all"), true},
+	}
+	for _, tt := range tests {
+		if got := Can(models.User{RoleName: tt.role}, tt.permission); got != tt.want {
+			t.Errorf("Can(%s, %s) = %v, want %v", tt.role, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOwnScope(t *testing.T) {
+	mockDatabase(t)
+	applicant := models.User{ID: uuid.New(), RoleName: models.UserRole}
+
+	if err := Check(applicant, "application:read", Own(applicant.ID)); err != nil {
+		t.Errorf("reading an own application: %v", err)
+	}
+	if err := Check(applicant, "application:read", Own(uuid.New())); !errors.Is(err, ErrForbidden) {
+		t.Errorf("reading someone else's application: %v", err)
+	}
+	// the scope has to come with the permission for it
+	if err := Check(applicant, "application:delete", Own(applicant.ID)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("deleting an own application without application:delete:own: %v", err)
+	}
+	// records without an owner are nobody's
+	if err := Check(models.User{RoleName: models.UserRole}, "application:read", Own(uuid.Nil)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("a user without an id owns a record without an owner: %v", err)
+	}
+}
+
+func TestCheckOwnProfileScope(t *testing.T) {
+	mockDatabase(t)
+	profileID := uuid.New()
+	user := models.User{ID: uuid.New(), RoleName: models.UserRole, Profile: &models.Profile{ID: profileID}}
+
+	if err := Check(user, "profile:update", OwnProfile(profileID)); err != nil {
+		t.Errorf("updating the own profile: %v", err)
+	}
+	if err := Check(user, "profile:update", OwnProfile(uuid.New())); !errors.Is(err, ErrForbidden) {
+		t.Errorf("updating another profile: %v", err)
+	}
+	if err := Check(models.User{RoleName: models.UserRole}, "profile:update", OwnProfile(profileID)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("updating a profile without having one: %v", err)
+	}
+}
+
+func TestCheckCompanyScope(t *testing.T) {
+	mock := mockDatabase(t)
+	companyID := uuid.New()
+	poster := models.User{ID: uuid.New(), RoleName: models.PosterRole}
+
+	expectMember(mock, companyID, poster.ID, true)
+	if err := Check(poster, "job:update", Company(companyID)); err != nil {
+		t.Errorf("a member updating the company's job: %v", err)
+	}
+
+	expectMember(mock, companyID, poster.ID, false)
+	if err := Check(poster, "job:update", Company(companyID)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("an outsider updating the company's job: %v", err)
+	}
+
+	// roles narrow the scope to some of the team
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "company_members" WHERE (company_id = $1 AND user_id = $2) AND role IN ($3,$4)`)).
+		WithArgs(companyID, poster.ID, models.CompanyOwner, models.CompanyRecruiter).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	if err := Check(poster, "job:update", Company(companyID, models.Hiring...)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("a viewer updating the company's job: %v", err)
+	}
+
+	// an applicant on the team still only gets what their role grants
+	applicant := models.User{ID: uuid.New(), RoleName: models.UserRole}
+	expectMember(mock, companyID, applicant.ID, true)
+	if err := Check(applicant, "job:update", Company(companyID)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("a team member without job:update:company: %v", err)
+	}
+}
+
+func TestCheckAnyPermissionSkipsScopes(t *testing.T) {
+	mockDatabase(t)
+	admin := models.User{ID: uuid.New(), RoleName: models.AdminRole}
+
+	// no membership lookup is expected, the :any permission answers first
+	if err := Check(admin, "job:update", Company(uuid.New())); err != nil {
+		t.Errorf("an admin updating any job: %v", err)
+	}
+	if err := Check(admin, "job:update"); err != nil {
+		t.Errorf("an admin updating a job without scopes: %v", err)
+	}
+	if err := Check(models.User{RoleName: models.PosterRole}, "job:update"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("a poster updating a job without scopes: %v", err)
+	}
+}
+
+func TestCheckTriesEveryScope(t *testing.T) {
+	mock := mockDatabase(t)
+	companyID := uuid.New()
+	poster := models.User{ID: uuid.New(), RoleName: models.PosterRole}
+
+	// the upload isn't theirs but belongs to someone who applied to their company
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "job_applications" JOIN jobs`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	if err := Check(poster, "file:read", Own(uuid.New()), Applicant(uuid.New())); err != nil {
+		t.Errorf("reading an applicant's upload: %v", err)
+	}
+
+	expectMember(mock, companyID, poster.ID, false)
+	if err := Check(poster, "file:read", Own(uuid.New()), Company(companyID)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("reading a stranger's upload: %v", err)
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"errors"
 	"net/http"
 	"strconv"
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 	// "job_board/notifications"
 )
 
@@ -41,7 +43,7 @@ func CreateProfile(ctx *gin.Context) {
 	profile, err := createProfile(user.ID, models.Profile{
 		UserID:                   user.ID,
 		Bio:                      req.Bio,
-		Resume:                   req.Resume,
+		ResumeID:                 &req.ResumeID,
 		GenderID:                 req.GenderID,
 		CurrentSalary:            req.CurrentSalary,
 		CurrentSalaryCurrencyID:  &req.CurrentSalaryCurrencyID,
@@ -133,9 +135,20 @@ func GetProfile(ctx *gin.Context) {
 		}
 	}
 
+	var minCompleteness int
+	if val := ctx.Query("min_completeness"); val != "" {
+		if minCompleteness, err = strconv.Atoi(val); err != nil || minCompleteness < 0 || minCompleteness > 100 {
+			helpers.CreateResponse(ctx, helpers.Response{
+				Message:    "min_completeness must be a whole number from 0 to 100",
+				StatusCode: http.StatusBadRequest,
+				Data:       nil,
+			})
+			return
+		}
+	}
+
 	filter := ProfileDto{
 		Bio:                      ctx.Query("bio"),
-		Resume:                   ctx.Query("resume"),
 		GenderID:                 genderID,
 		CurrentSalary:            currentSalary,
 		CurrentSalaryCurrencyID:  currentSalaryCurrencyID,
@@ -143,7 +156,16 @@ func GetProfile(ctx *gin.Context) {
 		ExpectedSalaryCurrencyID: expectedSalaryCurrencyID,
 	}
 
-	profiles, total, page, perPage, err := getProfiles(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, profileSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	profiles, err := getProfiles(filter, minCompleteness, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -156,12 +178,8 @@ func GetProfile(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched profiles",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     profiles,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       profiles,
+		Links:      profiles.Links(ctx),
 	})
 }
 
@@ -235,12 +253,6 @@ func UpdateProfile(ctx *gin.Context) {
 		return
 	}
 
-	// Prepare search criteria
-	search := models.Profile{ID: profileID}
-	if user.RoleName == models.UserRole {
-		search.UserID = user.ID
-	}
-
 	// Parse request body
 	var req ProfileDto
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -255,7 +267,7 @@ func UpdateProfile(ctx *gin.Context) {
 	// Create a map to hold updated profile fields
 	profileMap := make(map[string]interface{})
 	profileMap["bio"] = req.Bio
-	profileMap["resume"] = req.Resume
+	profileMap["resume_id"] = req.ResumeID
 	profileMap["gender_id"] = req.GenderID
 	if req.CurrentSalary != 0.0 {
 		profileMap["current_salary"] = req.CurrentSalary
@@ -315,14 +327,8 @@ func DeleteProfile(ctx *gin.Context) {
 		return
 	}
 
-	// Prepare search criteria
-	search := models.Profile{ID: profileID}
-	if user.RoleName == models.UserRole {
-		search.UserID = user.ID
-	}
-
 	// Delete profile
-	err = deleteSingleProfile(search)
+	err = deleteSingleProfile(profileID, *user)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -342,4 +348,77 @@ func DeleteProfile(ctx *gin.Context) {
 	})
 }
 
+func UpdatePrivacy(ctx *gin.Context) {
+	user, err := models.GetUserFromContext(ctx)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+
+	profileID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req PrivacyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	profile, err := updatePrivacy(profileID, *user, req)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+	session := sessions.Default(ctx)
+	user.Profile = profile
+	session.Set(user.ProviderID, user)
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully updated profile privacy",
+		StatusCode: http.StatusOK,
+		Data:       privacyOf(*profile),
+	})
+}
+
+func publicProfile(ctx *gin.Context) {
+	profile, err := PublicProfile(ctx.Param("slug"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrProfileNotFound) {
+			status = http.StatusNotFound
+		}
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: status,
+			Data:       nil,
+		})
+		return
+	}
+
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "Successfully fetched profile",
+		StatusCode: http.StatusOK,
+		Data:       profile,
+	})
+}
+
 /* profile segment ends*/
@@ -1,12 +1,29 @@
 package profile
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+
+	"job_board/completeness"
+	"job_board/models"
+	"job_board/pagination"
 )
 
+var profileSorts = pagination.Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at":      "created_at",
+		"updated_at":      "updated_at",
+		"expected_salary": "expected_salary",
+		"current_salary":  "current_salary",
+		"completeness":    "completeness",
+	},
+}
+
 type ProfileDto struct {
 	Bio                      string    `json:"bio" binding:"required"`
-	Resume                   string    `json:"resume_link" binding:"required"`
+	ResumeID                 uuid.UUID `json:"resume_id" binding:"required"`
 	GenderID                 uuid.UUID `json:"gender_id" binding:"required"`
 	CurrentSalary            float64   `json:"current_salary" binding:"omitempty"`
 	CurrentSalaryCurrencyID  uuid.UUID `json:"current_salary_id" binding:"omitempty"`
@@ -14,4 +31,55 @@ type ProfileDto struct {
 	ExpectedSalaryCurrencyID uuid.UUID `json:"expected_salary_id" binding:"omitempty"`
 }
 
+// PrivacyRequest changes who can see a profile and which parts of it, fields left out
+// keep their value
+type PrivacyRequest struct {
+	Visibility  *models.ProfileVisibility `json:"visibility" binding:"omitempty,oneof=private applied_employers public"`
+	HideSalary  *bool                     `json:"hide_salary"`
+	HideContact *bool                     `json:"hide_contact"`
+}
+
+// ProfileView is a profile as somebody other than its owner sees it, hidden sections are
+// left out and listed in HiddenSections
+type ProfileView struct {
+	ID                     uuid.UUID                     `json:"id"`
+	Slug                   string                        `json:"slug"`
+	Name                   string                        `json:"name"`
+	Picture                string                        `json:"picture"`
+	Email                  string                        `json:"email,omitempty"`
+	MobileNumber           *string                       `json:"mobile_number,omitempty"`
+	ResumeID               *uuid.UUID                    `json:"resume_id,omitempty"`
+	Bio                    string                        `json:"bio"`
+	Skills                 []string                      `json:"skills"`
+	Gender                 string                        `json:"gender"`
+	CurrentSalary          *float64                      `json:"current_salary,omitempty"`
+	CurrentSalaryCurrency  string                        `json:"current_salary_currency,omitempty"`
+	ExpectedSalary         *float64                      `json:"expected_salary,omitempty"`
+	ExpectedSalaryCurrency string                        `json:"expected_salary_currency,omitempty"`
+	Educations             []models.Education            `json:"educations"`
+	Internships            []models.InternShipExperience `json:"internships"`
+	Projects               []models.ProjectsExperience   `json:"projects"`
+	WorkSamples            []models.WorkSample           `json:"work_samples"`
+	Awards                 []models.Award                `json:"awards"`
+	Languages              []models.ProfileLanguage      `json:"languages"`
+	SocialMediaAccounts    []models.SocialMediaAccount   `json:"social_media_accounts"`
+	Completeness           int                           `json:"completeness"`
+	HiddenSections         []string                      `json:"hidden_sections,omitempty"`
+	UpdatedAt              time.Time                     `json:"updated_at"`
+}
 
+// ProfileResponse is a profile as its owner and admins see it, with how complete it is and
+// what to add next
+type ProfileResponse struct {
+	*models.Profile
+	Completeness completeness.Result `json:"completeness"`
+}
+
+// PrivacyResponse is a profile's privacy settings and the link to share it with
+type PrivacyResponse struct {
+	Slug        string                   `json:"slug"`
+	Visibility  models.ProfileVisibility `json:"visibility"`
+	HideSalary  bool                     `json:"hide_salary"`
+	HideContact bool                     `json:"hide_contact"`
+	PublicURL   string                   `json:"public_url,omitempty"`
+}
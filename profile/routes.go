@@ -0,0 +1,10 @@
+package profile
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// CandidateRoutes serves public profiles by their slug, no sign in needed
+func CandidateRoutes(superRoute *gin.RouterGroup) {
+	superRoute.GET("/candidates/:slug", publicProfile)
+}
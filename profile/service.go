@@ -4,19 +4,21 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/completeness"
+	"job_board/matching"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func createProfile(userID uuid.UUID, profile models.Profile) (*models.Profile, error) {
@@ -27,6 +29,11 @@ func createProfile(userID uuid.UUID, profile models.Profile) (*models.Profile, e
 		}
 	}()
 
+	if err := models.CheckFileOwner(tx, *profile.ResumeID, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Create(&profile).Error; err != nil {
 		tx.Rollback()
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
@@ -52,29 +59,13 @@ func createProfile(userID uuid.UUID, profile models.Profile) (*models.Profile, e
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(profile.ID)
+	completeness.Queue(profile.ID)
+
 	return &profile, nil
 }
 
-func getProfiles(filter ProfileDto, pageSize string, pageNumber string) ([]models.Profile, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getProfiles(filter ProfileDto, minCompleteness int, params pagination.Params) (*pagination.Page[models.Profile], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.Profile{})
 
@@ -82,9 +73,6 @@ func getProfiles(filter ProfileDto, pageSize string, pageNumber string) ([]model
 	if filter.Bio != "" {
 		db = db.Where("bio LIKE ?", "%"+filter.Bio+"%")
 	}
-	if filter.Resume != "" {
-		db = db.Where("resume LIKE ?", "%"+filter.Resume+"%")
-	}
 	if filter.GenderID != uuid.Nil {
 		db = db.Where("gender_id = ?", filter.GenderID)
 	}
@@ -100,52 +88,25 @@ func getProfiles(filter ProfileDto, pageSize string, pageNumber string) ([]model
 	if filter.ExpectedSalaryCurrencyID != uuid.Nil {
 		db = db.Where("expected_salary_currency_id = ?", filter.ExpectedSalaryCurrencyID)
 	}
+	if minCompleteness > 0 {
+		db = db.Where("completeness >= ?", minCompleteness)
+	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.Profile
-	if err := db.
-		// Preload("Educations").
-		// Preload("InternShipExperiences").
-		// Preload("ProjectsExperiences").
-		// Preload("WorkSamples").
-		// Preload("Awards").
-		// Preload("ProfileLanguages").
-		// Preload("SocialMediaAccounts").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.Profile](db, params)
+	if err != nil {
 		log.Println("Error finding profiles:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
-func getProfile(ID uuid.UUID, user models.User) (*models.Profile, error) {
+func getProfile(ID uuid.UUID, user models.User) (interface{}, error) {
 	profile := models.Profile{}
-	if err := database.
-		Preload("Educations").
-		Preload("InternShipExperiences").
-		Preload("ProjectsExperiences").
-		Preload("WorkSamples").
-		Preload("Awards").
-		Preload("ProfileLanguages").
-		Preload("SocialMediaAccounts").
-		First(&profile, "id = ?", ID).Error; err != nil {
+	if err := withSections(database).First(&profile, "id = ?", ID).Error; err != nil {
 		return nil, err
 	}
-	if (user.RoleName == models.UserRole || user.RoleName == models.PosterRole) && user.ID != profile.UserID {
-		return nil, fmt.Errorf("error: you don't ave access to this resource")
-	}
-
-	return &profile, nil
+	return viewFor(profile, &user)
 }
 
 func updateProfile(ID uuid.UUID, user models.User, profile map[string]interface{}) (*models.Profile, error) {
@@ -169,8 +130,16 @@ func updateProfile(ID uuid.UUID, user models.User, profile map[string]interface{
 		return nil, err // Record not found or other database error
 	}
 
-	if (user.RoleName == models.UserRole || user.RoleName == models.PosterRole) && user.ID != existingRecord.UserID {
-		return nil, fmt.Errorf("error: you don't ave access to this resource")
+	if err := policy.Check(user, "profile:update", policy.Own(existingRecord.UserID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error: you don't have access to this resource")
+	}
+
+	if resumeID, ok := profile["resume_id"].(uuid.UUID); ok {
+		if err := models.CheckFileOwner(tx, resumeID, existingRecord.UserID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	// Update the record with the provided updates
@@ -182,11 +151,79 @@ func updateProfile(ID uuid.UUID, user models.User, profile map[string]interface{
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(existingRecord.ID)
+	completeness.Queue(existingRecord.ID)
+
+	return &existingRecord, nil
+}
+
+func updatePrivacy(ID uuid.UUID, user models.User, req PrivacyRequest) (*models.Profile, error) {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var existingRecord models.Profile
+	if err := tx.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := policy.Check(user, "profile:update", policy.Own(existingRecord.UserID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error: you don't have access to this resource")
+	}
+
+	values := map[string]interface{}{}
+	if req.Visibility != nil {
+		values["visibility"] = *req.Visibility
+	}
+	if req.HideSalary != nil {
+		values["hide_salary"] = *req.HideSalary
+	}
+	if req.HideContact != nil {
+		values["hide_contact"] = *req.HideContact
+	}
+	if len(values) > 0 {
+		if err := tx.Model(&existingRecord).Updates(values).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error updating privacy settings: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
 	return &existingRecord, nil
 }
 
-func deleteSingleProfile(search models.Profile) error {
-	result := database.Delete(&search)
+// privacyOf is the profile's privacy settings, the public link is only given out once the
+// profile is public
+func privacyOf(profile models.Profile) PrivacyResponse {
+	resp := PrivacyResponse{
+		Slug:        profile.Slug,
+		Visibility:  profile.Visibility,
+		HideSalary:  profile.HideSalary,
+		HideContact: profile.HideContact,
+	}
+	if profile.Visibility == models.ProfilePublic {
+		resp.PublicURL = "/api/v1/candidates/" + profile.Slug
+	}
+	return resp
+}
+
+func deleteSingleProfile(ID uuid.UUID, user models.User) error {
+	var existingRecord models.Profile
+	if err := database.First(&existingRecord, "id = ?", ID).Error; err != nil {
+		return err
+	}
+	if err := policy.Check(user, "profile:delete", policy.Own(existingRecord.UserID)); err != nil {
+		return fmt.Errorf("error: you don't have access to this resource")
+	}
+
+	result := database.Delete(&existingRecord)
 	if result.RowsAffected == 0 {
 		return errors.New("user already deleted")
 	}
@@ -0,0 +1,156 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"job_board/completeness"
+	"job_board/models"
+	"job_board/policy"
+)
+
+const (
+	salarySection  = "salary"
+	contactSection = "contact"
+)
+
+var ErrProfileNotFound = errors.New("profile not found")
+
+func withSections(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("User").
+		Preload("Gender").
+		Preload("CurrentSalaryCurrency").
+		Preload("ExpectedSalaryCurrency").
+		Preload("Educations.Degree").
+		Preload("Educations.AcademicRanking").
+		Preload("InternShipExperiences").
+		Preload("ProjectsExperiences").
+		Preload("WorkSamples").
+		Preload("Awards").
+		Preload("ProfileLanguages.Language").
+		Preload("ProfileLanguages.LanguageProficiency").
+		Preload("SocialMediaAccounts.SocialMedia")
+}
+
+// sharedWith reports whether the profile's visibility lets the user see it, user is nil for
+// visitors who aren't signed in
+func sharedWith(profile models.Profile, user *models.User) bool {
+	switch profile.Visibility {
+	case models.ProfilePublic:
+		return true
+	case models.ProfileAppliedEmployers:
+		// applying is what shares it, the company's team sees it without the candidate
+		// doing anything else
+		return user != nil && policy.Check(*user, "candidate:read", policy.Applicant(profile.UserID)) == nil
+	}
+	return false
+}
+
+// viewFor returns what the user may see of the profile, the whole record for its owner and
+// admins and the shared view for whoever the profile is shared with
+func viewFor(profile models.Profile, user *models.User) (interface{}, error) {
+	if user != nil && policy.Check(*user, "profile:read", policy.Own(profile.UserID)) == nil {
+		return &ProfileResponse{Profile: &profile, Completeness: completeness.Score(profile)}, nil
+	}
+	if !sharedWith(profile, user) {
+		return nil, fmt.Errorf("error: you don't have access to this resource")
+	}
+	view := newProfileView(profile)
+	return &view, nil
+}
+
+// newProfileView leaves out the sections the owner hid, contact covers the resume too since
+// resumes carry the same details. Completeness is scored before anything is left out
+func newProfileView(profile models.Profile) ProfileView {
+	view := ProfileView{
+		ID:                  profile.ID,
+		Slug:                profile.Slug,
+		Name:                profile.User.Name,
+		Picture:             profile.User.Picture,
+		Bio:                 profile.Bio,
+		Skills:              profile.Skills,
+		Gender:              profile.Gender.Name,
+		Educations:          profile.Educations,
+		Internships:         profile.InternShipExperiences,
+		Projects:            profile.ProjectsExperiences,
+		WorkSamples:         profile.WorkSamples,
+		Awards:              profile.Awards,
+		Languages:           profile.ProfileLanguages,
+		SocialMediaAccounts: profile.SocialMediaAccounts,
+		Completeness:        completeness.Score(profile).Score,
+		UpdatedAt:           profile.UpdatedAt,
+	}
+
+	if profile.HideSalary {
+		view.HiddenSections = append(view.HiddenSections, salarySection)
+	} else {
+		view.CurrentSalary = &profile.CurrentSalary
+		view.CurrentSalaryCurrency = profile.CurrentSalaryCurrency.Name
+		view.ExpectedSalary = &profile.ExpectedSalary
+		view.ExpectedSalaryCurrency = profile.ExpectedSalaryCurrency.Name
+	}
+
+	if profile.HideContact {
+		view.HiddenSections = append(view.HiddenSections, contactSection)
+	} else {
+		view.Email = profile.User.Email
+		view.MobileNumber = profile.User.MobileNumber
+		view.ResumeID = profile.ResumeID
+	}
+	return view
+}
+
+// PublicProfile finds a profile by its slug for visitors who aren't signed in, profiles
+// that aren't public are reported as missing so the link doesn't confirm they exist
+func PublicProfile(slug string) (*ProfileView, error) {
+	var profile models.Profile
+	if err := withSections(database).
+		Where("user_id IN (?)", database.Model(&models.User{}).Select("id")).
+		First(&profile, "slug = ?", slug).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, err
+	}
+	if !sharedWith(profile, nil) {
+		return nil, ErrProfileNotFound
+	}
+	view := newProfileView(profile)
+	return &view, nil
+}
+
+// ViewProfile is the shared view of a profile for the user, its owner and admins get every
+// section whatever the owner hid from others
+func ViewProfile(ID uuid.UUID, user models.User) (*ProfileView, error) {
+	var profile models.Profile
+	if err := withSections(database).First(&profile, "id = ?", ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, err
+	}
+	if policy.Check(user, "profile:read", policy.Own(profile.UserID)) == nil {
+		profile.HideSalary, profile.HideContact = false, false
+	} else if !sharedWith(profile, &user) {
+		return nil, policy.ErrForbidden
+	}
+	view := newProfileView(profile)
+	return &view, nil
+}
+
+// ApplicantProfile is the profile of an applicant as the user sees it, the caller checks the
+// user may see the application it came from
+func ApplicantProfile(applicantID uuid.UUID, user models.User) (interface{}, error) {
+	var profile models.Profile
+	if err := withSections(database).First(&profile, "user_id = ?", applicantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("the applicant hasn't made a profile yet")
+		}
+		return nil, err
+	}
+	return viewFor(profile, &user)
+}
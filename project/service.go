@@ -4,19 +4,21 @@ package project
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/completeness"
+	"job_board/matching"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func checkProfile(user models.User) bool {
@@ -48,29 +50,13 @@ func createProject(project models.ProjectsExperience, user models.User) (*models
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(project.ProfileID)
+	completeness.Queue(project.ProfileID)
+
 	return &project, nil
 }
 
-func getProject(filter Search, pageSize string, pageNumber string) ([]models.ProjectsExperience, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getProject(filter Search, params pagination.Params) (*pagination.Page[models.ProjectsExperience], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.ProjectsExperience{})
 
@@ -96,25 +82,13 @@ func getProject(filter Search, pageSize string, pageNumber string) ([]models.Pro
 		db = db.Where("end_date <= ?", endDateStr)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.ProjectsExperience
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.ProjectsExperience](db, params)
+	if err != nil {
 		log.Println("Error finding project:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleProject(ID uuid.UUID, user models.User) (*models.ProjectsExperience, error) {
@@ -128,7 +102,7 @@ func getSingleProject(ID uuid.UUID, user models.User) (*models.ProjectsExperienc
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole && record.ProfileID != user.Profile.ID {
+	if err := policy.Check(user, "profile:read", policy.OwnProfile(record.ProfileID)); err != nil {
 		return nil, fmt.Errorf("you don't have permission to view this record")
 	}
 
@@ -149,13 +123,9 @@ func updateProject(ID uuid.UUID, user models.User, updates Request) (*models.Pro
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole {
-		if profile := checkProfile(user); profile {
-			return nil, fmt.Errorf("you don't have a profile")
-		}
-		if existingRecord.ProfileID != user.Profile.ID {
-			return nil, fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:update", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
 	}
 
 	// Update the record with the provided updates
@@ -167,6 +137,9 @@ func updateProject(ID uuid.UUID, user models.User, updates Request) (*models.Pro
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
+
 	return &existingRecord, nil
 }
 
@@ -184,10 +157,9 @@ func deleteSingleProject(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName == models.UserRole {
-		if existingRecord.ProfileID != user.Profile.ID {
-			return fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:delete", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.ProjectsExperience{}, "id = ?", ID)
@@ -206,5 +178,8 @@ func deleteSingleProject(ID uuid.UUID, user models.User) error {
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
 	return nil
 }
\ No newline at end of file
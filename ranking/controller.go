@@ -8,6 +8,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 /* profile language segment  starts*/
@@ -43,9 +44,16 @@ func create(ctx *gin.Context) {
 
 func get(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	rankings, total, page, perPage, err := getAcademicRanking(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	rankings, err := getAcademicRanking(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -57,12 +65,8 @@ func get(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched rankings",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     rankings,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       rankings,
+		Links:      rankings.Links(ctx),
 	})
 }
 
@@ -8,14 +8,12 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-
 func RankingRoutes(superRoute *gin.RouterGroup) {
 	rankingRouter := superRoute.Group("/rankings")
 
-	rankingRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), create)
+	rankingRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), create)
 	rankingRouter.GET("/", get)
 	rankingRouter.GET("/:id", getSingle)
-	rankingRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), update)
-	rankingRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), delete)
+	rankingRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), update)
+	rankingRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), delete)
 }
@@ -5,19 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func createAcademicRanking(academicRanking models.AcademicRanking) (*models.AcademicRanking, error) {
@@ -41,26 +40,7 @@ func createAcademicRanking(academicRanking models.AcademicRanking) (*models.Acad
 	return &academicRanking, nil
 }
 
-func getAcademicRanking(name string, pageSize string, pageNumber string) ([]models.AcademicRanking, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getAcademicRanking(name string, params pagination.Params) (*pagination.Page[models.AcademicRanking], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.AcademicRanking{})
 
@@ -69,25 +49,13 @@ func getAcademicRanking(name string, pageSize string, pageNumber string) ([]mode
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.AcademicRanking
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.AcademicRanking](db, params)
+	if err != nil {
 		log.Println("Error finding AcademicRanking:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleAcademicRanking(ID uuid.UUID) (*models.AcademicRanking, error) {
@@ -4,45 +4,26 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-redis/redis/v8"
-	"github.com/joho/godotenv"
-	"log"
-	"os"
-	"strconv"
 	"time"
 	"encoding/json"
+
+	"job_board/config"
 )
 
 var client *redis.Client
 var ctx = context.Background()
 
 
-func init() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Error loading .env file")
-	}
-
-	redisPassword := os.Getenv("REDIS_PASSWORD")
-	redisDB := os.Getenv("REDIS_DB")
-	redisHost := os.Getenv("REDIS_HOST")
-	fmt.Println(redisPassword, redisDB, redisHost)
-
-	if  redisDB == "" || redisHost == "" {
-		panic("Error loading redis password, db or host")
-	}
-
-	hostNumber, err := strconv.Atoi(redisDB)
-	if err != nil {
-		panic("Error converting redis host to int")
-	}
+// Connect opens the redis client and checks that it is reachable
+func Connect(cfg config.Redis) error {
 	client = redis.NewClient(&redis.Options{
-		Addr:     redisHost,
-		Password: redisPassword, 
-		DB:       hostNumber,    
+		Addr:     cfg.Host,
+		Password: cfg.Password,
+		DB:       cfg.DB,
 	})
-
-	Test()
+	return Test()
 }
+
 // exposes client to be used by other packages
 func GetClient() *redis.Client {
 	return client
@@ -50,14 +31,11 @@ func GetClient() *redis.Client {
 
 
 // Test function to test connection to redis
-func Test() {
-	ping, err := client.Ping(ctx).Result()
-	if err != nil {
-		fmt.Println(err.Error())
-		panic(err)
-		return
+func Test() error {
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to reach redis: %w", err)
 	}
-	fmt.Println(ping)
+	return nil
 }
 
 // Store function to store a value in redis
@@ -0,0 +1,321 @@
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnsupportedType = errors.New("only pdf, docx and plain text resumes can be imported")
+	ErrNoText          = errors.New("no text could be read from the resume, scanned documents aren't supported")
+)
+
+// ExtractText turns an uploaded resume into plain text with one line per paragraph
+func ExtractText(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	var (
+		text string
+		err  error
+	)
+	switch {
+	case contentType == "application/pdf":
+		text, err = pdfText(data)
+	case contentType == "application/zip":
+		text, err = docxText(data)
+	case strings.HasPrefix(contentType, "text/plain") && utf8.Valid(data):
+		text = string(data)
+	default:
+		return "", ErrUnsupportedType
+	}
+	if err != nil {
+		return "", err
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if strings.TrimSpace(text) == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+// docxText reads the paragraphs out of word/document.xml
+func docxText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", ErrUnsupportedType
+	}
+	for _, f := range archive.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+
+		var b strings.Builder
+		decoder := xml.NewDecoder(rc)
+		inText := false
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				switch t.Name.Local {
+				case "t":
+					inText = true
+				case "tab":
+					b.WriteString("\t")
+				case "br", "cr":
+					b.WriteString("\n")
+				}
+			case xml.EndElement:
+				switch t.Name.Local {
+				case "t":
+					inText = false
+				case "p":
+					b.WriteString("\n")
+				}
+			case xml.CharData:
+				if inText {
+					b.Write(t)
+				}
+			}
+		}
+		return b.String(), nil
+	}
+	// a zip without a word document is something else entirely
+	return "", ErrUnsupportedType
+}
+
+var streamPattern = regexp.MustCompile(`(?s)<<(.*?)>>\s*stream\r?\n`)
+
+// pdfText pulls the text operators out of every content stream. It only understands fonts
+// with a plain byte encoding, which covers what word processors export, not scanned or
+// subset CID documents
+func pdfText(data []byte) (string, error) {
+	var b strings.Builder
+	for _, loc := range streamPattern.FindAllSubmatchIndex(data, -1) {
+		dict := data[loc[2]:loc[3]]
+		start := loc[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		raw := data[start : start+end]
+
+		var content []byte
+		switch {
+		case bytes.Contains(dict, []byte("/FlateDecode")):
+			r, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			// streams are often cut short of their checksum, keep whatever inflated
+			content, _ = io.ReadAll(r)
+			r.Close()
+		case bytes.Contains(dict, []byte("/Filter")):
+			// images and other encodings carry no text we can read
+			continue
+		default:
+			content = raw
+		}
+		if bytes.Contains(content, []byte("BT")) {
+			b.WriteString(contentText(content))
+		}
+	}
+	return b.String(), nil
+}
+
+// contentText walks a content stream keeping the string operands of the text operators
+// and starting a new line whenever the text moves down the page
+func contentText(content []byte) string {
+	var (
+		b        strings.Builder
+		operands []interface{}
+		array    []interface{}
+		inArray  bool
+	)
+	push := func(v interface{}) {
+		if inArray {
+			array = append(array, v)
+		} else {
+			operands = append(operands, v)
+		}
+	}
+	newline := func() {
+		if s := b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '(':
+			s, n := literalString(content[i:])
+			push(s)
+			i += n
+		case c == '<' && i+1 < len(content) && content[i+1] != '<':
+			end := bytes.IndexByte(content[i:], '>')
+			if end < 0 {
+				return b.String()
+			}
+			decoded, _ := hex.DecodeString(strings.Map(func(r rune) rune {
+				if strings.ContainsRune(" \t\r\n", r) {
+					return -1
+				}
+				return r
+			}, string(content[i+1:i+end])))
+			push(string(decoded))
+			i += end + 1
+		case c == '[':
+			inArray, array = true, nil
+			i++
+		case c == ']':
+			inArray = false
+			operands = append(operands, array)
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case isSpace(c) || c == '<' || c == '>' || c == '{' || c == '}':
+			i++
+		default:
+			j := i
+			for j < len(content) && !isSpace(content[j]) && !strings.ContainsRune("()<>[]{}%", rune(content[j])) {
+				j++
+			}
+			if j == i {
+				j++
+			}
+			word := string(content[i:j])
+			i = j
+			if word[0] == '/' {
+				push(nil)
+				continue
+			}
+			if n, err := strconv.ParseFloat(word, 64); err == nil {
+				push(n)
+				continue
+			}
+
+			switch word {
+			case "Tj":
+				writeOperand(&b, operands)
+			case "'", "\"":
+				newline()
+				writeOperand(&b, operands)
+			case "TJ":
+				if len(operands) > 0 {
+					if parts, ok := operands[len(operands)-1].([]interface{}); ok {
+						for _, part := range parts {
+							switch v := part.(type) {
+							case string:
+								b.WriteString(v)
+							case float64:
+								// a large negative kern is how most generators draw a space
+								if v < -200 {
+									b.WriteString(" ")
+								}
+							}
+						}
+					}
+				}
+			case "Td", "TD":
+				if len(operands) >= 2 {
+					if y, ok := operands[len(operands)-1].(float64); ok && y != 0 {
+						newline()
+					} else {
+						b.WriteString(" ")
+					}
+				}
+			case "T*", "Tm", "ET":
+				newline()
+			}
+			operands = operands[:0]
+		}
+	}
+	return b.String()
+}
+
+func writeOperand(b *strings.Builder, operands []interface{}) {
+	if len(operands) == 0 {
+		return
+	}
+	if s, ok := operands[len(operands)-1].(string); ok {
+		b.WriteString(s)
+	}
+}
+
+// literalString decodes a (...) string, returning it with the number of bytes consumed
+func literalString(data []byte) (string, int) {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '(':
+			if depth > 0 {
+				b.WriteByte(c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return b.String(), i + 1
+			}
+			b.WriteByte(c)
+		case '\\':
+			i++
+			if i >= len(data) {
+				return b.String(), i
+			}
+			switch e := data[i]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r', '\n':
+			case 't':
+				b.WriteByte('\t')
+			case 'b', 'f':
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					n := 0
+					for n < 3 && i < len(data) && data[i] >= '0' && data[i] <= '7' {
+						v = v*8 + int(data[i]-'0')
+						i++
+						n++
+					}
+					i--
+					b.WriteByte(byte(v))
+				} else {
+					b.WriteByte(e)
+				}
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), len(data)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
@@ -0,0 +1,49 @@
+package resume
+
+import (
+	"job_board/award"
+	"job_board/education"
+	"job_board/internship"
+	"job_board/language"
+	"job_board/project"
+)
+
+type Contact struct {
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Phone string   `json:"phone"`
+	Links []string `json:"links"`
+}
+
+// EducationDraft is an education request with the degree as written, degree_id and
+// academic_ranking_id are only filled when a lookup matched
+type EducationDraft struct {
+	education.Request
+	Degree string `json:"degree"`
+}
+
+// LanguageDraft is a profile language request with the level as written
+type LanguageDraft struct {
+	language.Request
+	Proficiency string `json:"proficiency"`
+}
+
+// Draft is what the parser understood, the client corrects it and posts it back as an ImportRequest
+type Draft struct {
+	Contact     Contact              `json:"contact"`
+	Skills      []string             `json:"skills"`
+	Educations  []EducationDraft     `json:"educations"`
+	Internships []internship.Request `json:"internships"`
+	Projects    []project.Request    `json:"projects"`
+	Awards      []award.Request      `json:"awards"`
+	Languages   []LanguageDraft      `json:"languages"`
+}
+
+type ImportRequest struct {
+	Skills      []string             `json:"skills" binding:"omitempty"`
+	Educations  []education.Request  `json:"educations" binding:"omitempty,dive"`
+	Internships []internship.Request `json:"internships" binding:"omitempty,dive"`
+	Projects    []project.Request    `json:"projects" binding:"omitempty,dive"`
+	Awards      []award.Request      `json:"awards" binding:"omitempty,dive"`
+	Languages   []language.Request   `json:"languages" binding:"omitempty,dive"`
+}
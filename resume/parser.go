@@ -0,0 +1,442 @@
+package resume
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"job_board/award"
+	"job_board/internship"
+	"job_board/project"
+)
+
+type section string
+
+const (
+	header      section = "header"
+	educations  section = "education"
+	experiences section = "experience"
+	projects    section = "projects"
+	skills      section = "skills"
+	languages   section = "languages"
+	awards      section = "awards"
+	ignored     section = "ignored"
+)
+
+// headings maps the titles resumes commonly use to the section they start
+var headings = map[string]section{
+	"education":                 educations,
+	"academic background":       educations,
+	"academic qualifications":   educations,
+	"qualifications":            educations,
+	"experience":                experiences,
+	"work experience":           experiences,
+	"professional experience":   experiences,
+	"employment":                experiences,
+	"employment history":        experiences,
+	"work history":              experiences,
+	"internships":               experiences,
+	"internship":                experiences,
+	"internship experience":     experiences,
+	"projects":                  projects,
+	"personal projects":         projects,
+	"academic projects":         projects,
+	"skills":                    skills,
+	"technical skills":          skills,
+	"core competencies":         skills,
+	"key skills":                skills,
+	"tools":                     skills,
+	"technologies":              skills,
+	"languages":                 languages,
+	"spoken languages":          languages,
+	"awards":                    awards,
+	"honors":                    awards,
+	"honours":                   awards,
+	"honors and awards":         awards,
+	"awards and honors":         awards,
+	"achievements":              awards,
+	"certifications":            awards,
+	"summary":                   ignored,
+	"profile":                   ignored,
+	"about me":                  ignored,
+	"objective":                 ignored,
+	"references":                ignored,
+	"interests":                 ignored,
+	"hobbies":                   ignored,
+	"contact":                   header,
+	"contact information":       header,
+	"personal information":      header,
+	"personal details":          header,
+	"volunteer experience":      experiences,
+	"leadership experience":     experiences,
+	"research experience":       experiences,
+	"education and training":    educations,
+	"certifications and awards": awards,
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`)
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s().-]{7,}\d`)
+	linkPattern  = regexp.MustCompile(`(?i)\b((https?://|www\.)[^\s,;|]+|(linkedin\.com|github\.com|gitlab\.com)/[^\s,;|]+)`)
+	yearPattern  = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+	bullet       = regexp.MustCompile(`^[\s•·▪●◦‣\-*–]+`)
+	// dates are written as "Jan 2020", "January 2020", "01/2020", "2020-01" or just "2020"
+	datePattern    = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?\s+((19|20)\d{2})\b|\b(\d{1,2})[/.](19|20)(\d{2})\b|\b((19|20)\d{2})-(\d{2})\b|\b((19|20)\d{2})\b`)
+	presentPattern = regexp.MustCompile(`(?i)\b(present|current|now|ongoing|to date)\b`)
+	degreePattern  = regexp.MustCompile(`(?i)\b(bachelor|master|doctor|ph\.?\s?d|mba|b\.?\s?sc|m\.?\s?sc|b\.?\s?a\b|m\.?\s?a\b|b\.?\s?eng|m\.?\s?eng|b\.?\s?tech|m\.?\s?tech|diploma|associate|certificate|hnd|ond|high school|secondary school)`)
+	listSeparator  = regexp.MustCompile(`[,;|•·▪●]|\s/\s`)
+	schoolPattern  = regexp.MustCompile(`(?i)\b(university|college|institute|polytechnic|school|academy|universit)`)
+	studyPattern   = regexp.MustCompile(`(?i)\b(?:in|of)\s+([A-Za-z&,' ]+?)(?:\s*[,|(–-]|\s+\d|$)`)
+)
+
+var months = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// Parse splits resume text into sections and guesses the records each one describes.
+// It never fails, anything it can't place is simply left out of the draft
+func Parse(text string) Draft {
+	draft := Draft{
+		Skills:      []string{},
+		Educations:  []EducationDraft{},
+		Internships: []internship.Request{},
+		Projects:    []project.Request{},
+		Awards:      []award.Request{},
+		Languages:   []LanguageDraft{},
+	}
+
+	sections := map[section][]string{}
+	current := header
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if s, ok := heading(line); ok {
+			current = s
+			continue
+		}
+		sections[current] = append(sections[current], line)
+	}
+
+	draft.Contact = parseContact(sections[header], text)
+	draft.Skills = splitList(sections[skills], true)
+	for _, block := range entries(sections[educations]) {
+		if entry, ok := parseEducation(block); ok {
+			draft.Educations = append(draft.Educations, entry)
+		}
+	}
+	for _, block := range entries(sections[experiences]) {
+		if entry, ok := parseExperience(block); ok {
+			draft.Internships = append(draft.Internships, entry)
+		}
+	}
+	for _, block := range entries(sections[projects]) {
+		if entry, ok := parseProject(block); ok {
+			draft.Projects = append(draft.Projects, entry)
+		}
+	}
+	for _, line := range nonEmpty(sections[awards]) {
+		draft.Awards = append(draft.Awards, parseAward(line))
+	}
+	for _, item := range splitList(sections[languages], false) {
+		draft.Languages = append(draft.Languages, parseLanguage(item))
+	}
+	return draft
+}
+
+// heading reports whether a line is a section title such as "WORK EXPERIENCE:"
+func heading(line string) (section, bool) {
+	key := strings.ToLower(strings.Trim(line, " :#*-_=|\t"))
+	key = strings.Join(strings.Fields(key), " ")
+	if key == "" || len(strings.Fields(key)) > 4 {
+		return "", false
+	}
+	key = strings.ReplaceAll(key, "&", "and")
+	s, ok := headings[key]
+	return s, ok
+}
+
+func parseContact(lines []string, text string) Contact {
+	contact := Contact{Links: []string{}}
+	contact.Email = emailPattern.FindString(text)
+	for _, line := range lines {
+		if contact.Phone == "" {
+			if phone := phonePattern.FindString(line); phone != "" && !yearRange(line) {
+				contact.Phone = strings.TrimSpace(phone)
+			}
+		}
+		for _, link := range linkPattern.FindAllString(line, -1) {
+			if !strings.Contains(link, "@") {
+				contact.Links = append(contact.Links, strings.TrimRight(link, ".)"))
+			}
+		}
+		// the name is the first line that isn't contact details
+		if contact.Name == "" && line != "" && !strings.ContainsAny(line, "@/:") &&
+			!strings.ContainsAny(line, "0123456789") && len(strings.Fields(line)) <= 5 {
+			contact.Name = line
+		}
+	}
+	return contact
+}
+
+// entries groups the lines of a section into one block per record, a record ends at a
+// blank line or when a second line carrying dates shows up
+func entries(lines []string) [][]string {
+	var (
+		blocks  [][]string
+		block   []string
+		hasDate bool
+	)
+	flush := func() {
+		if len(block) > 0 {
+			blocks = append(blocks, block)
+		}
+		block, hasDate = nil, false
+	}
+	for _, line := range lines {
+		if line == "" {
+			flush()
+			continue
+		}
+		dated := datePattern.MatchString(line)
+		if dated && hasDate && !bullet.MatchString(line) {
+			// the title usually sits on the line above the dates of the next record
+			last := block[len(block)-1]
+			if len(block) > 1 && !datePattern.MatchString(last) && !bullet.MatchString(last) {
+				block = block[:len(block)-1]
+				flush()
+				block = append(block, last)
+			} else {
+				flush()
+			}
+		}
+		block = append(block, line)
+		hasDate = hasDate || dated
+	}
+	flush()
+	return blocks
+}
+
+// dateRange finds the start and end of a record, end is nil for a single date and
+// current is true for "2020 - Present"
+func dateRange(lines []string) (start string, end *string, current bool) {
+	for _, line := range lines {
+		found := datePattern.FindAllStringSubmatch(line, 2)
+		if len(found) == 0 {
+			continue
+		}
+		start = date(found[0])
+		if len(found) > 1 {
+			e := date(found[1])
+			end = &e
+		} else if presentPattern.MatchString(line) {
+			current = true
+		}
+		return start, end, current
+	}
+	return "", nil, false
+}
+
+// date formats one datePattern match the way the profile endpoints expect
+func date(match []string) string {
+	year, month := 0, time.January
+	switch {
+	case match[1] != "":
+		year, _ = strconv.Atoi(match[2])
+		month = months[strings.ToLower(match[1])[:3]]
+	case match[4] != "":
+		year, _ = strconv.Atoi(match[5] + match[6])
+		m, _ := strconv.Atoi(match[4])
+		if m >= 1 && m <= 12 {
+			month = time.Month(m)
+		}
+	case match[7] != "":
+		year, _ = strconv.Atoi(match[7])
+		m, _ := strconv.Atoi(match[9])
+		if m >= 1 && m <= 12 {
+			month = time.Month(m)
+		}
+	default:
+		year, _ = strconv.Atoi(match[10])
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+}
+
+func yearRange(line string) bool {
+	return len(yearPattern.FindAllString(line, -1)) > 1
+}
+
+// withoutDates drops the dates and separators left around them
+func withoutDates(line string) string {
+	line = datePattern.ReplaceAllString(line, "")
+	line = presentPattern.ReplaceAllString(line, "")
+	return strings.Trim(line, " \t,|–—-()")
+}
+
+func parseEducation(block []string) (EducationDraft, bool) {
+	var entry EducationDraft
+	start, end, current := dateRange(block)
+	entry.StartDate, entry.EndDate = start, end
+	if current {
+		entry.IsCurrent = &current
+	}
+	if end != nil {
+		entry.GraduationYear, _ = strconv.Atoi((*end)[:4])
+	} else if start != "" && !current {
+		// a lone year on an education entry is usually the graduation
+		entry.GraduationYear, _ = strconv.Atoi(start[:4])
+	}
+
+	for _, line := range block {
+		text := withoutDates(bullet.ReplaceAllString(line, ""))
+		if text == "" {
+			continue
+		}
+		if entry.Degree == "" && degreePattern.MatchString(text) {
+			entry.Degree = text
+			// "BSc Computer Science, University of Lagos" carries both on one line
+			if parts := strings.Split(text, ","); len(parts) > 1 && schoolPattern.MatchString(parts[len(parts)-1]) {
+				entry.Degree = strings.TrimSpace(strings.Join(parts[:len(parts)-1], ","))
+				entry.InstitutionName = strings.TrimSpace(parts[len(parts)-1])
+			}
+			// the last "in"/"of" names the field, "Master of Science in Data Science"
+			for study := studyPattern.FindStringSubmatch(entry.Degree); study != nil; study = studyPattern.FindStringSubmatch(study[1]) {
+				entry.FieldOFStudy = strings.TrimSpace(study[1])
+			}
+			continue
+		}
+		if entry.InstitutionName == "" && (schoolPattern.MatchString(text) || entry.Degree == "") {
+			entry.InstitutionName = text
+		}
+	}
+	if entry.FieldOFStudy == "" && entry.Degree != "" {
+		entry.FieldOFStudy = strings.TrimSpace(degreePattern.ReplaceAllString(entry.Degree, ""))
+		entry.FieldOFStudy = strings.Trim(entry.FieldOFStudy, " .,-")
+	}
+	if entry.InstitutionName == "" && entry.Degree == "" {
+		return entry, false
+	}
+	return entry, true
+}
+
+// titleAndCompany splits "Backend Engineer at Acme", "Backend Engineer - Acme" or
+// "Acme | Backend Engineer" style lines
+func titleAndCompany(line string) (string, string) {
+	if parts := strings.SplitN(line, " at ", 2); len(parts) == 2 {
+		return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	}
+	for _, sep := range []string{" | ", " – ", " — ", " - ", ", "} {
+		if parts := strings.SplitN(line, sep, 2); len(parts) == 2 {
+			return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		}
+	}
+	return line, ""
+}
+
+func parseExperience(block []string) (internship.Request, bool) {
+	var entry internship.Request
+	start, end, current := dateRange(block)
+	entry.StartDate, entry.EndDate = start, end
+	if current {
+		entry.IsCurrent = &current
+	}
+
+	var description []string
+	for _, line := range block {
+		isBullet := bullet.MatchString(line)
+		text := withoutDates(bullet.ReplaceAllString(line, ""))
+		if text == "" {
+			continue
+		}
+		switch {
+		case entry.Title == "" && !isBullet:
+			entry.Title, entry.CompanyName = titleAndCompany(text)
+		case entry.CompanyName == "" && !isBullet:
+			entry.CompanyName = text
+		default:
+			description = append(description, text)
+		}
+	}
+	entry.Description = strings.Join(description, "\n")
+	return entry, entry.Title != ""
+}
+
+func parseProject(block []string) (project.Request, bool) {
+	var entry project.Request
+	entry.StartDate, entry.EndDate, _ = dateRange(block)
+
+	var description []string
+	for _, line := range block {
+		text := withoutDates(bullet.ReplaceAllString(line, ""))
+		if text == "" {
+			continue
+		}
+		if entry.ProjectName == "" {
+			entry.ProjectName, entry.Title = titleAndCompany(text)
+			continue
+		}
+		description = append(description, text)
+	}
+	if entry.Title == "" {
+		entry.Title = entry.ProjectName
+	}
+	entry.Description = strings.Join(description, "\n")
+	return entry, entry.ProjectName != ""
+}
+
+func parseAward(line string) award.Request {
+	entry := award.Request{Title: withoutDates(bullet.ReplaceAllString(line, ""))}
+	if year := yearPattern.FindString(line); year != "" {
+		entry.Year, _ = strconv.Atoi(year)
+	}
+	if title, description := titleAndCompany(entry.Title); description != "" {
+		entry.Title, entry.Description = title, description
+	}
+	return entry
+}
+
+// parseLanguage reads "French (Fluent)", "Spanish - native" or just "English"
+func parseLanguage(item string) LanguageDraft {
+	var entry LanguageDraft
+	name, level := item, ""
+	if i := strings.IndexAny(item, "(:-–"); i > 0 {
+		name = item[:i]
+		level = strings.Trim(item[i:], " ()-–:")
+	}
+	entry.Name = strings.TrimSpace(name)
+	entry.Proficiency = level
+	return entry
+}
+
+// splitList reads comma, pipe or bullet separated items from a section. With labels
+// set it drops "Frameworks:" style prefixes in front of them
+func splitList(lines []string, labels bool) []string {
+	seen := map[string]bool{}
+	items := []string{}
+	for _, line := range lines {
+		if i := strings.Index(line, ":"); labels && i > 0 && i < 30 {
+			line = line[i+1:]
+		}
+		for _, item := range listSeparator.Split(line, -1) {
+			item = strings.TrimSpace(bullet.ReplaceAllString(item, ""))
+			key := strings.ToLower(item)
+			if item == "" || len(item) > 40 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func nonEmpty(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if strings.TrimSpace(bullet.ReplaceAllString(line, "")) != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -0,0 +1,265 @@
+package resume
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const sampleResume = `Ada Okafor
+ada.okafor@example.com | +234 803 555 0199
+linkedin.com/in/adaokafor, https://github.com/adaok
+
+SUMMARY
+Backend engineer who likes boring, reliable systems.
+
+Work Experience
+Backend Engineer at Paystack
+Jan 2021 - Present
+- Built the settlement service in Go
+- Cut payout latency by 40%
+
+Software Intern - Andela
+06/2019 – 12/2019
+- Wrote integration tests
+
+Education
+BSc Computer Science, University of Lagos
+2014 - 2018
+
+Projects
+Ledger | Double entry bookkeeping library
+2020
+- Open source, 300 stars
+
+Skills
+Languages: Go, Python, SQL
+Tools: Docker | Kubernetes | go
+
+Languages
+English (Native), French - Intermediate
+
+Awards & Honors
+Best Graduating Student – Faculty of Science 2018
+`
+
+func TestParseSampleResume(t *testing.T) {
+	draft := Parse(sampleResume)
+
+	wantContact := Contact{
+		Name:  "Ada Okafor",
+		Email: "ada.okafor@example.com",
+		Phone: "+234 803 555 0199",
+		Links: []string{"linkedin.com/in/adaokafor", "https://github.com/adaok"},
+	}
+	if !reflect.DeepEqual(draft.Contact, wantContact) {
+		t.Errorf("contact %+v, want %+v", draft.Contact, wantContact)
+	}
+
+	// "go" is dropped as a repeat of "Go", labels in front of the lists are dropped too
+	wantSkills := []string{"Go", "Python", "SQL", "Docker", "Kubernetes"}
+	if !reflect.DeepEqual(draft.Skills, wantSkills) {
+		t.Errorf("skills %q, want %q", draft.Skills, wantSkills)
+	}
+
+	if len(draft.Internships) != 2 {
+		t.Fatalf("got %d experiences, want 2: %+v", len(draft.Internships), draft.Internships)
+	}
+	current := draft.Internships[0]
+	if current.Title != "Backend Engineer" || current.CompanyName != "Paystack" {
+		t.Errorf("first experience is %q at %q", current.Title, current.CompanyName)
+	}
+	if current.StartDate != "2021-01-01" || current.EndDate != nil || current.IsCurrent == nil || !*current.IsCurrent {
+		t.Errorf("first experience dates %s %v current %v", current.StartDate, current.EndDate, current.IsCurrent)
+	}
+	if current.Description != "Built the settlement service in Go\nCut payout latency by 40%" {
+		t.Errorf("first experience description %q", current.Description)
+	}
+	intern := draft.Internships[1]
+	if intern.Title != "Software Intern" || intern.CompanyName != "Andela" {
+		t.Errorf("second experience is %q at %q", intern.Title, intern.CompanyName)
+	}
+	if intern.StartDate != "2019-06-01" || intern.EndDate == nil || *intern.EndDate != "2019-12-01" {
+		t.Errorf("second experience dates %s %v", intern.StartDate, intern.EndDate)
+	}
+
+	if len(draft.Educations) != 1 {
+		t.Fatalf("got %d educations, want 1: %+v", len(draft.Educations), draft.Educations)
+	}
+	education := draft.Educations[0]
+	if education.Degree != "BSc Computer Science" || education.InstitutionName != "University of Lagos" {
+		t.Errorf("education is %q at %q", education.Degree, education.InstitutionName)
+	}
+	if education.FieldOFStudy != "Computer Science" || education.GraduationYear != 2018 {
+		t.Errorf("education field %q graduating %d", education.FieldOFStudy, education.GraduationYear)
+	}
+
+	if len(draft.Projects) != 1 {
+		t.Fatalf("got %d projects, want 1: %+v", len(draft.Projects), draft.Projects)
+	}
+	if p := draft.Projects[0]; p.ProjectName != "Ledger" || p.Title != "Double entry bookkeeping library" || p.StartDate != "2020-01-01" {
+		t.Errorf("project %+v", p)
+	}
+
+	wantLanguages := [][2]string{{"English", "Native"}, {"French", "Intermediate"}}
+	if len(draft.Languages) != len(wantLanguages) {
+		t.Fatalf("languages %+v", draft.Languages)
+	}
+	for i, want := range wantLanguages {
+		if draft.Languages[i].Name != want[0] || draft.Languages[i].Proficiency != want[1] {
+			t.Errorf("language %d is %q (%q), want %q (%q)", i, draft.Languages[i].Name, draft.Languages[i].Proficiency, want[0], want[1])
+		}
+	}
+
+	if len(draft.Awards) != 1 {
+		t.Fatalf("awards %+v", draft.Awards)
+	}
+	if a := draft.Awards[0]; a.Title != "Best Graduating Student" || a.Description != "Faculty of Science" || a.Year != 2018 {
+		t.Errorf("award %+v", a)
+	}
+}
+
+func TestParseNeverFails(t *testing.T) {
+	for _, text := range []string{"", "\n\n\n", "EDUCATION\n", "just one line", "Skills\n,,,;;|"} {
+		draft := Parse(text)
+		if draft.Skills == nil || draft.Educations == nil || draft.Internships == nil ||
+			draft.Projects == nil || draft.Awards == nil || draft.Languages == nil {
+			t.Errorf("Parse(%q) left a nil list: %+v", text, draft)
+		}
+	}
+}
+
+func TestHeading(t *testing.T) {
+	tests := []struct {
+		line string
+		want section
+		ok   bool
+	}{
+		{"EDUCATION", educations, true},
+		{"Work Experience:", experiences, true},
+		{"## Technical   Skills ##", skills, true},
+		{"Honors & Awards", awards, true},
+		{"Certifications", awards, true},
+		{"Summary", ignored, true},
+		{"Experience building reliable systems at scale", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := heading(tt.line)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("heading(%q) = %q, %v, want %q, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		line    string
+		start   string
+		end     *string
+		current bool
+	}{
+		{"Jan 2020 - Mar 2021", "2020-01-01", str("2021-03-01"), false},
+		{"September 2019 – Present", "2019-09-01", nil, true},
+		{"01/2018 - 06/2019", "2018-01-01", str("2019-06-01"), false},
+		{"2017-09 to 2018-07", "2017-09-01", str("2018-07-01"), false},
+		{"2016 - 2020", "2016-01-01", str("2020-01-01"), false},
+		{"2015", "2015-01-01", nil, false},
+		{"no dates here", "", nil, false},
+	}
+	for _, tt := range tests {
+		start, end, current := dateRange([]string{tt.line})
+		if start != tt.start || !reflect.DeepEqual(end, tt.end) || current != tt.current {
+			t.Errorf("dateRange(%q) = %q, %v, %v, want %q, %v, %v", tt.line, start, end, current, tt.start, tt.end, tt.current)
+		}
+	}
+}
+
+func TestTitleAndCompany(t *testing.T) {
+	tests := []struct{ line, title, company string }{
+		{"Backend Engineer at Acme", "Backend Engineer", "Acme"},
+		{"Backend Engineer - Acme", "Backend Engineer", "Acme"},
+		{"Acme | Backend Engineer", "Acme", "Backend Engineer"},
+		{"Backend Engineer", "Backend Engineer", ""},
+	}
+	for _, tt := range tests {
+		title, company := titleAndCompany(tt.line)
+		if title != tt.title || company != tt.company {
+			t.Errorf("titleAndCompany(%q) = %q, %q, want %q, %q", tt.line, title, company, tt.title, tt.company)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		lines  []string
+		labels bool
+		want   []string
+	}{
+		{[]string{"Go, Python; SQL"}, false, []string{"Go", "Python", "SQL"}},
+		{[]string{"Frameworks: Gin | GORM", "• Docker"}, true, []string{"Gin", "GORM", "Docker"}},
+		{[]string{"Frameworks: Gin"}, false, []string{"Frameworks: Gin"}},
+		{[]string{"go", "Go", "GO"}, false, []string{"go"}},
+		{[]string{"CI / CD"}, false, []string{"CI", "CD"}},
+	}
+	for _, tt := range tests {
+		if got := splitList(tt.lines, tt.labels); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitList(%q, %v) = %q, want %q", tt.lines, tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	text, err := ExtractText([]byte("Ada Okafor\r\nSkills\r\nGo\r\n"))
+	if err != nil || text != "Ada Okafor\nSkills\nGo\n" {
+		t.Errorf("plain text gave %q, %v", text, err)
+	}
+
+	var stream bytes.Buffer
+	w := zlib.NewWriter(&stream)
+	fmt.Fprint(w, "BT /F1 12 Tf 72 720 Td (Ada Okafor) Tj 0 -14 Td (Skills: Go) Tj ET")
+	w.Close()
+	pdf := fmt.Sprintf("%%PDF-1.4\n1 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream\nendobj\n%%%%EOF\n", stream.Len(), stream.Bytes())
+	text, err = ExtractText([]byte(pdf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if draft := Parse(text); draft.Contact.Name != "Ada Okafor" {
+		t.Errorf("pdf text %q parsed to name %q", text, draft.Contact.Name)
+	}
+
+	if _, err := ExtractText([]byte("\x89PNG\r\n\x1a\n")); err != ErrUnsupportedType {
+		t.Errorf("png gave %v, want ErrUnsupportedType", err)
+	}
+	if _, err := ExtractText([]byte("   \n  ")); err != ErrNoText {
+		t.Errorf("blank text gave %v, want ErrNoText", err)
+	}
+}
+
+func TestParseEducation(t *testing.T) {
+	tests := []struct {
+		block                 []string
+		degree, school, field string
+		graduation            int
+	}{
+		{[]string{"BSc Computer Science, University of Lagos", "2014 - 2018"}, "BSc Computer Science", "University of Lagos", "Computer Science", 2018},
+		{[]string{"Master of Science in Data Science", "Imperial College London", "2021"}, "Master of Science in Data Science", "Imperial College London", "Data Science", 2021},
+		{[]string{"Yaba College of Technology", "HND Electrical Engineering", "2015 - Present"}, "HND Electrical Engineering", "Yaba College of Technology", "Electrical Engineering", 0},
+	}
+	for _, tt := range tests {
+		entry, ok := parseEducation(tt.block)
+		if !ok {
+			t.Errorf("parseEducation(%q) found nothing", tt.block)
+			continue
+		}
+		if entry.Degree != tt.degree || entry.InstitutionName != tt.school || entry.FieldOFStudy != tt.field || entry.GraduationYear != tt.graduation {
+			t.Errorf("parseEducation(%q) = %q at %q in %q graduating %d", tt.block, entry.Degree, entry.InstitutionName, entry.FieldOFStudy, entry.GraduationYear)
+		}
+	}
+	if _, ok := parseEducation([]string{"2019"}); ok {
+		t.Error("a block with only dates made an education")
+	}
+}
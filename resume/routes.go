@@ -0,0 +1,17 @@
+package resume
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"job_board/jwt"
+	"job_board/middleware"
+	"job_board/models"
+)
+
+func ImportRoutes(superRoute *gin.RouterGroup) {
+	importRouter := superRoute.Group("/import")
+	importRouter.Use(jwt.Middleware(), middleware.PermissionMiddleware(models.ResumeImport))
+
+	importRouter.POST("/parse", parse)
+	importRouter.POST("/", confirm)
+}
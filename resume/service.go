@@ -0,0 +1,279 @@
+package resume
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"job_board/completeness"
+	"job_board/matching"
+	"job_board/models"
+)
+
+var database *gorm.DB
+
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
+}
+
+// lookup maps lower cased names of a reference table to their ids
+type lookup map[string]uuid.UUID
+
+func loadLookup(model interface{}) (lookup, error) {
+	var rows []struct {
+		ID   uuid.UUID
+		Name string
+	}
+	if err := database.Model(model).Select("id", "name").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	l := lookup{}
+	for _, row := range rows {
+		l[strings.ToLower(row.Name)] = row.ID
+	}
+	return l, nil
+}
+
+// find prefers an exact name, then the longest name the text contains, then a name
+// sharing the text's first word so "BSc Physics" still lands on "Bachelor" when nothing closer exists
+func (l lookup) find(text string) *uuid.UUID {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return nil
+	}
+	if id, ok := l[text]; ok {
+		return &id
+	}
+	best, bestLen := uuid.Nil, 0
+	for name, id := range l {
+		if (strings.Contains(text, name) || strings.Contains(name, text)) && len(name) > bestLen {
+			best, bestLen = id, len(name)
+		}
+	}
+	if best == uuid.Nil {
+		for name, id := range l {
+			word := strings.TrimSuffix(strings.Fields(name)[0], "'s")
+			if len(word) >= 4 && strings.Contains(text, word) {
+				best = id
+				break
+			}
+		}
+	}
+	if best == uuid.Nil {
+		return nil
+	}
+	return &best
+}
+
+// resolve fills the reference ids the draft can be matched to by name
+func resolve(draft *Draft) error {
+	degrees, err := loadLookup(&models.Degree{})
+	if err != nil {
+		return fmt.Errorf("error loading degrees: %w", err)
+	}
+	languages, err := loadLookup(&models.Language{})
+	if err != nil {
+		return fmt.Errorf("error loading languages: %w", err)
+	}
+	proficiencies, err := loadLookup(&models.LanguageProficiency{})
+	if err != nil {
+		return fmt.Errorf("error loading language proficiencies: %w", err)
+	}
+
+	for i := range draft.Educations {
+		if id := degrees.find(draft.Educations[i].Degree); id != nil {
+			draft.Educations[i].DegreeID = *id
+		}
+	}
+	for i := range draft.Languages {
+		if id := languages.find(draft.Languages[i].Name); id != nil {
+			draft.Languages[i].LanguageID = *id
+		}
+		if id := proficiencies.find(draft.Languages[i].Proficiency); id != nil {
+			draft.Languages[i].LanguageProficiencyID = *id
+		}
+	}
+	return nil
+}
+
+// endDate drops the zero time the request helpers return for a missing end date
+func endDate(end *time.Time) *time.Time {
+	if end == nil || end.IsZero() {
+		return nil
+	}
+	return end
+}
+
+// records turns a confirmed import into rows for the profile, checking dates the same
+// way the single record endpoints do
+func records(profileID uuid.UUID, req ImportRequest) (*models.Profile, error) {
+	profile := models.Profile{ID: profileID}
+
+	for i, r := range req.Educations {
+		if err := r.ValidateDatesAndIsCurrent(); err != nil {
+			return nil, fmt.Errorf("education %d: %w", i+1, err)
+		}
+		start, end, err := r.ValidateDates()
+		if err != nil {
+			return nil, fmt.Errorf("education %d: %w", i+1, err)
+		}
+		profile.Educations = append(profile.Educations, models.Education{
+			ProfileID:         profileID,
+			InstitutionName:   r.InstitutionName,
+			FieldOFStudy:      r.FieldOFStudy,
+			DegreeID:          r.DegreeID,
+			AcademicRankingID: r.AcademicRankingID,
+			GraduationYear:    r.GraduationYear,
+			StartDate:         *start,
+			EndDate:           endDate(end),
+			IsCurrent:         r.IsCurrent,
+		})
+	}
+
+	for i, r := range req.Internships {
+		if err := r.ValidateDatesAndIsCurrent(); err != nil {
+			return nil, fmt.Errorf("internship %d: %w", i+1, err)
+		}
+		start, end, err := r.ValidateDates()
+		if err != nil {
+			return nil, fmt.Errorf("internship %d: %w", i+1, err)
+		}
+		profile.InternShipExperiences = append(profile.InternShipExperiences, models.InternShipExperience{
+			ProfileID:   profileID,
+			CompanyName: r.CompanyName,
+			Title:       r.Title,
+			Description: r.Description,
+			StartDate:   *start,
+			EndDate:     endDate(end),
+			IsCurrent:   r.IsCurrent,
+		})
+	}
+
+	for i, r := range req.Projects {
+		start, end, err := r.ValidateDates()
+		if err != nil {
+			return nil, fmt.Errorf("project %d: %w", i+1, err)
+		}
+		profile.ProjectsExperiences = append(profile.ProjectsExperiences, models.ProjectsExperience{
+			ProfileID:   profileID,
+			ProjectName: r.ProjectName,
+			Title:       r.Title,
+			Description: r.Description,
+			StartDate:   *start,
+			EndDate:     endDate(end),
+		})
+	}
+
+	for _, r := range req.Awards {
+		profile.Awards = append(profile.Awards, models.Award{
+			ProfileID:   profileID,
+			Title:       r.Title,
+			Year:        r.Year,
+			Description: r.Description,
+		})
+	}
+
+	for _, r := range req.Languages {
+		profile.ProfileLanguages = append(profile.ProfileLanguages, models.ProfileLanguage{
+			ProfileID:             profileID,
+			Name:                  r.Name,
+			LanguageID:            r.LanguageID,
+			LanguageProficiencyID: r.LanguageProficiencyID,
+		})
+	}
+	return &profile, nil
+}
+
+// mergeSkills adds the imported skills the profile doesn't list yet
+func mergeSkills(existing []string, imported []string) []string {
+	seen := map[string]bool{}
+	merged := []string{}
+	for _, skill := range append(existing, imported...) {
+		skill = strings.TrimSpace(skill)
+		if skill == "" || seen[strings.ToLower(skill)] {
+			continue
+		}
+		seen[strings.ToLower(skill)] = true
+		merged = append(merged, skill)
+	}
+	return merged
+}
+
+// importProfile creates every confirmed record in one transaction, so a bad row leaves
+// the profile untouched
+func importProfile(user models.User, req ImportRequest) (*models.Profile, error) {
+	if user.Profile == nil {
+		return nil, fmt.Errorf("create your profile before importing a resume")
+	}
+
+	imported, err := records(user.Profile.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var profile models.Profile
+	if err := tx.First(&profile, "id = ?", user.Profile.ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	creates := []struct {
+		name string
+		rows interface{}
+		n    int
+	}{
+		{"educations", &imported.Educations, len(imported.Educations)},
+		{"internships", &imported.InternShipExperiences, len(imported.InternShipExperiences)},
+		{"projects", &imported.ProjectsExperiences, len(imported.ProjectsExperiences)},
+		{"awards", &imported.Awards, len(imported.Awards)},
+		{"languages", &imported.ProfileLanguages, len(imported.ProfileLanguages)},
+	}
+	for _, create := range creates {
+		if create.n == 0 {
+			continue
+		}
+		if err := tx.Create(create.rows).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error importing %s: %w", create.name, err)
+		}
+	}
+
+	if len(req.Skills) > 0 {
+		skills := mergeSkills(profile.Skills, req.Skills)
+		if err := tx.Model(&profile).Update("skills", pq.StringArray(skills)).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("error importing skills: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	matching.QueueProfile(profile.ID)
+	completeness.Queue(profile.ID)
+
+	if err := database.
+		Preload("Educations").
+		Preload("InternShipExperiences").
+		Preload("ProjectsExperiences").
+		Preload("WorkSamples").
+		Preload("Awards").
+		Preload("ProfileLanguages").
+		Preload("SocialMediaAccounts").
+		First(&profile, "id = ?", profile.ID).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
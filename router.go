@@ -8,15 +8,25 @@ import (
 
 	"encoding/gob"
 
+	"job_board/alert"
+	"job_board/audit"
+	"job_board/auditlog"
 	"job_board/auth"
+	"job_board/company"
 	"job_board/country"
+	"job_board/cv"
 	"job_board/degree"
+	"job_board/export"
 	"job_board/files"
 	"job_board/gender"
 	"job_board/job"
 	"job_board/language"
+	"job_board/matching"
 	"job_board/models"
+	"job_board/permission"
+	"job_board/profile"
 	"job_board/ranking"
+	"job_board/salazrycurrency"
 	"job_board/user"
 )
 
@@ -35,6 +45,7 @@ func AddRoutes(superRoute *gin.RouterGroup) {
 	//register session to be used any where
 	store := cookie.NewStore([]byte("secret"))
 	superRoute.Use(sessions.Sessions("auth-session", store))
+	superRoute.Use(audit.Middleware())
 
 	//register routes
 	auth.AuthRoutes(superRoute)
@@ -44,6 +55,16 @@ func AddRoutes(superRoute *gin.RouterGroup) {
 	ranking.RankingRoutes(superRoute)
 	language.LanguageRoutes(superRoute)
 	job.JobRoutes(superRoute)
+	company.CompanyRoutes(superRoute)
+	company.EmployerRoutes(superRoute)
+	profile.CandidateRoutes(superRoute)
+	cv.CVRoutes(superRoute)
 	files.FileRoutes(superRoute)
 	country.CountryRoutes(superRoute)
+	salazrycurrency.CurrencyRoutes(superRoute)
+	alert.AlertRoutes(superRoute)
+	matching.MatchRoutes(superRoute)
+	permission.PermissionRoutes(superRoute)
+	auditlog.AuditLogRoutes(superRoute)
+	export.ExportRoutes(superRoute)
 }
@@ -8,6 +8,7 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 /* profile language segment  starts*/
@@ -43,9 +44,16 @@ func create(ctx *gin.Context) {
 
 func get(ctx *gin.Context) {
 	name := ctx.Query("name")
-	pageSize := ctx.Query("page_size")
-	pageNumber := ctx.Query("page_number")
-	currencies, total, page, perPage, err := getSalary(name, pageSize, pageNumber)
+	params, err := pagination.FromContext(ctx, pagination.LookupSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	currencies, err := getSalary(name, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -57,12 +65,8 @@ func get(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched currencies",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     currencies,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       currencies,
+		Links:      currencies.Links(ctx),
 	})
 }
 
@@ -154,4 +158,96 @@ func delete(ctx *gin.Context) {
 	})
 }
 
-/* ProfileLanguage segment ends*/
\ No newline at end of file
+/* ProfileLanguage segment ends*/
+
+/* exchange rate segment starts*/
+
+func getRatesHandler(ctx *gin.Context) {
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	rates, err := getRates(params)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "successfully fetched exchange rates",
+		StatusCode: http.StatusOK,
+		Data:       rates,
+		Links:      rates.Links(ctx),
+	})
+}
+
+func setRateHandler(ctx *gin.Context) {
+	currencyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+
+	var req RateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	rate, err := setRate(currencyID, req.Rate)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "successfully updated exchange rate",
+		StatusCode: http.StatusOK,
+		Data:       rate,
+	})
+}
+
+func deleteRateHandler(ctx *gin.Context) {
+	currencyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	if err := deleteRate(currencyID); err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusInternalServerError,
+			Data:       nil,
+		})
+		return
+	}
+	helpers.CreateResponse(ctx, helpers.Response{
+		Message:    "successfully deleted exchange rate",
+		StatusCode: http.StatusOK,
+		Data:       nil,
+	})
+}
+
+/* exchange rate segment ends*/
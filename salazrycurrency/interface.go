@@ -3,4 +3,8 @@ package salazrycurrency
 
 type SalaryCurrency struct {
 	Name string `json:"name" binding:"required"`
-}
\ No newline at end of file
+}
+
+type RateRequest struct {
+	Rate float64 `json:"rate" binding:"required,gt=0"`
+}
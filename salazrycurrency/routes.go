@@ -8,13 +8,16 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-
 func CurrencyRoutes(superRoute *gin.RouterGroup) {
 	currencyRouter := superRoute.Group("/currencies")
-	currencyRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), create)
+	currencyRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), create)
 	currencyRouter.GET("/", get)
 	currencyRouter.GET("/:id", getSingle)
-	currencyRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), update)
-	currencyRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), delete)
-}
\ No newline at end of file
+	currencyRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), update)
+	currencyRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), delete)
+
+	// rates are what one unit of the currency is worth in the base currency
+	currencyRouter.GET("/rates", getRatesHandler)
+	currencyRouter.PUT("/:id/rate", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), setRateHandler)
+	currencyRouter.DELETE("/:id/rate", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), deleteRateHandler)
+}
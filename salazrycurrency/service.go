@@ -4,19 +4,19 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"gorm.io/gorm/clause"
 	"job_board/models"
+	"job_board/pagination"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func createSalary(salaryCurrency models.SalaryCurrency) (*models.SalaryCurrency, error) {
@@ -40,26 +40,7 @@ func createSalary(salaryCurrency models.SalaryCurrency) (*models.SalaryCurrency,
 	return &salaryCurrency, nil
 }
 
-func getSalary(name string, pageSize string, pageNumber string) ([]models.SalaryCurrency, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getSalary(name string, params pagination.Params) (*pagination.Page[models.SalaryCurrency], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.SalaryCurrency{})
 
@@ -68,25 +49,13 @@ func getSalary(name string, pageSize string, pageNumber string) ([]models.Salary
 		db = db.Where("name LIKE ?", "%"+name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var profiles []models.SalaryCurrency
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&profiles).Error; err != nil {
+	data, err := pagination.Find[models.SalaryCurrency](db, params)
+	if err != nil {
 		log.Println("Error finding SalaryCurrency:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return profiles, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleSalary(ID uuid.UUID) (*models.SalaryCurrency, error) {
@@ -129,3 +98,81 @@ func deleteSingle(SalaryCurrencyID uuid.UUID) error {
 	}
 	return result.Error
 }
+
+func getRates(params pagination.Params) (*pagination.Page[models.ExchangeRate], error) {
+	db := database.Model(&models.ExchangeRate{})
+
+	data, err := pagination.Find[models.ExchangeRate](db, params)
+	if err != nil {
+		log.Println("Error finding ExchangeRate:", err)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// setRate stores the rate of a currency and renormalises every job paid in it
+func setRate(currencyID uuid.UUID, rate float64) (*models.ExchangeRate, error) {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var currency models.SalaryCurrency
+	if err := tx.First(&currency, "id = ?", currencyID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	exchangeRate := models.ExchangeRate{CurrencyID: currencyID, Rate: rate}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&exchangeRate).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error saving exchange rate: %w", err)
+	}
+
+	if err := models.RefreshJobSalaries(tx, "salary_currency_id = ?", currencyID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("error normalising job salaries: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	exchangeRate.Currency = currency
+	return &exchangeRate, nil
+}
+
+func deleteRate(currencyID uuid.UUID) error {
+	tx := database.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result := tx.Delete(&models.ExchangeRate{}, "currency_id = ?", currencyID)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("rate already deleted")
+	}
+
+	if err := models.RefreshJobSalaries(tx, "salary_currency_id = ?", currencyID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error normalising job salaries: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"job_board/alert"
+	"job_board/audit"
+	"job_board/auditlog"
+	"job_board/auth"
+	"job_board/award"
+	"job_board/company"
+	"job_board/completeness"
+	"job_board/config"
+	"job_board/country"
+	"job_board/degree"
+	"job_board/education"
+	"job_board/erasure"
+	"job_board/export"
+	"job_board/files"
+	"job_board/gender"
+	"job_board/inbox"
+	"job_board/internship"
+	"job_board/job"
+	"job_board/jwt"
+	"job_board/language"
+	"job_board/matching"
+	"job_board/models"
+	"job_board/notifications"
+	"job_board/permission"
+	"job_board/policy"
+	"job_board/profile"
+	"job_board/project"
+	"job_board/ranking"
+	"job_board/resume"
+	"job_board/salazrycurrency"
+	"job_board/socialaccount"
+	"job_board/user"
+	"job_board/work"
+)
+
+// setup hands every package the connections and settings it needs, nothing reads the
+// environment on its own
+func setup(cfg *config.Config, database *gorm.DB) error {
+	models.Setup(database, cfg.Jobs)
+	if err := audit.Setup(database); err != nil {
+		return fmt.Errorf("error registering audit callbacks: %w", err)
+	}
+	if err := files.Setup(database, cfg.Storage); err != nil {
+		return err
+	}
+	if err := notifications.Setup(database, cfg.Notifications); err != nil {
+		return err
+	}
+
+	jwt.Setup(database, cfg.Auth)
+	auth.Setup(database, cfg.Auth, cfg.AppURL)
+	user.Setup(database, cfg.Admin)
+	company.Setup(database, cfg.AppURL, cfg.DNSResolver)
+	alert.Setup(database, cfg.AppURL)
+	job.Setup(database, cfg.Jobs)
+	erasure.Setup(database, cfg.Accounts)
+	export.Setup(database, cfg.ExportSigningKey)
+
+	for _, setup := range []func(*gorm.DB){
+		auditlog.Setup,
+		award.Setup,
+		completeness.Setup,
+		country.Setup,
+		degree.Setup,
+		education.Setup,
+		gender.Setup,
+		inbox.Setup,
+		internship.Setup,
+		language.Setup,
+		matching.Setup,
+		permission.Setup,
+		policy.Setup,
+		profile.Setup,
+		project.Setup,
+		ranking.Setup,
+		resume.Setup,
+		salazrycurrency.Setup,
+		socialaccount.Setup,
+		work.Setup,
+	} {
+		setup(database)
+	}
+	return nil
+}
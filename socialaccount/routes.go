@@ -8,14 +8,12 @@ import (
 	"job_board/models"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-
 func SocialRoutes(superRoute *gin.RouterGroup) {
 	socialRouter := superRoute.Group("/socials")
 
-	socialRouter.POST("/", jwt.Middleware(), middleware.RolesMiddleware(admins), CreateSocial)
+	socialRouter.POST("/", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), CreateSocial)
 	socialRouter.GET("/", GetSocial)
 	socialRouter.GET("/:id", GetSingleSocial)
-	socialRouter.PATCH("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), UpdateSocial)
-	socialRouter.DELETE("/:id", jwt.Middleware(), middleware.RolesMiddleware(admins), DeleteSocial)
+	socialRouter.PATCH("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), UpdateSocial)
+	socialRouter.DELETE("/:id", jwt.Middleware(), middleware.PermissionMiddleware(models.LookupManage), DeleteSocial)
 }
\ No newline at end of file
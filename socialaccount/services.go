@@ -3,19 +3,20 @@ package socialaccount
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/completeness"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func checkProfile(user models.User) bool {
@@ -47,29 +48,12 @@ func createSocial(Social models.SocialMediaAccount, user models.User) (*models.S
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	completeness.Queue(Social.ProfileID)
+
 	return &Social, nil
 }
 
-func getSocial(filter Search, pageSize string, pageNumber string) ([]models.SocialMediaAccount, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getSocial(filter Search, params pagination.Params) (*pagination.Page[models.SocialMediaAccount], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.SocialMediaAccount{})
 
@@ -81,25 +65,13 @@ func getSocial(filter Search, pageSize string, pageNumber string) ([]models.Soci
 		db = db.Where("social_media_id = ?", filter.SocialMediaID)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.SocialMediaAccount
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.SocialMediaAccount](db, params)
+	if err != nil {
 		log.Println("Error finding Social:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleSocial(ID uuid.UUID, user models.User) (*models.SocialMediaAccount, error) {
@@ -114,7 +86,7 @@ func getSingleSocial(ID uuid.UUID, user models.User) (*models.SocialMediaAccount
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole && record.ProfileID != user.Profile.ID {
+	if err := policy.Check(user, "profile:read", policy.OwnProfile(record.ProfileID)); err != nil {
 		return nil, fmt.Errorf("you don't have permission to view this record")
 	}
 
@@ -135,13 +107,9 @@ func updateSocial(ID uuid.UUID, user models.User, updates Request) (*models.Soci
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole {
-		if profile := checkProfile(user); profile {
-			return nil, fmt.Errorf("you don't have a profile")
-		}
-		if existingRecord.ProfileID != user.Profile.ID {
-			return nil, fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:update", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
 	}
 
 	// Update the record with the provided updates
@@ -170,10 +138,9 @@ func deleteSingleSocial(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName == models.UserRole {
-		if existingRecord.ProfileID != user.Profile.ID {
-			return fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:delete", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.SocialMediaAccount{}, "id = ?", ID)
@@ -192,6 +159,8 @@ func deleteSingleSocial(ID uuid.UUID, user models.User) error {
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+
+	completeness.Queue(existingRecord.ProfileID)
 	return nil
 }
 
@@ -217,26 +186,7 @@ func createProject(project models.SocialMedia) (*models.SocialMedia, error) {
 	return &project, nil
 }
 
-func getProject(filter SocialRequest, pageSize string, pageNumber string) ([]models.SocialMedia, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getProject(filter SocialRequest, params pagination.Params) (*pagination.Page[models.SocialMedia], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.SocialMedia{})
 
@@ -245,25 +195,13 @@ func getProject(filter SocialRequest, pageSize string, pageNumber string) ([]mod
 		db = db.Where("name LIKE ?", "%"+filter.Name+"%")
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting profiles:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.SocialMedia
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.SocialMedia](db, params)
+	if err != nil {
 		log.Println("Error finding project:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleProject(ID uuid.UUID) (*models.SocialMedia, error) {
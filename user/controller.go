@@ -8,12 +8,11 @@ import (
 	"github.com/google/uuid"
 
 	"io"
-	"log"
 	"net/http"
 
 	"job_board/helpers"
 	"job_board/models"
-	"job_board/notifications"
+	"job_board/pagination"
 )
 
 func User(ctx *gin.Context) {
@@ -56,8 +55,16 @@ func GetAllUsers(ctx *gin.Context) {
 		SubscriberID: ctx.Query("subscriber_id"),
 		MobileNumber: ctx.Query("mobile_number"),
 	}
-	//i just want to get the user data
-	users, total, page, perPage, err := GetUsers(query, ctx.Query("page"), ctx.Query("pageNumber"))
+	params, err := pagination.FromContext(ctx, userSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	users, err := GetUsers(query, params)
 
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
@@ -71,12 +78,8 @@ func GetAllUsers(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched users",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     users,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       users,
+		Links:      users.Links(ctx),
 	})
 }
 
@@ -103,7 +106,7 @@ func CreateAdmin(ctx *gin.Context) {
 		Email:        req.Email,
 	}
 
-	user, err := CreateAdminUser(newUser)
+	user, err := CreateAdminUser(ctx.Request.Context(), newUser)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -118,47 +121,6 @@ func CreateAdmin(ctx *gin.Context) {
 		StatusCode: http.StatusOK,
 		Data:       user,
 	})
-
-	go func() {
-		log.Print("Try creating subscriber ")
-		subscriber := notifications.Subscriber{
-			SubscriberID: user.SubscriberID,
-			Name:         user.Name,
-			Email:        user.Email,
-			Avatar:       user.Picture,
-			Data:         map[string]interface{}{},
-		}
-
-		if _, err := notifications.CreateSubscriber(subscriber); err != nil {
-			log.Printf("Failed to create subscriber: %v", err)
-			return
-		}
-		log.Printf("finished creating subscriber ID: %v", subscriber.SubscriberID)
-		log.Print("Send notification ")
-		notification := notifications.Trigger{
-			// Name:         user.Name,
-			// Email:        user.Email,
-			// Title:        "You have the power",
-			// SubscriberID: user.SubscriberID,
-			EventID:      "account-activation",
-			To: map[string]interface{}{
-				"subscriberId": user.SubscriberID,
-				"phone":        user.MobileNumber,
-				"email":        user.Email,
-			},
-			Data: map[string]interface{}{
-				"companyName": "Jobby",
-				"password":    req.Password,
-			},
-		}
-
-		if _, err := notifications.SendNotification(notification); err != nil {
-			log.Printf("Failed to send notification: %v", err)
-			return
-		}
-		log.Print("Successfully sent welcome notification to admin")
-
-	}()
 }
 
 func UpdateUser(ctx *gin.Context) {
@@ -201,7 +163,7 @@ func UpdateUser(ctx *gin.Context) {
 		})
 		return
 	}
-	newUser, err := UpdateSingleUser(user.ID, req)
+	newUser, err := UpdateSingleUser(ctx.Request.Context(), user.ID, req)
 	session := sessions.Default(ctx)
 	session.Set(newUser.ProviderID, newUser)
 	if err != nil {
@@ -240,7 +202,7 @@ func DeleteUser(ctx *gin.Context) {
 		})
 		return
 	}
-	err := DeleteSingleUser(user.ID)
+	err := DeleteSingleUser(ctx.Request.Context(), user.ID)
 
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
@@ -259,7 +221,7 @@ func DeleteUser(ctx *gin.Context) {
 
 func ReinStateAccount(ctx *gin.Context) {
 	userID := ctx.Param("id")
-	user, err := Reinstate(userID)
+	user, err := Reinstate(ctx.Request.Context(), userID)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
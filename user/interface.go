@@ -1,5 +1,17 @@
 package user
 
+import "job_board/pagination"
+
+var userSorts = pagination.Sorts{
+	Default: "created_at",
+	Fields: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"name":       "name",
+		"email":      "email",
+	},
+}
+
 type UserDetails struct {
 	Name         string `json:"name" binding:"omitempty"`
 	Email        string `json:"email" binding:"omitempty,email"`
@@ -7,6 +7,7 @@ import (
 	"job_board/middleware"
 	"job_board/models"
 	"job_board/award"
+	"job_board/cv"
 	"job_board/education"
 	"job_board/internship"
 	"job_board/project"
@@ -14,10 +15,16 @@ import (
 	"job_board/profile"
 	"job_board/language"
 	"job_board/socialaccount"
+	"job_board/resume"
+	"job_board/inbox"
+	"job_board/erasure"
 )
 
-var admins []models.RoleAllowed = []models.RoleAllowed{models.AdminRole, models.SuperAdminRole}
-var everybody []models.RoleAllowed = []models.RoleAllowed{models.UserRole, models.AdminRole, models.SuperAdminRole}
+var readProfiles = []models.Permission{models.ProfileReadOwn, models.ProfileReadAny}
+// posters see the profiles their applicants share with them
+var viewProfiles = []models.Permission{models.ProfileReadOwn, models.ProfileReadAny, models.CandidateReadCompany}
+var updateProfiles = []models.Permission{models.ProfileUpdateOwn, models.ProfileUpdateAny}
+var deleteProfiles = []models.Permission{models.ProfileDeleteOwn, models.ProfileDeleteAny}
 
 // New registers the routes and returns the router.
 func UserRoutes(superRoute *gin.RouterGroup) {
@@ -25,25 +32,29 @@ func UserRoutes(superRoute *gin.RouterGroup) {
 	userRouter := superRoute.Group("/users")
 	{
 		userRouter.Use(jwt.Middleware())
-		userRouter.GET("/", middleware.RolesMiddleware([]models.RoleAllowed{models.AdminRole, models.SuperAdminRole}), GetAllUsers)
-		userRouter.POST("/", middleware.RolesMiddleware([]models.RoleAllowed{models.AdminRole, models.SuperAdminRole}), CreateAdmin)
+		userRouter.GET("/", middleware.PermissionMiddleware(models.UserReadAny), GetAllUsers)
+		userRouter.POST("/", middleware.PermissionMiddleware(models.UserCreate), CreateAdmin)
 		userRouter.GET("/user", User)
 		userRouter.PATCH("/user", UpdateUser)
-		userRouter.PATCH("/user/:id", middleware.RolesMiddleware([]models.RoleAllowed{models.AdminRole, models.SuperAdminRole}), ReinStateAccount)
+		userRouter.PATCH("/user/:id", middleware.PermissionMiddleware(models.UserReinstate), ReinStateAccount)
 		userRouter.DELETE("/user", DeleteUser)
 
 		SetupProfileRoutes(userRouter.Group("/profiles"))
+		inbox.InboxRoutes(userRouter.Group("/notifications"))
+		erasure.ErasureRoutes(userRouter)
 	}
 
 }
 
 func SetupProfileRoutes(profileRouter *gin.RouterGroup) {
 	profileRouter.Use(jwt.Middleware())
-	profileRouter.POST("/", middleware.RolesMiddleware(everybody), profile.CreateProfile)
-	profileRouter.GET("/", middleware.RolesMiddleware(admins), profile.GetProfile)
-	profileRouter.GET("/:id", middleware.RolesMiddleware(everybody), profile.GetSingleProfile)
-	profileRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), profile.UpdateProfile)
-	profileRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), profile.DeleteProfile)
+	profileRouter.POST("/", middleware.PermissionMiddleware(models.ProfileCreate), profile.CreateProfile)
+	profileRouter.GET("/", middleware.PermissionMiddleware(models.ProfileReadAny), profile.GetProfile)
+	profileRouter.GET("/:id", middleware.PermissionMiddleware(viewProfiles...), profile.GetSingleProfile)
+	profileRouter.PATCH("/:id", middleware.PermissionMiddleware(updateProfiles...), profile.UpdateProfile)
+	profileRouter.PATCH("/:id/privacy", middleware.PermissionMiddleware(updateProfiles...), profile.UpdatePrivacy)
+	profileRouter.GET("/:id/cv", middleware.PermissionMiddleware(viewProfiles...), cv.ProfileCV)
+	profileRouter.DELETE("/:id", middleware.PermissionMiddleware(deleteProfiles...), profile.DeleteProfile)
 
 	/* subprofile routes */
 	SetupEducationRoutes(profileRouter.Group("/educations"))
@@ -53,68 +64,69 @@ func SetupProfileRoutes(profileRouter *gin.RouterGroup) {
 	SetupAwardRoutes(profileRouter.Group("/awards"))
 	SetupProfileLanguageRoutes(profileRouter.Group("/languages"))
 	SetupSocialMediaRoutes(profileRouter.Group("/socials"))
+	resume.ImportRoutes(profileRouter)
 
 }
 
 func SetupEducationRoutes(profileRouter *gin.RouterGroup) {
 	profileRouter.Use(jwt.Middleware())
-	profileRouter.POST("/", middleware.RolesMiddleware(everybody), education.CreateEducation)
-	profileRouter.GET("/", middleware.RolesMiddleware(admins), education.GetEducation)
-	profileRouter.GET("/:id", middleware.RolesMiddleware(everybody), education.GetSingleEducation)
-	profileRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), education.UpdateEducation)
-	profileRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), education.DeleteEducation)
+	profileRouter.POST("/", middleware.PermissionMiddleware(models.ProfileCreate), education.CreateEducation)
+	profileRouter.GET("/", middleware.PermissionMiddleware(models.ProfileReadAny), education.GetEducation)
+	profileRouter.GET("/:id", middleware.PermissionMiddleware(readProfiles...), education.GetSingleEducation)
+	profileRouter.PATCH("/:id", middleware.PermissionMiddleware(updateProfiles...), education.UpdateEducation)
+	profileRouter.DELETE("/:id", middleware.PermissionMiddleware(deleteProfiles...), education.DeleteEducation)
 }
 
 func SetupInternshipExperienceRoutes(profileRouter *gin.RouterGroup) {
 	profileRouter.Use(jwt.Middleware())
-	profileRouter.POST("/", middleware.RolesMiddleware(everybody), internship.CreateInternShipExperience)
-	profileRouter.GET("/", middleware.RolesMiddleware(admins), internship.GetInternShipExperience)
-	profileRouter.GET("/:id", middleware.RolesMiddleware(everybody), internship.GetInternShipExperience)
-	profileRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), internship.UpdateInternShipExperience)
-	profileRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), internship.DeleteInternShipExperience)
+	profileRouter.POST("/", middleware.PermissionMiddleware(models.ProfileCreate), internship.CreateInternShipExperience)
+	profileRouter.GET("/", middleware.PermissionMiddleware(models.ProfileReadAny), internship.GetInternShipExperience)
+	profileRouter.GET("/:id", middleware.PermissionMiddleware(readProfiles...), internship.GetInternShipExperience)
+	profileRouter.PATCH("/:id", middleware.PermissionMiddleware(updateProfiles...), internship.UpdateInternShipExperience)
+	profileRouter.DELETE("/:id", middleware.PermissionMiddleware(deleteProfiles...), internship.DeleteInternShipExperience)
 }
 
 func SetupProjectExperienceRoutes(profileRouter *gin.RouterGroup) {
 	profileRouter.Use(jwt.Middleware())
-	profileRouter.POST("/", middleware.RolesMiddleware(everybody), project.CreateProjectExperience)
-	profileRouter.GET("/", middleware.RolesMiddleware(admins), project.GetProjectExperience)
-	profileRouter.GET("/:id", middleware.RolesMiddleware(everybody), project.GetProjectExperience)
-	profileRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), project.UpdateProjectExperience)
-	profileRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), project.DeleteProjectExperience)
+	profileRouter.POST("/", middleware.PermissionMiddleware(models.ProfileCreate), project.CreateProjectExperience)
+	profileRouter.GET("/", middleware.PermissionMiddleware(models.ProfileReadAny), project.GetProjectExperience)
+	profileRouter.GET("/:id", middleware.PermissionMiddleware(readProfiles...), project.GetProjectExperience)
+	profileRouter.PATCH("/:id", middleware.PermissionMiddleware(updateProfiles...), project.UpdateProjectExperience)
+	profileRouter.DELETE("/:id", middleware.PermissionMiddleware(deleteProfiles...), project.DeleteProjectExperience)
 }
 
 func SetupWorkSampleRoutes(profileRouter *gin.RouterGroup) {
 	profileRouter.Use(jwt.Middleware())
-	profileRouter.POST("/", middleware.RolesMiddleware(everybody), work.CreateWorkSample)
-	profileRouter.GET("/", middleware.RolesMiddleware(admins), work.GetWorkSample)
-	profileRouter.GET("/:id", middleware.RolesMiddleware(everybody), work.GetWorkSample)
-	profileRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), work.UpdateWorkSample)
-	profileRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), work.DeleteWorkSample)
+	profileRouter.POST("/", middleware.PermissionMiddleware(models.ProfileCreate), work.CreateWorkSample)
+	profileRouter.GET("/", middleware.PermissionMiddleware(models.ProfileReadAny), work.GetWorkSample)
+	profileRouter.GET("/:id", middleware.PermissionMiddleware(readProfiles...), work.GetWorkSample)
+	profileRouter.PATCH("/:id", middleware.PermissionMiddleware(updateProfiles...), work.UpdateWorkSample)
+	profileRouter.DELETE("/:id", middleware.PermissionMiddleware(deleteProfiles...), work.DeleteWorkSample)
 }
 
 func SetupAwardRoutes(profileRouter *gin.RouterGroup) {
 	profileRouter.Use(jwt.Middleware())
-	profileRouter.POST("/", middleware.RolesMiddleware(everybody), award.CreateAward)
-	profileRouter.GET("/", middleware.RolesMiddleware(admins), award.GetAward)
-	profileRouter.GET("/:id", middleware.RolesMiddleware(everybody), award.GetSingleAward)
-	profileRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), award.UpdateAward)
-	profileRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), award.DeleteAward)
+	profileRouter.POST("/", middleware.PermissionMiddleware(models.ProfileCreate), award.CreateAward)
+	profileRouter.GET("/", middleware.PermissionMiddleware(models.ProfileReadAny), award.GetAward)
+	profileRouter.GET("/:id", middleware.PermissionMiddleware(readProfiles...), award.GetSingleAward)
+	profileRouter.PATCH("/:id", middleware.PermissionMiddleware(updateProfiles...), award.UpdateAward)
+	profileRouter.DELETE("/:id", middleware.PermissionMiddleware(deleteProfiles...), award.DeleteAward)
 }
 
 func SetupProfileLanguageRoutes(profileRouter *gin.RouterGroup) {
 	profileRouter.Use(jwt.Middleware())
-	profileRouter.POST("/", middleware.RolesMiddleware(everybody), language.CreateProfileLanguage)
-	profileRouter.GET("/", middleware.RolesMiddleware(admins), language.GetProfileLanguage)
-	profileRouter.GET("/:id", middleware.RolesMiddleware(everybody), language.GetProfileLanguage)
-	profileRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), language.UpdateProfileLanguage)
-	profileRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), language.DeleteProfileLanguage)
+	profileRouter.POST("/", middleware.PermissionMiddleware(models.ProfileCreate), language.CreateProfileLanguage)
+	profileRouter.GET("/", middleware.PermissionMiddleware(models.ProfileReadAny), language.GetProfileLanguage)
+	profileRouter.GET("/:id", middleware.PermissionMiddleware(readProfiles...), language.GetProfileLanguage)
+	profileRouter.PATCH("/:id", middleware.PermissionMiddleware(updateProfiles...), language.UpdateProfileLanguage)
+	profileRouter.DELETE("/:id", middleware.PermissionMiddleware(deleteProfiles...), language.DeleteProfileLanguage)
 }
 
 func SetupSocialMediaRoutes(profileRouter *gin.RouterGroup) {
 	profileRouter.Use(jwt.Middleware())
-	profileRouter.POST("/", middleware.RolesMiddleware(everybody), socialaccount.CreateSocialMedia)
-	profileRouter.GET("/", middleware.RolesMiddleware(admins), socialaccount.GetSocialMedia)
-	profileRouter.GET("/:id", middleware.RolesMiddleware(everybody), socialaccount.GetSocialMedia)
-	profileRouter.PATCH("/:id", middleware.RolesMiddleware(everybody), socialaccount.UpdateSocialMedia)
-	profileRouter.DELETE("/:id", middleware.RolesMiddleware(everybody), socialaccount.DeleteSocialMedia)
+	profileRouter.POST("/", middleware.PermissionMiddleware(models.ProfileCreate), socialaccount.CreateSocialMedia)
+	profileRouter.GET("/", middleware.PermissionMiddleware(models.ProfileReadAny), socialaccount.GetSocialMedia)
+	profileRouter.GET("/:id", middleware.PermissionMiddleware(readProfiles...), socialaccount.GetSocialMedia)
+	profileRouter.PATCH("/:id", middleware.PermissionMiddleware(updateProfiles...), socialaccount.UpdateSocialMedia)
+	profileRouter.DELETE("/:id", middleware.PermissionMiddleware(deleteProfiles...), socialaccount.DeleteSocialMedia)
 }
@@ -1,47 +1,36 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
-	"os"
-	"strconv"
-	"time"
 
 	"github.com/google/uuid"
-	"github.com/joho/godotenv"
 	"gorm.io/gorm"
 
-	"job_board/db"
+	"job_board/config"
 	"job_board/models"
+	"job_board/notifications"
+	"job_board/pagination"
 )
 
 var database *gorm.DB
-var adminName string
-var adminEmail string
-var adminPassword string
-var adminPicture string
-var adminMobileNumber string
-
-func init() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Error loading .env file")
-	}
-	adminName = os.Getenv("ADMIN_NAME")
-	adminEmail = os.Getenv("ADMIN_EMAIL")
-	adminPassword = os.Getenv("ADMIN_PASSWORD")
-	adminPicture = os.Getenv("ADMIN_PICTURE")
-	adminMobileNumber = os.Getenv("ADMIN_MOBILE_NUMBER")
-
-	if adminName == "" || adminEmail == "" || adminPassword == "" || adminPicture == "" || adminMobileNumber == "" {
-		log.Fatal("Error loading super admin details")
-	}
-	database = db.GetDB()
-	createAdmin()
+var admin config.Admin
+
+// Setup gives the package its database handle and the super admin to create on the first start
+func Setup(db *gorm.DB, cfg config.Admin) {
+	database = db
+	admin = cfg
 }
 
-func createAdmin() {
+// CreateSuperAdmin creates the super admin from the config the first time the api starts,
+// it runs once the migrations have created the users table
+func CreateSuperAdmin() {
+	if !admin.Enabled() {
+		log.Print("ADMIN_EMAIL isn't set, skipping the super admin")
+		return
+	}
 	log.Print("checking admin")
 
 	tx := database.Begin()
@@ -58,11 +47,11 @@ func createAdmin() {
 			subscriberID := uuid.NewString()
 			providerID := "superadmin|" + subscriberID
 			user := models.User{
-				Name:         adminName,
-				Email:        adminEmail,
-				Picture:      adminPicture,
-				Password:     adminPassword,
-				MobileNumber: &adminMobileNumber,
+				Name:         admin.Name,
+				Email:        admin.Email,
+				Picture:      admin.Picture,
+				Password:     admin.Password,
+				MobileNumber: &admin.MobileNumber,
 				RoleName:     models.SuperAdminRole, // Ensure the role is set to SuperAdminRole
 				ProviderID:   providerID,
 				SubscriberID: subscriberID,
@@ -109,27 +98,7 @@ func GetAltSingleUser(filter models.User) (*models.User, error) {
 	return &user, nil
 }
 
-func GetUsers(filter FilterDetails, pageNumber string, pageSize string) ([]models.User, int64, int, int, error) {
-	perPage := 15
-	page := 1
-
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-
-	if pageNumber != "" {
-		if num, err := strconv.Atoi(pageNumber); err == nil {
-			page = num
-		}
-	}
-
-	offset := (page - 1) * perPage
-
-	var users []models.User
-	var total int64
-
+func GetUsers(filter FilterDetails, params pagination.Params) (*pagination.Page[models.User], error) {
 	db := database.Model(&models.User{})
 
 	if filter.Name != "" {
@@ -160,29 +129,25 @@ func GetUsers(filter FilterDetails, pageNumber string, pageSize string) ([]model
 		db = db.Unscoped().Where("subscriber_id = ?", filter.SubscriberID)
 	}
 
-	if err := db.Unscoped().Count(&total).Error; err != nil {
-		log.Println("Error counting books:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	if err := db.Unscoped().Order("created_at DESC").Limit(perPage).Offset(offset).Find(&users).Error; err != nil {
-		log.Println("Error finding books:", err)
-		return nil, 0, 0, 0, err
+	users, err := pagination.Find[models.User](db.Unscoped(), params)
+	if err != nil {
+		log.Println("Error finding users:", err)
+		return nil, err
 	}
 
-	return users, total, page, perPage, nil
+	return users, nil
 }
 
-func DeleteSingleUser(userID uuid.UUID) error {
-	result := database.Delete(&models.User{}, userID)
+func DeleteSingleUser(ctx context.Context, userID uuid.UUID) error {
+	result := database.WithContext(ctx).Delete(&models.User{}, userID)
 	if result.RowsAffected == 0 {
 		return errors.New("user already deleted")
 	}
 	return result.Error
 }
 
-func UpdateSingleUser(id uuid.UUID, values interface{}) (*models.User, error) {
-	tx := database.Begin()
+func UpdateSingleUser(ctx context.Context, id uuid.UUID, values interface{}) (*models.User, error) {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -209,8 +174,8 @@ func UpdateSingleUser(id uuid.UUID, values interface{}) (*models.User, error) {
 	return existingUser, nil
 }
 
-func CreateAdminUser(user models.User) (*models.User, error) {
-	tx := database.Begin()
+func CreateAdminUser(ctx context.Context, user models.User) (*models.User, error) {
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -222,10 +187,16 @@ func CreateAdminUser(user models.User) (*models.User, error) {
 	result := tx.Preload("Profile").Preload("Companies").Preload("JobApplications").Unscoped().Where("mobile_number = ? OR email = ?", user.MobileNumber, user.Email).First(existingUser)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// the password is hashed on create, the admin gets it emailed in the clear
+			password := user.Password
 			if err := tx.Create(&user).Error; err != nil {
 				tx.Rollback()
 				return nil, fmt.Errorf("error creating user: %w", err)
 			}
+			if err := queueActivation(tx, user, password); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
 
 			if err := tx.Commit().Error; err != nil {
 				tx.Rollback()
@@ -245,24 +216,39 @@ func CreateAdminUser(user models.User) (*models.User, error) {
 	return &user, nil
 }
 
-func GetVerificationToken(token string) (models.User, error) {
-	var user models.User
-	err := database.Where("verification_token = ?", token).Where("expires_at >= ?", time.Now()).First(&user).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return models.User{}, errors.New("invalid or expired token, please request another token")
-		}
-		return models.User{}, err
+// queueActivation registers a new admin with the notification provider and mails them
+// their password
+func queueActivation(tx *gorm.DB, user models.User, password string) error {
+	subscriber := notifications.Subscriber{
+		SubscriberID: user.SubscriberID,
+		Name:         user.Name,
+		Email:        user.Email,
+		Avatar:       user.Picture,
+		Data:         map[string]interface{}{},
+	}
+	if err := notifications.EnqueueSubscriber(tx, subscriber); err != nil {
+		return err
 	}
-	return user, nil
+	return notifications.Enqueue(tx, notifications.Trigger{
+		EventID: "account-activation",
+		To: map[string]interface{}{
+			"subscriberId": user.SubscriberID,
+			"phone":        user.MobileNumber,
+			"email":        user.Email,
+		},
+		Data: map[string]interface{}{
+			"companyName": "Jobby",
+			"password":    password,
+		},
+	})
 }
 
-func Reinstate(user_id string) (*models.User, error) {
+func Reinstate(ctx context.Context, user_id string) (*models.User, error) {
 	userID, err := uuid.Parse(user_id)
 	if err != nil {
 		return nil, err
 	}
-	tx := database.Begin()
+	tx := database.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -279,9 +265,13 @@ func Reinstate(user_id string) (*models.User, error) {
 		tx.Rollback()
 		return nil, errors.New("this account wasn't deleted")
 	}
+	if existingUser.ErasedAt != nil {
+		tx.Rollback()
+		return nil, errors.New("this account was erased and can't be reinstated")
+	}
 
 	// Assuming `user` is the soft-deleted record you want to undelete
-	if err := database.Model(&existingUser).Unscoped().Update("deleted_at", nil).Error; err != nil {
+	if err := tx.Model(&existingUser).Unscoped().Update("deleted_at", nil).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -8,9 +8,9 @@ import (
 
 	"job_board/helpers"
 	"job_board/models"
+	"job_board/pagination"
 )
 
-
 /* work sample segment  starts*/
 
 func CreateWorkSample(ctx *gin.Context) {
@@ -35,10 +35,10 @@ func CreateWorkSample(ctx *gin.Context) {
 	}
 
 	newProject := models.WorkSample{
-		ProfileID:   user.Profile.ID,
-		Attachment:  req.Attachment,
-		Link: req.Link,
-		Description: req.Description,
+		ProfileID:    user.Profile.ID,
+		AttachmentID: req.AttachmentID,
+		Link:         req.Link,
+		Description:  req.Description,
 	}
 
 	resp, err := createWork(newProject, *user)
@@ -48,6 +48,7 @@ func CreateWorkSample(ctx *gin.Context) {
 			StatusCode: http.StatusBadRequest,
 			Data:       nil,
 		})
+		return
 	}
 
 	helpers.CreateResponse(ctx, helpers.Response{
@@ -59,12 +60,20 @@ func CreateWorkSample(ctx *gin.Context) {
 
 func GetWorkSample(ctx *gin.Context) {
 	filter := Search{
-		Attachment:  ctx.Query("attachment"),
-		Link: ctx.Query("link"),
+		Link:        ctx.Query("link"),
 		Description: ctx.Query("description"),
 	}
 
-	resp, total, page, perPage, err := getWork(filter, ctx.Query("page_size"), ctx.Query("page_number"))
+	params, err := pagination.FromContext(ctx, pagination.CreatedSorts)
+	if err != nil {
+		helpers.CreateResponse(ctx, helpers.Response{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+			Data:       nil,
+		})
+		return
+	}
+	resp, err := getWork(filter, params)
 	if err != nil {
 		helpers.CreateResponse(ctx, helpers.Response{
 			Message:    err.Error(),
@@ -76,12 +85,8 @@ func GetWorkSample(ctx *gin.Context) {
 	helpers.CreateResponse(ctx, helpers.Response{
 		Message:    "successfully fetched work samples",
 		StatusCode: http.StatusOK,
-		Data: map[string]interface{}{
-			"data":     resp,
-			"total":    total,
-			"page":     page,
-			"per_page": perPage,
-		},
+		Data:       resp,
+		Links:      resp.Links(ctx),
 	})
 }
 
@@ -208,4 +213,4 @@ func DeleteWorkSample(ctx *gin.Context) {
 	})
 }
 
-/* WorkSample segment ends*/
\ No newline at end of file
+/* WorkSample segment ends*/
@@ -1,16 +1,16 @@
 package work
 
+import (
+	"github.com/google/uuid"
+)
 
 type Request struct {
-	Attachment string `json:"attachment" binding:"required"`
-	Link       string `json:"link" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	AttachmentID *uuid.UUID `json:"attachment_id" binding:"omitempty"`
+	Link         string     `json:"link" binding:"required"`
+	Description  string     `json:"description" binding:"required"`
 }
 
-
-
 type Search struct {
-	Attachment string    `json:"attachment" binding:"omitempty"`
-	Link       string    `json:"link" binding:"omitempty"`
-	Description string    `json:"description" binding:"omitempty"`
-}
\ No newline at end of file
+	Link        string `json:"link" binding:"omitempty"`
+	Description string `json:"description" binding:"omitempty"`
+}
@@ -3,19 +3,21 @@ package work
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-
-	"job_board/db"
+	"job_board/completeness"
+	"job_board/matching"
 	"job_board/models"
+	"job_board/pagination"
+	"job_board/policy"
 )
 
 var database *gorm.DB
 
-func init() {
-	database = db.GetDB()
+// Setup gives the package its database handle
+func Setup(db *gorm.DB) {
+	database = db
 }
 
 func checkProfile(user models.User) bool {
@@ -37,6 +39,13 @@ func createWork(Work models.WorkSample, user models.User) (*models.WorkSample, e
 		return nil, fmt.Errorf("you don't have a profile")
 	}
 
+	if Work.AttachmentID != nil {
+		if err := models.CheckFileOwner(tx, *Work.AttachmentID, user.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	if err := tx.Create(&Work).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("error creating a new Work sample: %w", err)
@@ -47,36 +56,17 @@ func createWork(Work models.WorkSample, user models.User) (*models.WorkSample, e
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(Work.ProfileID)
+	completeness.Queue(Work.ProfileID)
+
 	return &Work, nil
 }
 
-func getWork(filter Search, pageSize string, pageNumber string) ([]models.WorkSample, int64, int, int, error) {
-	// Set default values for page size and page number
-	perPage := 15
-	page := 1
-
-	// Parse page size and page number if provided
-	if pageSize != "" {
-		if perPageNum, err := strconv.Atoi(pageSize); err == nil {
-			perPage = perPageNum
-		}
-	}
-	if pageNumber != "" {
-		if pageNum, err := strconv.Atoi(pageNumber); err == nil {
-			page = pageNum
-		}
-	}
-
-	// Calculate offset
-	offset := (page - 1) * perPage
-
+func getWork(filter Search, params pagination.Params) (*pagination.Page[models.WorkSample], error) {
 	// Initialize database model with filtering conditions
 	db := database.Model(&models.WorkSample{})
 
 	// Add WHERE clauses only if the corresponding filter fields are not empty or zero
-	if filter.Attachment != "" {
-		db = db.Where("attachment LIKE ?", "%"+filter.Attachment+"%")
-	}
 	if filter.Link != "" {
 		db = db.Where("link LIKE ?", "%"+filter.Link+"%")
 	}
@@ -84,25 +74,13 @@ func getWork(filter Search, pageSize string, pageNumber string) ([]models.WorkSa
 		db = db.Where("description = ?", filter.Description)
 	}
 
-	// Count total number of profiles
-	var total int64
-	if err := db.Count(&total).Error; err != nil {
-		log.Println("Error counting work samples:", err)
-		return nil, 0, 0, 0, err
-	}
-
-	// Retrieve profiles with preloaded associations
-	var data []models.WorkSample
-	if err := db.
-		Order("created_at DESC").
-		Limit(perPage).
-		Offset(offset).
-		Find(&data).Error; err != nil {
+	data, err := pagination.Find[models.WorkSample](db, params)
+	if err != nil {
 		log.Println("Error finding Work:", err)
-		return nil, 0, 0, 0, err
+		return nil, err
 	}
 
-	return data, total, page, perPage, nil
+	return data, nil
 }
 
 func getSingleWork(ID uuid.UUID, user models.User) (*models.WorkSample, error) {
@@ -116,7 +94,7 @@ func getSingleWork(ID uuid.UUID, user models.User) (*models.WorkSample, error) {
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole && record.ProfileID != user.Profile.ID {
+	if err := policy.Check(user, "profile:read", policy.OwnProfile(record.ProfileID)); err != nil {
 		return nil, fmt.Errorf("you don't have permission to view this record")
 	}
 
@@ -137,12 +115,21 @@ func updateWork(ID uuid.UUID, user models.User, updates Request) (*models.WorkSa
 	}
 
 	// Check if the user has permission to update the record
-	if user.RoleName == models.UserRole {
-		if profile := checkProfile(user); profile {
-			return nil, fmt.Errorf("you don't have a profile")
+	if err := policy.Check(user, "profile:update", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("you don't have permission to update this record")
+	}
+
+	// the attachment has to be uploaded by whoever owns the profile, not whoever edits it
+	if updates.AttachmentID != nil {
+		var profile models.Profile
+		if err := tx.First(&profile, "id = ?", existingRecord.ProfileID).Error; err != nil {
+			tx.Rollback()
+			return nil, err
 		}
-		if existingRecord.ProfileID != user.Profile.ID {
-			return nil, fmt.Errorf("you don't have permission to update this record")
+		if err := models.CheckFileOwner(tx, *updates.AttachmentID, profile.UserID); err != nil {
+			tx.Rollback()
+			return nil, err
 		}
 	}
 
@@ -155,6 +142,9 @@ func updateWork(ID uuid.UUID, user models.User, updates Request) (*models.WorkSa
 		return nil, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
+
 	return &existingRecord, nil
 }
 
@@ -172,10 +162,9 @@ func deleteSingleWork(ID uuid.UUID, user models.User) error {
 		return err
 	}
 
-	if user.RoleName == models.UserRole {
-		if existingRecord.ProfileID != user.Profile.ID {
-			return fmt.Errorf("you don't have permission to update this record")
-		}
+	if err := policy.Check(user, "profile:delete", policy.OwnProfile(existingRecord.ProfileID)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("you don't have permission to delete this record")
 	}
 
 	result := tx.Delete(&models.WorkSample{}, "id = ?", ID)
@@ -194,5 +183,8 @@ func deleteSingleWork(ID uuid.UUID, user models.User) error {
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
+
+	matching.QueueProfile(existingRecord.ProfileID)
+	completeness.Queue(existingRecord.ProfileID)
 	return nil
-}
\ No newline at end of file
+}